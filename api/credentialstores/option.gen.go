@@ -99,6 +99,54 @@ func WithVaultCredentialStoreAddress(inAddress string) Option {
 	}
 }
 
+func WithVaultCredentialStoreApproleRoleId(inApproleRoleId string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["approle_role_id"] = inApproleRoleId
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultVaultCredentialStoreApproleRoleId() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["approle_role_id"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithVaultCredentialStoreApproleSecretId(inApproleSecretId string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["approle_secret_id"] = inApproleSecretId
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultVaultCredentialStoreApproleSecretId() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["approle_secret_id"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
 func WithAttributes(inAttributes map[string]interface{}) Option {
 	return func(o *options) {
 		o.postMap["attributes"] = inAttributes
@@ -111,6 +159,30 @@ func DefaultAttributes() Option {
 	}
 }
 
+func WithVaultCredentialStoreAuthMethod(inAuthMethod string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["auth_method"] = inAuthMethod
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultVaultCredentialStoreAuthMethod() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["auth_method"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
 func WithVaultCredentialStoreCaCert(inCaCert string) Option {
 	return func(o *options) {
 		raw, ok := o.postMap["attributes"]
@@ -183,6 +255,30 @@ func DefaultVaultCredentialStoreClientCertificateKey() Option {
 	}
 }
 
+func WithVaultCredentialStoreConnectTimeoutSeconds(inConnectTimeoutSeconds uint32) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["connect_timeout_seconds"] = inConnectTimeoutSeconds
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultVaultCredentialStoreConnectTimeoutSeconds() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["connect_timeout_seconds"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
 func WithDescription(inDescription string) Option {
 	return func(o *options) {
 		o.postMap["description"] = inDescription
@@ -231,6 +327,78 @@ func DefaultVaultCredentialStoreNamespace() Option {
 	}
 }
 
+func WithVaultCredentialStoreRequestTimeoutSeconds(inRequestTimeoutSeconds uint32) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["request_timeout_seconds"] = inRequestTimeoutSeconds
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultVaultCredentialStoreRequestTimeoutSeconds() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["request_timeout_seconds"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithVaultCredentialStoreRequestsPerSecond(inRequestsPerSecond uint32) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["requests_per_second"] = inRequestsPerSecond
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultVaultCredentialStoreRequestsPerSecond() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["requests_per_second"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
+func WithVaultCredentialStoreTlsMinVersion(inTlsMinVersion string) Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_min_version"] = inTlsMinVersion
+		o.postMap["attributes"] = val
+	}
+}
+
+func DefaultVaultCredentialStoreTlsMinVersion() Option {
+	return func(o *options) {
+		raw, ok := o.postMap["attributes"]
+		if !ok {
+			raw = interface{}(map[string]interface{}{})
+		}
+		val := raw.(map[string]interface{})
+		val["tls_min_version"] = nil
+		o.postMap["attributes"] = val
+	}
+}
+
 func WithVaultCredentialStoreTlsServerName(inTlsServerName string) Option {
 	return func(o *options) {
 		raw, ok := o.postMap["attributes"]
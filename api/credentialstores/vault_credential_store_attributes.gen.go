@@ -12,4 +12,12 @@ type VaultCredentialStoreAttributes struct {
 	ClientCertificate        string `json:"client_certificate,omitempty"`
 	ClientCertificateKey     string `json:"client_certificate_key,omitempty"`
 	ClientCertificateKeyHmac string `json:"client_certificate_key_hmac,omitempty"`
+	AuthMethod               string `json:"auth_method,omitempty"`
+	ApproleRoleId            string `json:"approle_role_id,omitempty"`
+	ApproleSecretId          string `json:"approle_secret_id,omitempty"`
+	ApproleSecretIdHmac      string `json:"approle_secret_id_hmac,omitempty"`
+	ConnectTimeoutSeconds    uint32 `json:"connect_timeout_seconds,omitempty"`
+	RequestTimeoutSeconds    uint32 `json:"request_timeout_seconds,omitempty"`
+	RequestsPerSecond        uint32 `json:"requests_per_second,omitempty"`
+	TlsMinVersion            string `json:"tls_min_version,omitempty"`
 }
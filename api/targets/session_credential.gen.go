@@ -5,4 +5,5 @@ type SessionCredential struct {
 	CredentialSource  *CredentialSource  `json:"credential_source,omitempty"`
 	CredentialLibrary *CredentialLibrary `json:"credential_library,omitempty"`
 	Secret            *SessionSecret     `json:"secret,omitempty"`
+	CredentialName    string             `json:"credential_name,omitempty"`
 }
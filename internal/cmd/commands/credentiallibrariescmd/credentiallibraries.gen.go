@@ -35,6 +35,8 @@ type Command struct {
 	Func string
 
 	plural string
+
+	extraCmdVars
 }
 
 func (c *Command) AutocompleteArgs() complete.Predictor {
@@ -222,18 +224,7 @@ func (c *Command) Run(args []string) int {
 		return base.CommandSuccess
 
 	case "list":
-		switch base.Format(c.UI) {
-		case "json":
-			if ok := c.PrintJsonItems(listResult); !ok {
-				return base.CommandCliError
-			}
-
-		case "table":
-			listedItems := listResult.GetItems().([]*credentiallibraries.CredentialLibrary)
-			c.UI.Output(c.printListTable(listedItems))
-		}
-
-		return base.CommandSuccess
+		return c.extraListOutputFunc(listResult)
 
 	}
 
@@ -1,7 +1,12 @@
 package credentiallibrariescmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/hashicorp/boundary/api"
@@ -9,6 +14,215 @@ import (
 	"github.com/hashicorp/boundary/internal/cmd/base"
 )
 
+func init() {
+	extraActionsFlagsMapFunc = extraActionsFlagsMapFuncImpl
+	extraFlagsFunc = extraFlagsFuncImpl
+	extraFlagsHandlingFunc = extraFlagsHandlingFuncImpl
+}
+
+const (
+	namePrefixFlagName = "name-prefix"
+	limitFlagName      = "limit"
+	columnsFlagName    = "columns"
+)
+
+type extraCmdVars struct {
+	flagNamePrefix string
+	flagLimit      int
+	flagColumns    string
+
+	columns []listColumn
+}
+
+// A listColumn is a column which can be selected with -columns for the
+// table output of the list command.
+type listColumn struct {
+	name   string
+	header string
+	value  func(*credentiallibraries.CredentialLibrary) string
+}
+
+// listColumns are the columns which can be selected with -columns, in the
+// order they are shown in the usage of the flag.
+var listColumns = []listColumn{
+	{
+		name:   "id",
+		header: "ID",
+		value:  func(l *credentiallibraries.CredentialLibrary) string { return l.Id },
+	},
+	{
+		name:   "name",
+		header: "Name",
+		value:  func(l *credentiallibraries.CredentialLibrary) string { return l.Name },
+	},
+	{
+		name:   "method",
+		header: "HTTP Method",
+		value:  func(l *credentiallibraries.CredentialLibrary) string { return attributeString(l, "http_method") },
+	},
+	{
+		name:   "path",
+		header: "Path",
+		value:  func(l *credentiallibraries.CredentialLibrary) string { return attributeString(l, "path") },
+	},
+	{
+		name:   "updated",
+		header: "Updated Time",
+		value: func(l *credentiallibraries.CredentialLibrary) string {
+			if l.UpdatedTime.IsZero() {
+				return ""
+			}
+			return l.UpdatedTime.Local().Format(time.RFC1123)
+		},
+	},
+}
+
+const defaultListColumns = "id,name,method"
+
+func attributeString(l *credentiallibraries.CredentialLibrary, key string) string {
+	v, ok := l.Attributes[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// parseListColumns returns the columns named in the comma separated list
+// s. An error is returned if s contains an unknown column name.
+func parseListColumns(s string) ([]listColumn, error) {
+	var columns []listColumn
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		var found bool
+		for _, c := range listColumns {
+			if c.name == name {
+				columns = append(columns, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Invalid column %q passed in via -%s; valid columns are: %s", name, columnsFlagName, listColumnNames())
+		}
+	}
+	return columns, nil
+}
+
+// listColumnNames returns the names of the columns which can be selected
+// with -columns as a comma separated string.
+func listColumnNames() string {
+	names := make([]string, 0, len(listColumns))
+	for _, c := range listColumns {
+		names = append(names, c.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func extraActionsFlagsMapFuncImpl() map[string][]string {
+	return map[string][]string{
+		"list": {namePrefixFlagName, limitFlagName, columnsFlagName},
+	}
+}
+
+func extraFlagsFuncImpl(c *Command, _ *base.FlagSets, f *base.FlagSet) {
+	for _, name := range flagsMap[c.Func] {
+		switch name {
+		case namePrefixFlagName:
+			f.StringVar(&base.StringVar{
+				Name:   namePrefixFlagName,
+				Target: &c.flagNamePrefix,
+				Usage:  "If set, only credential libraries with a name starting with this prefix are listed. It is combined with -filter if both are set.",
+			})
+		case limitFlagName:
+			f.IntVar(&base.IntVar{
+				Name:   limitFlagName,
+				Target: &c.flagLimit,
+				Usage:  "If set, at most this many credential libraries are listed.",
+			})
+		case columnsFlagName:
+			f.StringVar(&base.StringVar{
+				Name:    columnsFlagName,
+				Target:  &c.flagColumns,
+				Default: defaultListColumns,
+				Usage:   fmt.Sprintf("A comma-separated list of the columns printed for each credential library in table format. Valid columns are: %s.", listColumnNames()),
+			})
+		}
+	}
+}
+
+func extraFlagsHandlingFuncImpl(c *Command, _ *base.FlagSets, opts *[]credentiallibraries.Option) bool {
+	if c.Func != "list" {
+		return true
+	}
+	if c.flagLimit < 0 {
+		c.PrintCliError(errors.New("Limit must not be negative"))
+		return false
+	}
+	columns, err := parseListColumns(c.flagColumns)
+	if err != nil {
+		c.PrintCliError(err)
+		return false
+	}
+	c.columns = columns
+	if c.flagNamePrefix != "" {
+		*opts = append(*opts, credentiallibraries.WithFilter(listFilter(c.FlagFilter, c.flagNamePrefix)))
+	}
+	return true
+}
+
+// listFilter returns a filter which matches the items matched by filter
+// with a name starting with namePrefix. If filter is empty, the returned
+// filter only matches on the name prefix.
+func listFilter(filter, namePrefix string) string {
+	// The prefix is matched as a literal string. Backticks cannot be
+	// escaped in a raw string in a filter, so they are written as a hex
+	// escape in the regular expression.
+	re := "^" + strings.ReplaceAll(regexp.QuoteMeta(namePrefix), "`", `\x60`)
+	nameFilter := fmt.Sprintf("\"/item/name\" matches `%s`", re)
+	if filter == "" {
+		return nameFilter
+	}
+	return fmt.Sprintf("(%s) and (%s)", filter, nameFilter)
+}
+
+// extraListOutputFunc prints the result of a list action. If -limit is
+// set, only the first items up to the limit are printed.
+func (c *Command) extraListOutputFunc(listResult api.GenericListResult) int {
+	items := listResult.GetItems().([]*credentiallibraries.CredentialLibrary)
+	limited := c.flagLimit > 0 && len(items) > c.flagLimit
+	if limited {
+		items = items[:c.flagLimit]
+	}
+
+	switch base.Format(c.UI) {
+	case "json":
+		if !limited {
+			if ok := c.PrintJsonItems(listResult); !ok {
+				return base.CommandCliError
+			}
+			return base.CommandSuccess
+		}
+		output := struct {
+			StatusCode int                                      `json:"status_code"`
+			Items      []*credentiallibraries.CredentialLibrary `json:"items"`
+		}{
+			StatusCode: listResult.GetResponse().StatusCode(),
+			Items:      items,
+		}
+		b, err := base.JsonFormatter{}.Format(output)
+		if err != nil {
+			c.PrintCliError(fmt.Errorf("Error formatting as JSON: %w", err))
+			return base.CommandCliError
+		}
+		c.UI.Output(string(b))
+
+	case "table":
+		c.UI.Output(c.printListTable(items))
+	}
+
+	return base.CommandSuccess
+}
+
 func (c *Command) extraHelpFunc(helpMap map[string]func() string) string {
 	var helpStr string
 	switch c.Func {
@@ -59,53 +273,27 @@ func (c *Command) printListTable(items []*credentiallibraries.CredentialLibrary)
 		return "No credential library found"
 	}
 
-	var output []string
-	output = []string{
-		"",
-		"Credential Library information:",
+	columns := c.columns
+	if len(columns) == 0 {
+		columns, _ = parseListColumns(defaultListColumns)
 	}
-	for i, m := range items {
-		if i > 0 {
-			output = append(output, "")
-		}
-		if m.Id != "" {
-			output = append(output,
-				fmt.Sprintf("  ID:                    %s", m.Id),
-			)
-		} else {
-			output = append(output,
-				fmt.Sprintf("  ID:                    %s", "(not available)"),
-			)
-		}
-		if m.Version > 0 {
-			output = append(output,
-				fmt.Sprintf("    Version:             %d", m.Version),
-			)
-		}
-		if m.Type != "" {
-			output = append(output,
-				fmt.Sprintf("    Type:                %s", m.Type),
-			)
-		}
-		if m.Name != "" {
-			output = append(output,
-				fmt.Sprintf("    Name:                %s", m.Name),
-			)
-		}
-		if m.Description != "" {
-			output = append(output,
-				fmt.Sprintf("    Description:         %s", m.Description),
-			)
-		}
-		if len(m.AuthorizedActions) > 0 {
-			output = append(output,
-				"    Authorized Actions:",
-				base.WrapSlice(6, m.AuthorizedActions),
-			)
+
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 0, 2, 4, ' ', 0)
+	row := make([]string, len(columns))
+	for i, col := range columns {
+		row[i] = col.header
+	}
+	fmt.Fprintln(tw, strings.Join(row, "\t"))
+	for _, item := range items {
+		for i, col := range columns {
+			row[i] = col.value(item)
 		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
 	}
+	tw.Flush()
 
-	return base.WrapForHelpText(output)
+	return strings.TrimSuffix(b.String(), "\n")
 }
 
 func printItemTable(result api.GenericResult) string {
@@ -0,0 +1,149 @@
+package credentiallibrariescmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/boundary/api/credentiallibraries"
+	"github.com/hashicorp/go-bexpr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_listFilter(t *testing.T) {
+	type item struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	type filterItem struct {
+		Item item `json:"item"`
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		namePrefix string
+		match      []item
+		noMatch    []item
+	}{
+		{
+			name:       "prefix-only",
+			namePrefix: "foo",
+			match:      []item{{Name: "foo"}, {Name: "foobar"}},
+			noMatch:    []item{{Name: "barfoo"}, {Name: "fo"}, {}},
+		},
+		{
+			name:       "prefix-is-literal",
+			namePrefix: "a.b*`c\"d",
+			match:      []item{{Name: "a.b*`c\"d"}, {Name: "a.b*`c\"de"}},
+			noMatch:    []item{{Name: "axb*`c\"d"}, {Name: "a.bbb`c\"d"}},
+		},
+		{
+			name:       "combined-with-filter",
+			filter:     `"/item/description" == "prod"`,
+			namePrefix: "foo",
+			match:      []item{{Name: "foobar", Description: "prod"}},
+			noMatch:    []item{{Name: "foobar", Description: "dev"}, {Name: "bar", Description: "prod"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			e, err := bexpr.CreateEvaluator(listFilter(tt.filter, tt.namePrefix), bexpr.WithTagName("json"))
+			require.NoError(err)
+			for _, i := range tt.match {
+				got, err := e.Evaluate(filterItem{Item: i})
+				require.NoError(err)
+				assert.Truef(got, "want match: %+v", i)
+			}
+			for _, i := range tt.noMatch {
+				got, err := e.Evaluate(filterItem{Item: i})
+				require.NoError(err)
+				assert.Falsef(got, "want no match: %+v", i)
+			}
+		})
+	}
+}
+
+func Test_parseListColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		want      []string
+		wantErrIs string
+	}{
+		{
+			name: "default",
+			in:   defaultListColumns,
+			want: []string{"id", "name", "method"},
+		},
+		{
+			name: "all-with-spaces-and-case",
+			in:   "ID, name,Method ,path,updated",
+			want: []string{"id", "name", "method", "path", "updated"},
+		},
+		{
+			name:      "unknown-column",
+			in:        "id,bogus",
+			wantErrIs: `Invalid column "bogus" passed in via -columns; valid columns are: id, name, method, path, updated`,
+		},
+		{
+			name:      "empty-column",
+			in:        "id,,name",
+			wantErrIs: `Invalid column "" passed in via -columns`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := parseListColumns(tt.in)
+			if tt.wantErrIs != "" {
+				require.Error(err)
+				assert.Contains(err.Error(), tt.wantErrIs)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			var names []string
+			for _, c := range got {
+				names = append(names, c.name)
+			}
+			assert.Equal(tt.want, names)
+		})
+	}
+}
+
+func TestCommand_printListTable(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	items := []*credentiallibraries.CredentialLibrary{
+		{
+			Id:         "clvlt_1234567890",
+			Name:       "database",
+			Attributes: map[string]interface{}{"http_method": "GET", "path": "database/creds/opened"},
+		},
+		{
+			Id:         "clvlt_0987654321",
+			Attributes: map[string]interface{}{"http_method": "POST", "path": "pki/issue/boundary"},
+		},
+	}
+
+	c := &Command{}
+	got := strings.Split(c.printListTable(items), "\n")
+	require.Len(got, 3)
+	assert.Equal([]string{"ID", "Name", "HTTP", "Method"}, strings.Fields(got[0]))
+	assert.Equal([]string{"clvlt_1234567890", "database", "GET"}, strings.Fields(got[1]))
+	assert.Equal([]string{"clvlt_0987654321", "POST"}, strings.Fields(got[2]))
+
+	columns, err := parseListColumns("path,id")
+	require.NoError(err)
+	c.columns = columns
+	got = strings.Split(c.printListTable(items), "\n")
+	require.Len(got, 3)
+	assert.Equal([]string{"Path", "ID"}, strings.Fields(got[0]))
+	assert.Equal([]string{"database/creds/opened", "clvlt_1234567890"}, strings.Fields(got[1]))
+	assert.Equal([]string{"pki/issue/boundary", "clvlt_0987654321"}, strings.Fields(got[2]))
+
+	assert.Equal("No credential library found", c.printListTable(nil))
+}
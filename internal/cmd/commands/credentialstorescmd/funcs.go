@@ -197,7 +197,14 @@ var keySubstMap = map[string]string{
 	"ca_cert":                     "CA Cert",
 	"tls_server_name":             "TLS Server Name",
 	"tls_skip_verify":             "Skip TLS Verification",
+	"tls_min_version":             "TLS Min Version",
 	"token_hmac":                  "Token HMAC",
 	"client_certificate":          "Client Certificate",
 	"client_certificate_key_hmac": "Client Certificate Key HMAC",
+	"auth_method":                 "Auth Method",
+	"approle_role_id":             "AppRole RoleID",
+	"approle_secret_id_hmac":      "AppRole SecretID HMAC",
+	"connect_timeout_seconds":     "Connect Timeout Seconds",
+	"request_timeout_seconds":     "Request Timeout Seconds",
+	"requests_per_second":         "Requests Per Second",
 }
@@ -1,15 +1,26 @@
 package credentialstorescmd
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/credentialstores"
 	"github.com/hashicorp/boundary/internal/cmd/base"
 	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/posener/complete"
 )
 
 func init() {
 	extraVaultFlagsFunc = extraVaultFlagsFuncImpl
 	extraVaultActionsFlagsMapFunc = extraVaultActionsFlagsMapFuncImpl
 	extraVaultFlagsHandlingFunc = extraVaultFlagHandlingFuncImpl
+	executeExtraVaultActions = executeExtraVaultActionsImpl
 }
 
 const (
@@ -18,9 +29,21 @@ const (
 	vaultCaCertFlagName          = "vault-ca-cert"
 	tlsServerNameFlagName        = "vault-tls-server-name"
 	tlsSkipVerifyFlagName        = "vault-tls-skip-verify"
+	tlsMinVersionFlagName        = "vault-tls-min-version"
 	vaultTokenFlagName           = "vault-token"
 	clientCertificateFlagName    = "vault-client-certificate"
 	clientCertificateKeyFlagName = "vault-client-certificate-key"
+	authMethodFlagName           = "vault-auth-method"
+	appRoleRoleIdFlagName        = "vault-approle-role-id"
+	appRoleSecretIdFlagName      = "vault-approle-secret-id"
+	connectTimeoutFlagName       = "vault-connect-timeout-seconds"
+	requestTimeoutFlagName       = "vault-request-timeout-seconds"
+	requestsPerSecondFlagName    = "vault-requests-per-second"
+)
+
+const (
+	tokenAuthMethod   = "token"
+	appRoleAuthMethod = "approle"
 )
 
 type extraVaultCmdVars struct {
@@ -32,6 +55,14 @@ type extraVaultCmdVars struct {
 	flagClientCertKey string
 	flagTlsServerName string
 	flagTlsSkipVerify bool
+	flagTlsMinVersion string
+	flagAuthMethod    string
+	flagAppRoleId     string
+	flagAppRoleSecret string
+
+	flagConnectTimeout string
+	flagRequestTimeout string
+	flagRequestsPerSec string
 }
 
 func extraVaultActionsFlagsMapFuncImpl() map[string][]string {
@@ -42,12 +73,34 @@ func extraVaultActionsFlagsMapFuncImpl() map[string][]string {
 			vaultCaCertFlagName,
 			tlsServerNameFlagName,
 			tlsSkipVerifyFlagName,
+			tlsMinVersionFlagName,
 			vaultTokenFlagName,
 			clientCertificateFlagName,
 			clientCertificateKeyFlagName,
+			authMethodFlagName,
+			appRoleRoleIdFlagName,
+			appRoleSecretIdFlagName,
+			connectTimeoutFlagName,
+			requestTimeoutFlagName,
+			requestsPerSecondFlagName,
+		},
+		"update": {
+			addressFlagName,
+			namespaceFlagName,
+			vaultCaCertFlagName,
+			tlsServerNameFlagName,
+			tlsSkipVerifyFlagName,
+			tlsMinVersionFlagName,
+			vaultTokenFlagName,
+			clientCertificateFlagName,
+			clientCertificateKeyFlagName,
+			appRoleRoleIdFlagName,
+			appRoleSecretIdFlagName,
+			connectTimeoutFlagName,
+			requestTimeoutFlagName,
+			requestsPerSecondFlagName,
 		},
 	}
-	flags["update"] = flags["create"]
 	return flags
 }
 
@@ -86,6 +139,12 @@ func extraVaultFlagsFuncImpl(c *VaultCommand, set *base.FlagSets, _ *base.FlagSe
 				Target: &c.flagTlsSkipVerify,
 				Usage:  "Whether to skip tls verification.",
 			})
+		case tlsMinVersionFlagName:
+			f.StringVar(&base.StringVar{
+				Name:   tlsMinVersionFlagName,
+				Target: &c.flagTlsMinVersion,
+				Usage:  `The minimum TLS version to use when connecting to vault. Either "tls12" or "tls13". Defaults to "tls12".`,
+			})
 		case vaultTokenFlagName:
 			f.StringVar(&base.StringVar{
 				Name:   vaultTokenFlagName,
@@ -104,6 +163,43 @@ func extraVaultFlagsFuncImpl(c *VaultCommand, set *base.FlagSets, _ *base.FlagSe
 				Target: &c.flagClientCertKey,
 				Usage:  `The client certificate's private key to use when boundary connects to vault for this store. This can be the value itself, refer to a file on disk (file://) from which the value will be read, or an env var (env://) from which the value will be read.`,
 			})
+		case authMethodFlagName:
+			f.StringVar(&base.StringVar{
+				Name:       authMethodFlagName,
+				Target:     &c.flagAuthMethod,
+				Completion: complete.PredictSet(tokenAuthMethod, appRoleAuthMethod),
+				Usage:      `The method boundary uses to obtain a vault token for this store. Either "token" or "approle". Defaults to "token".`,
+			})
+		case appRoleRoleIdFlagName:
+			f.StringVar(&base.StringVar{
+				Name:   appRoleRoleIdFlagName,
+				Target: &c.flagAppRoleId,
+				Usage:  "The RoleID of the vault AppRole boundary logs in with to obtain a vault token for this store. Required when the auth method is approle.",
+			})
+		case appRoleSecretIdFlagName:
+			f.StringVar(&base.StringVar{
+				Name:   appRoleSecretIdFlagName,
+				Target: &c.flagAppRoleSecret,
+				Usage:  "The SecretID of the vault AppRole boundary logs in with to obtain a vault token for this store. Required when the auth method is approle. This can be the value itself, refer to a file on disk (file://) from which the value will be read, or an env var (env://) from which the value will be read.",
+			})
+		case connectTimeoutFlagName:
+			f.StringVar(&base.StringVar{
+				Name:   connectTimeoutFlagName,
+				Target: &c.flagConnectTimeout,
+				Usage:  "The maximum time to wait for a connection to vault to be established. Can be specified as an integer number of seconds or a duration string.",
+			})
+		case requestTimeoutFlagName:
+			f.StringVar(&base.StringVar{
+				Name:   requestTimeoutFlagName,
+				Target: &c.flagRequestTimeout,
+				Usage:  "The maximum time to wait for a request to vault to complete. Can be specified as an integer number of seconds or a duration string.",
+			})
+		case requestsPerSecondFlagName:
+			f.StringVar(&base.StringVar{
+				Name:   requestsPerSecondFlagName,
+				Target: &c.flagRequestsPerSec,
+				Usage:  "The maximum number of requests per second boundary sends to vault for this store. Requests over the limit are queued for a short time before failing.",
+			})
 		}
 	}
 }
@@ -132,6 +228,14 @@ func extraVaultFlagHandlingFuncImpl(c *VaultCommand, f *base.FlagSets, opts *[]c
 		*opts = append(*opts, credentialstores.DefaultVaultCredentialStoreCaCert())
 	default:
 		cer, _ := parseutil.ParsePath(c.flagCaCert)
+		warnings, err := validateCaCertChain(cer, time.Now())
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Invalid value for -%s: %s", vaultCaCertFlagName, err.Error()))
+			return false
+		}
+		for _, w := range warnings {
+			c.UI.Warn(w)
+		}
 		*opts = append(*opts, credentialstores.WithVaultCredentialStoreCaCert(cer))
 	}
 	switch c.flagClientCert {
@@ -153,10 +257,206 @@ func extraVaultFlagHandlingFuncImpl(c *VaultCommand, f *base.FlagSets, opts *[]c
 	if c.flagTlsSkipVerify {
 		*opts = append(*opts, credentialstores.WithVaultCredentialStoreTlsSkipVerify(c.flagTlsSkipVerify))
 	}
+	switch c.flagTlsMinVersion {
+	case "":
+	case "null":
+		*opts = append(*opts, credentialstores.DefaultVaultCredentialStoreTlsMinVersion())
+	default:
+		version, err := parseTlsMinVersion(c.flagTlsMinVersion)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Invalid value for -%s: %s", tlsMinVersionFlagName, err.Error()))
+			return false
+		}
+		*opts = append(*opts, credentialstores.WithVaultCredentialStoreTlsMinVersion(version))
+	}
+
+	if err := validateAppRoleFlags(c.Func, c.flagAuthMethod, c.flagAppRoleId, c.flagAppRoleSecret); err != nil {
+		c.UI.Error(err.Error())
+		return false
+	}
+	switch c.flagAuthMethod {
+	case "":
+	default:
+		*opts = append(*opts, credentialstores.WithVaultCredentialStoreAuthMethod(c.flagAuthMethod))
+	}
+	switch c.flagAppRoleId {
+	case "":
+	case "null":
+		*opts = append(*opts, credentialstores.DefaultVaultCredentialStoreApproleRoleId())
+	default:
+		*opts = append(*opts, credentialstores.WithVaultCredentialStoreApproleRoleId(c.flagAppRoleId))
+	}
+	switch c.flagAppRoleSecret {
+	case "":
+	case "null":
+		*opts = append(*opts, credentialstores.DefaultVaultCredentialStoreApproleSecretId())
+	default:
+		secretId, err := parseutil.ParsePath(c.flagAppRoleSecret)
+		if err != nil && !errors.Is(err, parseutil.ErrNotAUrl) {
+			c.UI.Error(fmt.Sprintf("Error parsing -%s: %s", appRoleSecretIdFlagName, err.Error()))
+			return false
+		}
+		*opts = append(*opts, credentialstores.WithVaultCredentialStoreApproleSecretId(secretId))
+	}
+
+	switch c.flagConnectTimeout {
+	case "":
+	case "null":
+		*opts = append(*opts, credentialstores.DefaultVaultCredentialStoreConnectTimeoutSeconds())
+	default:
+		secs, err := parseTimeoutSeconds(c.flagConnectTimeout)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Invalid value for -%s: %s", connectTimeoutFlagName, err.Error()))
+			return false
+		}
+		*opts = append(*opts, credentialstores.WithVaultCredentialStoreConnectTimeoutSeconds(secs))
+	}
+	switch c.flagRequestTimeout {
+	case "":
+	case "null":
+		*opts = append(*opts, credentialstores.DefaultVaultCredentialStoreRequestTimeoutSeconds())
+	default:
+		secs, err := parseTimeoutSeconds(c.flagRequestTimeout)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Invalid value for -%s: %s", requestTimeoutFlagName, err.Error()))
+			return false
+		}
+		*opts = append(*opts, credentialstores.WithVaultCredentialStoreRequestTimeoutSeconds(secs))
+	}
+	switch c.flagRequestsPerSec {
+	case "":
+	case "null":
+		*opts = append(*opts, credentialstores.DefaultVaultCredentialStoreRequestsPerSecond())
+	default:
+		rps, err := parseRequestsPerSecond(c.flagRequestsPerSec)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Invalid value for -%s: %s", requestsPerSecondFlagName, err.Error()))
+			return false
+		}
+		*opts = append(*opts, credentialstores.WithVaultCredentialStoreRequestsPerSecond(rps))
+	}
 
 	return true
 }
 
+func executeExtraVaultActionsImpl(c *VaultCommand, inResult api.GenericResult, inErr error, _ *credentialstores.Client, _ uint32, _ []credentialstores.Option) (api.GenericResult, error) {
+	if inErr != nil || inResult == nil {
+		return inResult, inErr
+	}
+	item, ok := inResult.GetItem().(*credentialstores.CredentialStore)
+	if !ok || item == nil {
+		return inResult, inErr
+	}
+	for _, w := range item.Warnings {
+		c.UI.Warn(fmt.Sprintf("Warning: %s", w))
+	}
+	return inResult, inErr
+}
+
+// validateAppRoleFlags checks the auth method and AppRole flags given to
+// the create or update command fn. The RoleID and SecretID are required
+// when creating a store with the approle auth method and are only allowed
+// with that method. When updating a store, the RoleID and SecretID must be
+// set, or unset with "null", together.
+func validateAppRoleFlags(fn, authMethod, roleId, secretId string) error {
+	switch fn {
+	case "create":
+		switch authMethod {
+		case "", tokenAuthMethod:
+			if roleId != "" || secretId != "" {
+				return fmt.Errorf("-%s and -%s can only be used with -%s %s", appRoleRoleIdFlagName, appRoleSecretIdFlagName, authMethodFlagName, appRoleAuthMethod)
+			}
+		case appRoleAuthMethod:
+			if roleId == "" || secretId == "" {
+				return fmt.Errorf("-%s and -%s are required with -%s %s", appRoleRoleIdFlagName, appRoleSecretIdFlagName, authMethodFlagName, appRoleAuthMethod)
+			}
+		default:
+			return fmt.Errorf("invalid value for -%s: %q, must be %q or %q", authMethodFlagName, authMethod, tokenAuthMethod, appRoleAuthMethod)
+		}
+	case "update":
+		if (roleId == "") != (secretId == "") || (roleId == "null") != (secretId == "null") {
+			return fmt.Errorf("-%s and -%s must be updated together", appRoleRoleIdFlagName, appRoleSecretIdFlagName)
+		}
+	}
+	return nil
+}
+
+// parseTimeoutSeconds parses s as an integer number of seconds or as a
+// duration string and returns the number of whole seconds. An error is
+// returned if s cannot be parsed or is not at least one second.
+func parseTimeoutSeconds(s string) (uint32, error) {
+	secs, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		dur, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse %q: %w", s, err)
+		}
+		if dur < 0 || dur.Seconds() > math.MaxUint32 {
+			return 0, fmt.Errorf("timeout out of range: %q", s)
+		}
+		secs = uint64(dur / time.Second)
+	}
+	if secs == 0 {
+		return 0, fmt.Errorf("timeout must be at least one second: %q", s)
+	}
+	return uint32(secs), nil
+}
+
+// parseRequestsPerSecond parses s as a positive integer number of requests
+// per second.
+func parseRequestsPerSecond(s string) (uint32, error) {
+	rps, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("must be a positive integer: %q", s)
+	}
+	if rps == 0 {
+		return 0, fmt.Errorf("must be at least one request per second: %q", s)
+	}
+	return uint32(rps), nil
+}
+
+// parseTlsMinVersion returns s if it is a TLS version supported as the
+// minimum TLS version of a vault credential store.
+func parseTlsMinVersion(s string) (string, error) {
+	switch s {
+	case "tls12", "tls13":
+		return s, nil
+	}
+	return "", fmt.Errorf(`must be either "tls12" or "tls13": %q`, s)
+}
+
+// validateCaCertChain verifies that caCert is a PEM encoded chain containing
+// at least one valid x509 certificate. An error is returned if the PEM
+// cannot be decoded or any certificate in it cannot be parsed. A warning is
+// returned for each certificate in the chain that is expired at now.
+func validateCaCertChain(caCert string, now time.Time) ([]string, error) {
+	var warnings []string
+	var count int
+	rest := []byte(caCert)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse certificate: %w", err)
+		}
+		count++
+		if now.After(cert.NotAfter) {
+			warnings = append(warnings, fmt.Sprintf("Vault CA certificate %q expired at %s", cert.Subject.String(), cert.NotAfter.Format(time.RFC3339)))
+		}
+	}
+	if count == 0 {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+	return warnings, nil
+}
+
 func (c *VaultCommand) extraVaultHelpFunc(helpMap map[string]func() string) string {
 	var helpStr string
 	switch c.Func {
@@ -168,6 +468,10 @@ func (c *VaultCommand) extraVaultHelpFunc(helpMap map[string]func() string) stri
 			"",
 			`    $ boundary credential-stores create vault -vault-address "http://localhost:8200" -vault-token "s.s0m3t0k3n"`,
 			"",
+			"  Create a vault-type credential store which logs in to vault with an AppRole. Example:",
+			"",
+			`    $ boundary credential-stores create vault -vault-address "http://localhost:8200" -vault-auth-method approle -vault-approle-role-id "r0l3-1d" -vault-approle-secret-id "env://VAULT_SECRET_ID"`,
+			"",
 			"",
 		})
 
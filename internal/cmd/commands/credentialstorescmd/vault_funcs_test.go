@@ -0,0 +1,281 @@
+package credentialstorescmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCaCertPem(t *testing.T, cn string, notBefore, notAfter time.Time) string {
+	t.Helper()
+	require := require.New(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func Test_validateCaCertChain(t *testing.T) {
+	now := time.Now()
+	valid := testCaCertPem(t, "valid", now.Add(-time.Hour), now.Add(time.Hour))
+	intermediate := testCaCertPem(t, "intermediate", now.Add(-time.Hour), now.Add(24*time.Hour))
+	expired := testCaCertPem(t, "expired", now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+	tests := []struct {
+		name         string
+		caCert       string
+		wantWarnings int
+		wantErr      bool
+	}{
+		{
+			name:   "valid-cert",
+			caCert: valid,
+		},
+		{
+			name:   "valid-chain",
+			caCert: valid + intermediate,
+		},
+		{
+			name:         "expired-cert",
+			caCert:       valid + expired,
+			wantWarnings: 1,
+		},
+		{
+			name:    "garbage",
+			caCert:  "this is not a certificate",
+			wantErr: true,
+		},
+		{
+			name:    "not-a-certificate-block",
+			caCert:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})),
+			wantErr: true,
+		},
+		{
+			name:    "undecodable-certificate",
+			caCert:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			warnings, err := validateCaCertChain(tt.caCert, now)
+			if tt.wantErr {
+				assert.Error(err)
+				assert.Empty(warnings)
+				return
+			}
+			assert.NoError(err)
+			assert.Len(warnings, tt.wantWarnings)
+			if tt.wantWarnings > 0 {
+				assert.Contains(warnings[0], "expired")
+			}
+		})
+	}
+}
+
+func Test_validateAppRoleFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         string
+		authMethod string
+		roleId     string
+		secretId   string
+		wantErr    bool
+	}{
+		{
+			name: "create-default-auth-method",
+			fn:   "create",
+		},
+		{
+			name:       "create-token",
+			fn:         "create",
+			authMethod: "token",
+		},
+		{
+			name:       "create-token-with-approle",
+			fn:         "create",
+			authMethod: "token",
+			roleId:     "role-id",
+			secretId:   "secret-id",
+			wantErr:    true,
+		},
+		{
+			name:     "create-default-with-approle",
+			fn:       "create",
+			roleId:   "role-id",
+			secretId: "secret-id",
+			wantErr:  true,
+		},
+		{
+			name:       "create-approle",
+			fn:         "create",
+			authMethod: "approle",
+			roleId:     "role-id",
+			secretId:   "secret-id",
+		},
+		{
+			name:       "create-approle-missing-secret-id",
+			fn:         "create",
+			authMethod: "approle",
+			roleId:     "role-id",
+			wantErr:    true,
+		},
+		{
+			name:       "create-approle-missing-role-id",
+			fn:         "create",
+			authMethod: "approle",
+			secretId:   "secret-id",
+			wantErr:    true,
+		},
+		{
+			name:       "create-unknown-auth-method",
+			fn:         "create",
+			authMethod: "userpass",
+			wantErr:    true,
+		},
+		{
+			name: "update-no-approle",
+			fn:   "update",
+		},
+		{
+			name:     "update-approle",
+			fn:       "update",
+			roleId:   "role-id",
+			secretId: "secret-id",
+		},
+		{
+			name:     "update-unset-approle",
+			fn:       "update",
+			roleId:   "null",
+			secretId: "null",
+		},
+		{
+			name:    "update-only-role-id",
+			fn:      "update",
+			roleId:  "role-id",
+			wantErr: true,
+		},
+		{
+			name:     "update-unset-only-secret-id",
+			fn:       "update",
+			roleId:   "role-id",
+			secretId: "null",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAppRoleFlags(tt.fn, tt.authMethod, tt.roleId, tt.secretId)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_parseTimeoutSeconds(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint32
+		wantErr bool
+	}{
+		{in: "30", want: 30},
+		{in: "90s", want: 90},
+		{in: "2m", want: 120},
+		{in: "1500ms", want: 1},
+		{in: "0", wantErr: true},
+		{in: "500ms", wantErr: true},
+		{in: "-5s", wantErr: true},
+		{in: "-5", wantErr: true},
+		{in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseTimeoutSeconds(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseRequestsPerSecond(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint32
+		wantErr bool
+	}{
+		{in: "1", want: 1},
+		{in: "250", want: 250},
+		{in: "0", wantErr: true},
+		{in: "-5", wantErr: true},
+		{in: "1.5", wantErr: true},
+		{in: "4294967296", wantErr: true},
+		{in: "fast", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseRequestsPerSecond(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseTlsMinVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "tls12", want: "tls12"},
+		{in: "tls13", want: "tls13"},
+		{in: "tls10", wantErr: true},
+		{in: "tls11", wantErr: true},
+		{in: "1.2", wantErr: true},
+		{in: "TLS13", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseTlsMinVersion(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
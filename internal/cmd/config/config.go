@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -122,6 +123,23 @@ type Controller struct {
 	AuthTokenTimeToStale         interface{} `hcl:"auth_token_time_to_stale"`
 	AuthTokenTimeToStaleDuration time.Duration
 
+	// VaultIssueRequestsPerSecond limits the rate of requests to Vault,
+	// including credential issue requests, for each Vault credential store
+	// which does not set its own requests per second. Those requests are
+	// not rate limited if it's zero.
+	VaultIssueRequestsPerSecond float64 `hcl:"vault_issue_requests_per_second"`
+
+	// VaultIssueBurst is the maximum burst of requests to Vault for each
+	// Vault credential store limited by VaultIssueRequestsPerSecond. A
+	// burst of one request is used if it's zero.
+	VaultIssueBurst int `hcl:"vault_issue_burst"`
+
+	// VaultTokenFileDirectory is the directory Vault credential stores can
+	// read their token files from. Token files must be in the directory or
+	// one of its subdirectories. Credential stores cannot read their token
+	// from a file if it's empty.
+	VaultTokenFileDirectory string `hcl:"vault_token_file_directory"`
+
 	// StatusGracePeriod represents the period of time (as a duration) that the
 	// controller will wait before marking connections from a disconnected worker
 	// as invalid.
@@ -310,6 +328,16 @@ func Parse(d string) (*Config, error) {
 			}
 			result.Controller.AuthTokenTimeToStaleDuration = t
 		}
+
+		if result.Controller.VaultIssueRequestsPerSecond < 0 {
+			return nil, errors.New("Controller vault_issue_requests_per_second must not be negative")
+		}
+		if result.Controller.VaultIssueBurst < 0 {
+			return nil, errors.New("Controller vault_issue_burst must not be negative")
+		}
+		if result.Controller.VaultTokenFileDirectory != "" && !filepath.IsAbs(result.Controller.VaultTokenFileDirectory) {
+			return nil, errors.New("Controller vault_token_file_directory must be an absolute path")
+		}
 	}
 
 	// Parse worker tags
@@ -482,6 +510,14 @@ func parseEventing(eventObj *ast.ObjectItem) (*event.EventerConfig, error) {
 			}
 		}
 
+		if s.BufferFlushIntervalHCL != "" {
+			var err error
+			s.BufferFlushInterval, err = parseutil.ParseDurationSecond(s.BufferFlushIntervalHCL)
+			if err != nil {
+				return nil, fmt.Errorf("can't parse buffer flush interval %s", s.BufferFlushIntervalHCL)
+			}
+		}
+
 		if err := s.Validate(); err != nil {
 			return nil, err
 		}
@@ -511,3 +511,114 @@ func TestController_EventingConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestController_VaultIssueRateLimit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                  string
+		config                string
+		wantRequestsPerSecond float64
+		wantBurst             int
+		wantErr               string
+	}{
+		{
+			name: "default",
+			config: `
+			controller {
+				name = "example-controller"
+			}`,
+		},
+		{
+			name: "configured",
+			config: `
+			controller {
+				name = "example-controller"
+				vault_issue_requests_per_second = 2.5
+				vault_issue_burst = 5
+			}`,
+			wantRequestsPerSecond: 2.5,
+			wantBurst:             5,
+		},
+		{
+			name: "negative-requests-per-second",
+			config: `
+			controller {
+				name = "example-controller"
+				vault_issue_requests_per_second = -1
+			}`,
+			wantErr: "Controller vault_issue_requests_per_second must not be negative",
+		},
+		{
+			name: "negative-burst",
+			config: `
+			controller {
+				name = "example-controller"
+				vault_issue_burst = -1
+			}`,
+			wantErr: "Controller vault_issue_burst must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := Parse(tt.config)
+			if tt.wantErr != "" {
+				require.Error(err)
+				assert.Equal(tt.wantErr, err.Error())
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.wantRequestsPerSecond, got.Controller.VaultIssueRequestsPerSecond)
+			assert.Equal(tt.wantBurst, got.Controller.VaultIssueBurst)
+		})
+	}
+}
+
+func TestController_VaultTokenFileDirectory(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		config  string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "default",
+			config: `
+			controller {
+				name = "example-controller"
+			}`,
+		},
+		{
+			name: "configured",
+			config: `
+			controller {
+				name = "example-controller"
+				vault_token_file_directory = "/var/run/boundary/vault"
+			}`,
+			want: "/var/run/boundary/vault",
+		},
+		{
+			name: "relative-path",
+			config: `
+			controller {
+				name = "example-controller"
+				vault_token_file_directory = "vault"
+			}`,
+			wantErr: "Controller vault_token_file_directory must be an absolute path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := Parse(tt.config)
+			if tt.wantErr != "" {
+				require.Error(err)
+				assert.Equal(tt.wantErr, err.Error())
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tt.want, got.Controller.VaultTokenFileDirectory)
+		})
+	}
+}
@@ -79,6 +79,10 @@ type cmdInfo struct {
 
 	// IsPluginType controls whether standard plugin flags are generated
 	IsPluginType bool
+
+	// HasExtraListOutputFunc controls whether the output of a list action is
+	// printed by an extra list output function instead of the standard output
+	HasExtraListOutputFunc bool
 }
 
 var inputStructs = map[string][]*cmdInfo{
@@ -203,13 +207,15 @@ var inputStructs = map[string][]*cmdInfo{
 	},
 	"credentiallibraries": {
 		{
-			ResourceType:     resource.CredentialLibrary.String(),
-			Pkg:              "credentiallibraries",
-			StdActions:       []string{"read", "delete", "list"},
-			IsAbstractType:   true,
-			HasExtraHelpFunc: true,
-			Container:        "CredentialStore",
-			HasId:            true,
+			ResourceType:           resource.CredentialLibrary.String(),
+			Pkg:                    "credentiallibraries",
+			StdActions:             []string{"read", "delete", "list"},
+			IsAbstractType:         true,
+			HasExtraCommandVars:    true,
+			HasExtraHelpFunc:       true,
+			HasExtraListOutputFunc: true,
+			Container:              "CredentialStore",
+			HasId:                  true,
 		},
 		{
 			ResourceType:        resource.CredentialLibrary.String(),
@@ -460,6 +460,9 @@ func (c *{{ camelCase .SubActionPrefix }}Command) Run(args []string) int {
 	{{ end }}
 	{{ if eq $action "list" }}
 	case "list":
+		{{ if $input.HasExtraListOutputFunc }}
+		return c.extraListOutputFunc(listResult)
+		{{ else }}
 		switch base.Format(c.UI) {
 		case "json":
 			if ok := c.PrintJsonItems(listResult); !ok {
@@ -472,6 +475,7 @@ func (c *{{ camelCase .SubActionPrefix }}Command) Run(args []string) int {
 		}
 
 		return base.CommandSuccess
+		{{ end }}
 	{{ end }}
 	{{ end }}
 	}
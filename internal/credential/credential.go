@@ -62,6 +62,15 @@ type Dynamic interface {
 	Purpose() Purpose
 }
 
+// Mapped is a dynamic credential mapped from part of the response to a
+// library's request. A library can map a single response into several
+// credentials. They share the library and the id of the request, so each
+// is distinguished by the name of the mapping it was mapped from.
+type Mapped interface {
+	Dynamic
+	MappingName() string
+}
+
 // A Request represents a request for a credential from the SourceId for
 // the given purpose. For dynamic credentials, the SourceId is the PublicId
 // of a credential library.
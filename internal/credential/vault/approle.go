@@ -0,0 +1,169 @@
+package vault
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+
+	"github.com/hashicorp/boundary/internal/credential/vault/store"
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	wrapping "github.com/hashicorp/go-kms-wrapping"
+	"github.com/hashicorp/go-kms-wrapping/structwrapping"
+	"google.golang.org/protobuf/proto"
+)
+
+// An AuthMethod is the method a credential store uses to obtain the Vault
+// token it uses.
+type AuthMethod string
+
+// Auth methods a credential store can use to obtain a Vault token.
+const (
+	// TokenAuthMethod is used by credential stores which are given a Vault
+	// token.
+	TokenAuthMethod AuthMethod = "token"
+
+	// AppRoleAuthMethod is used by credential stores which log in to
+	// Vault with an AppRole to obtain a Vault token.
+	AppRoleAuthMethod AuthMethod = "approle"
+
+	// TokenFileAuthMethod is used by credential stores which read their
+	// Vault token from a file, such as the token sink of a Vault agent,
+	// before each request to Vault.
+	TokenFileAuthMethod AuthMethod = "token-file"
+)
+
+// AppRole contains the RoleID and SecretID of a Vault AppRole. It is owned
+// by a credential store.
+type AppRole struct {
+	*store.AppRole
+	tableName string `gorm:"-"`
+}
+
+// NewAppRole creates a new in memory AppRole.
+func NewAppRole(ctx context.Context, roleId string, secretId SecretIdSecret) (*AppRole, error) {
+	const op = "vault.NewAppRole"
+	if roleId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no role id")
+	}
+	if len(secretId) == 0 {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no secret id")
+	}
+
+	secretIdCopy := make(SecretIdSecret, len(secretId))
+	copy(secretIdCopy, secretId)
+
+	a := &AppRole{
+		AppRole: &store.AppRole{
+			RoleId:   roleId,
+			SecretId: secretIdCopy,
+		},
+	}
+	return a, nil
+}
+
+func allocAppRole() *AppRole {
+	return &AppRole{
+		AppRole: &store.AppRole{},
+	}
+}
+
+func (a *AppRole) clone() *AppRole {
+	cp := proto.Clone(a.AppRole)
+	return &AppRole{
+		AppRole: cp.(*store.AppRole),
+	}
+}
+
+// TableName returns the table name.
+func (a *AppRole) TableName() string {
+	if a.tableName != "" {
+		return a.tableName
+	}
+	return "credential_vault_approle"
+}
+
+// SetTableName sets the table name.
+func (a *AppRole) SetTableName(n string) {
+	a.tableName = n
+}
+
+func (a *AppRole) encrypt(ctx context.Context, cipher wrapping.Wrapper) error {
+	const op = "vault.(AppRole).encrypt"
+	if len(a.SecretId) == 0 {
+		return errors.New(ctx, errors.InvalidParameter, op, "no secret id defined")
+	}
+	if err := structwrapping.WrapStruct(ctx, cipher, a.AppRole, nil); err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithCode(errors.Encrypt))
+	}
+	a.KeyId = cipher.KeyID()
+	if err := a.hmacSecretId(ctx, cipher); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	return nil
+}
+
+func (a *AppRole) decrypt(ctx context.Context, cipher wrapping.Wrapper) error {
+	const op = "vault.(AppRole).decrypt"
+	if err := structwrapping.UnwrapStruct(ctx, cipher, a.AppRole, nil); err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithCode(errors.Decrypt))
+	}
+	return nil
+}
+
+func (a *AppRole) hmacSecretId(ctx context.Context, cipher wrapping.Wrapper) error {
+	const op = "vault.(AppRole).hmacSecretId"
+	if cipher == nil {
+		return errors.New(ctx, errors.InvalidParameter, op, "missing cipher")
+	}
+	reader, err := kms.NewDerivedReader(cipher, 32, []byte(a.StoreId), nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	key, _, err := ed25519.GenerateKey(reader)
+	if err != nil {
+		return errors.New(ctx, errors.Encrypt, op, "unable to generate derived key")
+	}
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(a.SecretId)
+	a.SecretIdHmac = mac.Sum(nil)
+	return nil
+}
+
+func (a *AppRole) insertQuery() (query string, queryValues []interface{}) {
+	query = upsertAppRoleQuery
+	queryValues = []interface{}{
+		sql.Named("store_id", a.StoreId),
+		sql.Named("role_id", a.RoleId),
+		sql.Named("secret_id", a.CtSecretId),
+		sql.Named("secret_id_hmac", a.SecretIdHmac),
+		sql.Named("key_id", a.KeyId),
+	}
+	return
+}
+
+func (a *AppRole) deleteQuery() (query string, queryValues []interface{}) {
+	query = deleteAppRoleQuery
+	queryValues = []interface{}{
+		a.StoreId,
+	}
+	return
+}
+
+func (a *AppRole) oplogMessage(opType db.OpType) *oplog.Message {
+	msg := oplog.Message{
+		Message:  a.clone(),
+		TypeName: a.TableName(),
+	}
+	switch opType {
+	case db.CreateOp, db.UpdateOp:
+		msg.OpType = oplog.OpType_OP_TYPE_CREATE
+	case db.DeleteOp:
+		msg.OpType = oplog.OpType_OP_TYPE_DELETE
+	}
+	return &msg
+}
@@ -0,0 +1,64 @@
+package vault
+
+import (
+	"context"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/observability/event"
+	"github.com/hashicorp/eventlogger/filters/encrypt"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// auditDetails are the details of an audit event written by the vault
+// package. Every field of the details is classified as public, so the audit
+// filter never redacts or encrypts them. Secret values, such as tokens or
+// request bodies, must never be added to the details.
+type auditDetails struct {
+	*structpb.Struct
+}
+
+// Tags implements the encrypt.Taggable interface which allows the fields of
+// the audit details to be classified for the encrypt filter.
+func (d *auditDetails) Tags() ([]encrypt.PointerTag, error) {
+	tags := make([]encrypt.PointerTag, 0, len(d.GetFields()))
+	for k := range d.GetFields() {
+		tags = append(tags, encrypt.PointerTag{
+			Pointer:        "/Struct/Fields/" + k,
+			Classification: encrypt.PublicClassification,
+		})
+	}
+	return tags, nil
+}
+
+// writeAuditEvent writes an audit event for operation with fields as the
+// details of the event. The values in fields are written as is, so fields
+// must not contain secret values.
+//
+// The event gets its own id and is flushed immediately, so it's not composed
+// with the audit event of the API request which caused it.
+func writeAuditEvent(ctx context.Context, operation string, fields map[string]interface{}) error {
+	const op = "vault.writeAuditEvent"
+	id, err := event.NewId(event.IdPrefix)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	s, err := structpb.NewStruct(fields)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	opts := []event.Option{
+		event.WithId(id),
+		event.WithFlush(),
+		event.WithRequest(&event.Request{
+			Operation: operation,
+			Details:   &auditDetails{Struct: s},
+		}),
+	}
+	if info, ok := event.RequestInfoFromContext(ctx); ok {
+		opts = append(opts, event.WithRequestInfo(info))
+	}
+	if err := event.WriteAudit(ctx, op, opts...); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	return nil
+}
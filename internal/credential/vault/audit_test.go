@@ -0,0 +1,82 @@
+package vault
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/observability/event"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuditSinkFormats are the formats of the audit sinks created by
+// testAuditContext.
+var testAuditSinkFormats = []event.SinkFormat{event.JSONSinkFormat, event.TextHclogSinkFormat}
+
+// testAuditContext returns a context with an eventer which writes audit
+// events to a file sink for each of testAuditSinkFormats. The sinks use the
+// default audit filter operations. The returned function returns the
+// contents of the sink for a format.
+func testAuditContext(t *testing.T) (context.Context, func(event.SinkFormat) string) {
+	t.Helper()
+	require := require.New(t)
+	event.TestEnableEventing(t, true)
+
+	dir := t.TempDir()
+	c := event.EventerConfig{
+		AuditEnabled: true,
+	}
+	for _, f := range testAuditSinkFormats {
+		c.Sinks = append(c.Sinks, &event.SinkConfig{
+			Name:       string(f),
+			Type:       event.FileSink,
+			EventTypes: []event.Type{event.AuditType},
+			Format:     f,
+			FileConfig: &event.FileSinkTypeConfig{
+				Path:     dir,
+				FileName: string(f),
+			},
+			AuditConfig: event.DefaultAuditConfig(),
+		})
+	}
+	testLock := &sync.Mutex{}
+	testLogger := hclog.New(&hclog.LoggerOptions{
+		Mutex: testLock,
+		Name:  "test",
+	})
+	e, err := event.NewEventer(testLogger, testLock, t.Name(), c)
+	require.NoError(err)
+	ctx, err := event.NewEventerContext(context.Background(), e)
+	require.NoError(err)
+
+	return ctx, func(f event.SinkFormat) string {
+		t.Helper()
+		b, err := ioutil.ReadFile(filepath.Join(dir, string(f)))
+		require.NoError(err)
+		return string(b)
+	}
+}
+
+func Test_writeAuditEvent(t *testing.T) {
+	ctx, got := testAuditContext(t)
+	require.NoError(t, writeAuditEvent(ctx, "test-operation", map[string]interface{}{
+		"store_id":       "csvlt_1234567890",
+		"body_sent":      true,
+		"changed_fields": []interface{}{"name"},
+	}))
+
+	for _, f := range testAuditSinkFormats {
+		t.Run(string(f), func(t *testing.T) {
+			assert := assert.New(t)
+			got := got(f)
+			for _, want := range []string{"test-operation", "csvlt_1234567890", "body_sent", "changed_fields", "name"} {
+				assert.Contains(got, want)
+			}
+			assert.NotContains(got, "REDACTED")
+		})
+	}
+}
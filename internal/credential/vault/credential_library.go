@@ -1,8 +1,17 @@
 package vault
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/hashicorp/boundary/internal/credential"
 	"github.com/hashicorp/boundary/internal/credential/vault/store"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"google.golang.org/protobuf/proto"
 )
@@ -12,10 +21,60 @@ type Method string
 
 // HTTP methods use for communicating with Vault.
 const (
-	MethodGet  Method = "GET"
-	MethodPost Method = "POST"
+	MethodGet   Method = "GET"
+	MethodPost  Method = "POST"
+	MethodPut   Method = "PUT"
+	MethodPatch Method = "PATCH"
 )
 
+// Valid reports whether m is a supported HTTP method. Comparison is case
+// sensitive; use ParseMethod to normalize a method first.
+func (m Method) Valid() bool {
+	switch m {
+	case MethodGet, MethodPost, MethodPut, MethodPatch:
+		return true
+	}
+	return false
+}
+
+// AllowsRequestBody reports whether a request body can be sent to Vault
+// with m. Only GET requests cannot have a body.
+func (m Method) AllowsRequestBody() bool {
+	switch m {
+	case MethodPost, MethodPut, MethodPatch:
+		return true
+	}
+	return false
+}
+
+// ParseMethod returns the Method for s. The comparison is case insensitive.
+// An errors.InvalidParameter error is returned if s is not a supported HTTP
+// method.
+func ParseMethod(ctx context.Context, s string) (Method, error) {
+	const op = "vault.ParseMethod"
+	m := Method(strings.ToUpper(s))
+	if !m.Valid() {
+		return "", errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unsupported http method: %q", s))
+	}
+	return m, nil
+}
+
+// DefaultHttpMethod is the Method used for a CredentialLibrary when an HTTP
+// method is not specified. It can be overridden for a Repository with the
+// WithDefaultHttpMethod option.
+const DefaultHttpMethod = MethodGet
+
+// Content types of the request body a credential library sends to Vault
+// with the POST and PUT methods.
+const (
+	ContentTypeJson = "application/json"
+	ContentTypeForm = "application/x-www-form-urlencoded"
+)
+
+// DefaultContentType is the content type of the request body of a
+// CredentialLibrary when a content type is not specified.
+const DefaultContentType = ContentTypeJson
+
 // A CredentialLibrary contains a Vault path and is owned by a credential
 // store.
 type CredentialLibrary struct {
@@ -25,12 +84,26 @@ type CredentialLibrary struct {
 
 // NewCredentialLibrary creates a new in memory CredentialLibrary
 // for a Vault backend at vaultPath assigned to storeId.
-// Name, description, method, and request body are the only valid options.
-// All other options are ignored.
-func NewCredentialLibrary(storeId string, vaultPath string, opt ...Option) (*CredentialLibrary, error) {
+// Name, description, method, request body, credential JSON pointer,
+// templated vault path, credential mappings, wrap TTL, namespace, content
+// type, and template are the only valid options. All other options are
+// ignored.
+// A wrap TTL must be zero, for unwrapped responses, or a positive duration
+// of at least one second. A namespace overrides the namespace of the
+// credential store for the library's requests to Vault.
+func NewCredentialLibrary(ctx context.Context, storeId string, vaultPath string, opt ...Option) (*CredentialLibrary, error) {
 	const op = "vault.NewCredentialLibrary"
 	opts := getOpts(opt...)
 
+	mappings, err := encodeCredentialMappings(ctx, opts.withCredentialMappings)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	wrapTtl, err := wrapTtlSeconds(ctx, opts.withWrapTtl)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+
 	l := &CredentialLibrary{
 		CredentialLibrary: &store.CredentialLibrary{
 			StoreId:         storeId,
@@ -39,12 +112,57 @@ func NewCredentialLibrary(storeId string, vaultPath string, opt ...Option) (*Cre
 			VaultPath:       vaultPath,
 			HttpRequestBody: opts.withRequestBody,
 			HttpMethod:      string(opts.withMethod),
+
+			CredentialJsonPointer: opts.withCredentialJsonPointer,
+			TemplatedVaultPath:    opts.withTemplatedVaultPath,
+			CredentialMappings:    mappings,
+			WrapTtlSeconds:        wrapTtl,
+			Namespace:             opts.withNamespace,
+			ContentType:           opts.withContentType,
+			IsTemplate:            opts.withTemplate,
 		},
 	}
 
 	return l, nil
 }
 
+// wrapTtlSeconds returns d in whole seconds. An errors.InvalidParameter
+// error is returned if d is negative or greater than zero but less than
+// one second.
+func wrapTtlSeconds(ctx context.Context, d time.Duration) (uint32, error) {
+	const op = "vault.wrapTtlSeconds"
+	switch {
+	case d == 0:
+		return 0, nil
+	case d < time.Second:
+		return 0, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("wrap ttl must be a positive duration of at least one second: %s", d))
+	}
+	return uint32(d / time.Second), nil
+}
+
+// validLibraryNamespace returns an errors.InvalidParameter error if ns
+// contains only whitespace. An empty ns is valid and means the namespace
+// of the credential store is used.
+func validLibraryNamespace(ctx context.Context, ns string) error {
+	const op = "vault.validLibraryNamespace"
+	if ns != "" && strings.TrimSpace(ns) == "" {
+		return errors.New(ctx, errors.InvalidParameter, op, "namespace must not be empty", errors.WithField(namespaceField))
+	}
+	return nil
+}
+
+// validLibraryContentType returns an errors.InvalidParameter error if ct is
+// not empty, ContentTypeJson, or ContentTypeForm. An empty ct means
+// DefaultContentType is used.
+func validLibraryContentType(ctx context.Context, ct string) error {
+	const op = "vault.validLibraryContentType"
+	switch ct {
+	case "", ContentTypeJson, ContentTypeForm:
+		return nil
+	}
+	return errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unsupported content type: %q", ct), errors.WithField(contentTypeField))
+}
+
 func allocCredentialLibrary() *CredentialLibrary {
 	return &CredentialLibrary{
 		CredentialLibrary: &store.CredentialLibrary{},
@@ -71,6 +189,42 @@ func (l *CredentialLibrary) SetTableName(n string) {
 	l.tableName = n
 }
 
+// ContentHash returns a hex-encoded SHA-256 hash of the fields of l which
+// can be changed by UpdateCredentialLibrary. Libraries with equal values
+// for those fields have equal hashes regardless of their ids, versions,
+// and timestamps, so the hash can be compared to detect a change to a
+// library without comparing each field.
+func (l *CredentialLibrary) ContentHash() string {
+	h := sha256.New()
+	// Each value is prefixed with its length so bytes cannot move from one
+	// value to the next without changing the hash.
+	write := func(b []byte) {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(b)))
+		h.Write(n[:])
+		h.Write(b)
+	}
+	var templated byte
+	if l.GetTemplatedVaultPath() {
+		templated = 1
+	}
+	var wrapTtl [4]byte
+	binary.BigEndian.PutUint32(wrapTtl[:], l.GetWrapTtlSeconds())
+
+	write([]byte(l.GetName()))
+	write([]byte(l.GetDescription()))
+	write([]byte(l.GetVaultPath()))
+	write([]byte(l.GetHttpMethod()))
+	write(l.GetHttpRequestBody())
+	write([]byte(l.GetCredentialJsonPointer()))
+	write([]byte{templated})
+	write(l.GetCredentialMappings())
+	write(wrapTtl[:])
+	write([]byte(l.GetNamespace()))
+	write([]byte(l.GetContentType()))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func (l *CredentialLibrary) oplog(op oplog.OpType) oplog.Metadata {
 	metadata := oplog.Metadata{
 		"resource-public-id": []string{l.PublicId},
@@ -3,9 +3,12 @@ package vault
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/credential/vault/store"
 	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/db/timestamp"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,6 +21,56 @@ func TestMethodType(t *testing.T) {
 	assert.IsType(MethodPost, MethodGet)
 }
 
+func TestParseMethod(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    Method
+		wantErr bool
+	}{
+		{in: "GET", want: MethodGet},
+		{in: "POST", want: MethodPost},
+		{in: "get", want: MethodGet},
+		{in: "Post", want: MethodPost},
+		{in: "PUT", want: MethodPut},
+		{in: "patch", want: MethodPatch},
+		{in: "DELETE", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			assert := assert.New(t)
+			got, err := ParseMethod(context.Background(), tt.in)
+			if tt.wantErr {
+				assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+				assert.Empty(got)
+				assert.False(got.Valid())
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, got)
+			assert.True(got.Valid())
+		})
+	}
+	t.Run("valid-is-case-sensitive", func(t *testing.T) {
+		assert := assert.New(t)
+		assert.False(Method("get").Valid())
+		assert.False(Method("put").Valid())
+		assert.False(Method("DELETE").Valid())
+	})
+}
+
+func TestMethod_AllowsRequestBody(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	assert.False(MethodGet.AllowsRequestBody())
+	assert.True(MethodPost.AllowsRequestBody())
+	assert.True(MethodPut.AllowsRequestBody())
+	assert.True(MethodPatch.AllowsRequestBody())
+	assert.False(Method("DELETE").AllowsRequestBody())
+}
+
 func TestCredentialLibrary_New(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -190,6 +243,102 @@ func TestCredentialLibrary_New(t *testing.T) {
 			},
 			wantCreateErr: true,
 		},
+		{
+			name: "valid-with-wrap-ttl",
+			args: args{
+				storeId:   cs.PublicId,
+				vaultPath: "vault/path",
+				opts: []Option{
+					WithMethod(MethodGet),
+					WithWrapTtl(5 * time.Minute),
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:        cs.PublicId,
+					VaultPath:      "vault/path",
+					HttpMethod:     "GET",
+					WrapTtlSeconds: 300,
+				},
+			},
+		},
+		{
+			name: "valid-with-namespace",
+			args: args{
+				storeId:   cs.PublicId,
+				vaultPath: "vault/path",
+				opts: []Option{
+					WithMethod(MethodGet),
+					WithNamespace("library-ns"),
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.PublicId,
+					VaultPath:  "vault/path",
+					HttpMethod: "GET",
+					Namespace:  "library-ns",
+				},
+			},
+		},
+		{
+			name: "valid-with-content-type",
+			args: args{
+				storeId:   cs.PublicId,
+				vaultPath: "vault/path",
+				opts: []Option{
+					WithMethod(MethodPost),
+					WithContentType(ContentTypeForm),
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:     cs.PublicId,
+					VaultPath:   "vault/path",
+					HttpMethod:  "POST",
+					ContentType: ContentTypeForm,
+				},
+			},
+		},
+		{
+			name: "valid-with-template",
+			args: args{
+				storeId:   cs.PublicId,
+				vaultPath: "pki/issue/{{role}}",
+				opts: []Option{
+					WithTemplate(true),
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.PublicId,
+					VaultPath:  "pki/issue/{{role}}",
+					IsTemplate: true,
+				},
+			},
+		},
+		{
+			name: "negative-wrap-ttl",
+			args: args{
+				storeId:   cs.PublicId,
+				vaultPath: "vault/path",
+				opts: []Option{
+					WithWrapTtl(-time.Minute),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sub-second-wrap-ttl",
+			args: args{
+				storeId:   cs.PublicId,
+				vaultPath: "vault/path",
+				opts: []Option{
+					WithWrapTtl(500 * time.Millisecond),
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -197,7 +346,7 @@ func TestCredentialLibrary_New(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			assert, require := assert.New(t), require.New(t)
 			ctx := context.Background()
-			got, err := NewCredentialLibrary(tt.args.storeId, tt.args.vaultPath, tt.args.opts...)
+			got, err := NewCredentialLibrary(ctx, tt.args.storeId, tt.args.vaultPath, tt.args.opts...)
 			if tt.wantErr {
 				assert.Error(err)
 				require.Nil(got)
@@ -224,3 +373,120 @@ func TestCredentialLibrary_New(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialLibrary_ContentHash(t *testing.T) {
+	t.Parallel()
+	newLib := func() *CredentialLibrary {
+		return &CredentialLibrary{
+			CredentialLibrary: &store.CredentialLibrary{
+				PublicId:              "clvlt_1234567890",
+				StoreId:               "csvlt_1234567890",
+				Version:               1,
+				Name:                  "name",
+				Description:           "description",
+				VaultPath:             "pki/issue/boundary",
+				HttpMethod:            string(MethodPost),
+				HttpRequestBody:       []byte(`{"common_name":"boundary.com"}`),
+				CredentialJsonPointer: "/data",
+				CredentialMappings:    []byte(`[{"type":"username_password"}]`),
+				WrapTtlSeconds:        60,
+				Namespace:             "ns",
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		change   func(*CredentialLibrary)
+		wantSame bool
+	}{
+		{
+			name:     "equal-content",
+			change:   func(l *CredentialLibrary) {},
+			wantSame: true,
+		},
+		{
+			name: "different-read-only-fields",
+			change: func(l *CredentialLibrary) {
+				l.PublicId = "clvlt_0987654321"
+				l.StoreId = "csvlt_0987654321"
+				l.Version = 2
+				l.CreateTime = timestamp.Now()
+				l.UpdateTime = timestamp.Now()
+			},
+			wantSame: true,
+		},
+		{
+			name:   "name",
+			change: func(l *CredentialLibrary) { l.Name = "other" },
+		},
+		{
+			name:   "description",
+			change: func(l *CredentialLibrary) { l.Description = "other" },
+		},
+		{
+			name:   "vault-path",
+			change: func(l *CredentialLibrary) { l.VaultPath = "pki/issue/other" },
+		},
+		{
+			name:   "http-method",
+			change: func(l *CredentialLibrary) { l.HttpMethod = string(MethodGet) },
+		},
+		{
+			name:   "http-request-body",
+			change: func(l *CredentialLibrary) { l.HttpRequestBody = []byte(`{"common_name":"other.com"}`) },
+		},
+		{
+			name:   "no-http-request-body",
+			change: func(l *CredentialLibrary) { l.HttpRequestBody = nil },
+		},
+		{
+			name:   "credential-json-pointer",
+			change: func(l *CredentialLibrary) { l.CredentialJsonPointer = "/other" },
+		},
+		{
+			name:   "templated-vault-path",
+			change: func(l *CredentialLibrary) { l.TemplatedVaultPath = true },
+		},
+		{
+			name:   "credential-mappings",
+			change: func(l *CredentialLibrary) { l.CredentialMappings = nil },
+		},
+		{
+			name:   "wrap-ttl",
+			change: func(l *CredentialLibrary) { l.WrapTtlSeconds = 61 },
+		},
+		{
+			name:   "namespace",
+			change: func(l *CredentialLibrary) { l.Namespace = "other" },
+		},
+		{
+			name:   "content-type",
+			change: func(l *CredentialLibrary) { l.ContentType = ContentTypeForm },
+		},
+		{
+			name: "value-shifted-between-fields",
+			change: func(l *CredentialLibrary) {
+				l.Name = "namedescription"
+				l.Description = ""
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+			want := newLib().ContentHash()
+			lib := newLib()
+			tt.change(lib)
+			got := lib.ContentHash()
+			assert.Len(got, 64)
+			if tt.wantSame {
+				assert.Equal(want, got)
+			} else {
+				assert.NotEqual(want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/credential"
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// A CredentialType is the type of a credential mapped from the data of a
+// Vault response.
+type CredentialType string
+
+// Credential types supported by a CredentialMapping.
+const (
+	UserPasswordCredentialType CredentialType = "user_password"
+	CertificateCredentialType  CredentialType = "certificate"
+)
+
+// A CredentialMapping maps values in the data of a Vault response to a
+// typed credential. Each value is selected with an RFC 6901 JSON pointer.
+//
+// A UserPasswordCredentialType mapping requires UsernamePointer and
+// PasswordPointer. A CertificateCredentialType mapping requires
+// CertificatePointer and PrivateKeyPointer. The pointers of the other
+// credential types must not be set.
+type CredentialMapping struct {
+	Name string         `json:"name"`
+	Type CredentialType `json:"type"`
+
+	UsernamePointer string `json:"username_pointer,omitempty"`
+	PasswordPointer string `json:"password_pointer,omitempty"`
+
+	CertificatePointer string `json:"certificate_pointer,omitempty"`
+	PrivateKeyPointer  string `json:"private_key_pointer,omitempty"`
+}
+
+// encodeCredentialMappings returns the JSON encoding of mappings stored in
+// the CredentialMappings field of a credential library. It returns nil if
+// mappings is empty.
+func encodeCredentialMappings(ctx context.Context, mappings []*CredentialMapping) ([]byte, error) {
+	const op = "vault.encodeCredentialMappings"
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(mappings)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.InvalidParameter))
+	}
+	return b, nil
+}
+
+// decodeCredentialMappings decodes and validates the CredentialMappings
+// field of a credential library. An errors.InvalidParameter error is
+// returned if b is not a valid list of mappings.
+func decodeCredentialMappings(ctx context.Context, b []byte) ([]*CredentialMapping, error) {
+	const op = "vault.decodeCredentialMappings"
+	var mappings []*CredentialMapping
+	if err := json.Unmarshal(b, &mappings); err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.InvalidParameter), errors.WithMsg("invalid credential mappings"))
+	}
+	if len(mappings) == 0 {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "empty credential mappings")
+	}
+	names := make(map[string]bool, len(mappings))
+	for _, m := range mappings {
+		if m == nil {
+			return nil, errors.New(ctx, errors.InvalidParameter, op, "nil credential mapping")
+		}
+		if m.Name == "" {
+			return nil, errors.New(ctx, errors.InvalidParameter, op, "credential mapping has no name")
+		}
+		if names[m.Name] {
+			return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("duplicate credential mapping name: %s", m.Name))
+		}
+		names[m.Name] = true
+
+		var required, unused []string
+		switch m.Type {
+		case UserPasswordCredentialType:
+			required = []string{m.UsernamePointer, m.PasswordPointer}
+			unused = []string{m.CertificatePointer, m.PrivateKeyPointer}
+		case CertificateCredentialType:
+			required = []string{m.CertificatePointer, m.PrivateKeyPointer}
+			unused = []string{m.UsernamePointer, m.PasswordPointer}
+		default:
+			return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("credential mapping %s: unsupported credential type: %q", m.Name, m.Type))
+		}
+		for _, p := range required {
+			if _, err := parseJsonPointer(ctx, p); err != nil {
+				return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("credential mapping %s", m.Name)))
+			}
+		}
+		for _, p := range unused {
+			if p != "" {
+				return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("credential mapping %s: json pointer %q not allowed for credential type %s", m.Name, p, m.Type))
+			}
+		}
+	}
+	return mappings, nil
+}
+
+// mapCredentials returns a credential for each of the mappings in b with
+// the values selected from data. Each returned credential is a copy of ac
+// with the secret data replaced by the mapped values. The credentials
+// share the id of ac since they are issued by a single Vault request, so
+// each is distinguished by the name of its mapping.
+func mapCredentials(ctx context.Context, ac *actualCredential, b []byte, data interface{}) ([]credential.Dynamic, error) {
+	const op = "vault.mapCredentials"
+	mappings, err := decodeCredentialMappings(ctx, b)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	lookup := func(m *CredentialMapping, p string) (string, error) {
+		v, err := resolveJsonPointer(ctx, p, data)
+		if err != nil {
+			return "", errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("credential mapping %s", m.Name)))
+		}
+		s, ok := v.(string)
+		if !ok {
+			return "", errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("credential mapping %s: json pointer %q does not reference a string", m.Name, p))
+		}
+		return s, nil
+	}
+
+	creds := make([]credential.Dynamic, 0, len(mappings))
+	for _, m := range mappings {
+		base := *ac
+		switch m.Type {
+		case UserPasswordCredentialType:
+			username, err := lookup(m, m.UsernamePointer)
+			if err != nil {
+				return nil, err
+			}
+			password, err := lookup(m, m.PasswordPointer)
+			if err != nil {
+				return nil, err
+			}
+			base.secretData = map[string]interface{}{
+				"username": username,
+				"password": password,
+			}
+			creds = append(creds, &userPasswordCredential{
+				actualCredential: &base,
+				name:             m.Name,
+				username:         username,
+				password:         credential.Password(password),
+			})
+		case CertificateCredentialType:
+			cert, err := lookup(m, m.CertificatePointer)
+			if err != nil {
+				return nil, err
+			}
+			key, err := lookup(m, m.PrivateKeyPointer)
+			if err != nil {
+				return nil, err
+			}
+			base.secretData = map[string]interface{}{
+				"certificate": cert,
+				"private_key": key,
+			}
+			creds = append(creds, &certificateCredential{
+				actualCredential: &base,
+				name:             m.Name,
+				certificate:      []byte(cert),
+				privateKey:       credential.PrivateKey(key),
+			})
+		}
+	}
+	return creds, nil
+}
+
+var (
+	_ credential.Mapped       = (*userPasswordCredential)(nil)
+	_ credential.UserPassword = (*userPasswordCredential)(nil)
+)
+
+// userPasswordCredential is a username and password credential mapped
+// from the data of a Vault response.
+type userPasswordCredential struct {
+	*actualCredential
+	name     string
+	username string
+	password credential.Password
+}
+
+// MappingName returns the name of the CredentialMapping of the credential.
+func (c *userPasswordCredential) MappingName() string           { return c.name }
+func (c *userPasswordCredential) Username() string              { return c.username }
+func (c *userPasswordCredential) Password() credential.Password { return c.password }
+
+var (
+	_ credential.Mapped      = (*certificateCredential)(nil)
+	_ credential.Certificate = (*certificateCredential)(nil)
+)
+
+// certificateCredential is a certificate and private key credential
+// mapped from the data of a Vault response.
+type certificateCredential struct {
+	*actualCredential
+	name        string
+	certificate []byte
+	privateKey  credential.PrivateKey
+}
+
+// MappingName returns the name of the CredentialMapping of the credential.
+func (c *certificateCredential) MappingName() string            { return c.name }
+func (c *certificateCredential) Certificate() []byte            { return c.certificate }
+func (c *certificateCredential) Private() credential.PrivateKey { return c.privateKey }
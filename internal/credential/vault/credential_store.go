@@ -1,7 +1,10 @@
 package vault
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/credential/vault/store"
 	"github.com/hashicorp/boundary/internal/db"
@@ -16,6 +19,7 @@ type CredentialStore struct {
 	tableName string `gorm:"-"`
 
 	clientCert  *ClientCertificate `gorm:"-"`
+	appRole     *AppRole           `gorm:"-"`
 	inputToken  TokenSecret        `gorm:"-"`
 	outputToken *Token             `gorm:"-"`
 
@@ -25,13 +29,30 @@ type CredentialStore struct {
 
 // NewCredentialStore creates a new in memory CredentialStore for a Vault
 // server at vaultAddress assigned to scopeId. Name, description, CA cert,
-// client cert, namespace, TLS server name, and TLS skip verify are the
-// only valid options. All other options are ignored.
-func NewCredentialStore(scopeId string, vaultAddress string, token TokenSecret, opt ...Option) (*CredentialStore, error) {
+// client cert, AppRole, token file, namespace, TLS server name, TLS skip
+// verify, TLS min version, connect timeout, request timeout, and requests
+// per second are the only valid options. All other options are ignored.
+// token should be empty if an AppRole or a token file is provided. A connect or request timeout must be
+// zero, for the Vault client default, or at least one second. The TLS min
+// version must be empty, TlsVersion12, or TlsVersion13.
+func NewCredentialStore(ctx context.Context, scopeId string, vaultAddress string, token TokenSecret, opt ...Option) (*CredentialStore, error) {
+	const op = "vault.NewCredentialStore"
 	opts := getOpts(opt...)
+	connectTimeout, err := timeoutSeconds(ctx, opts.withConnectTimeout)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("invalid connect timeout"))
+	}
+	requestTimeout, err := timeoutSeconds(ctx, opts.withRequestTimeout)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("invalid request timeout"))
+	}
+	if _, err := tlsMinVersion(ctx, opts.withTlsMinVersion); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
 	cs := &CredentialStore{
 		inputToken: token,
 		clientCert: opts.withClientCert,
+		appRole:    opts.withAppRole,
 		CredentialStore: &store.CredentialStore{
 			ScopeId:       scopeId,
 			Name:          opts.withName,
@@ -41,11 +62,31 @@ func NewCredentialStore(scopeId string, vaultAddress string, token TokenSecret,
 			Namespace:     opts.withNamespace,
 			TlsServerName: opts.withTlsServerName,
 			TlsSkipVerify: opts.withTlsSkipVerify,
+			TlsMinVersion: opts.withTlsMinVersion,
+
+			ConnectTimeoutSeconds: connectTimeout,
+			RequestTimeoutSeconds: requestTimeout,
+			RequestsPerSecond:     opts.withRequestsPerSecond,
+			TokenFilePath:         opts.withTokenFile,
 		},
 	}
 	return cs, nil
 }
 
+// timeoutSeconds returns d in whole seconds. An errors.InvalidParameter
+// error is returned if d is negative or greater than zero but less than
+// one second.
+func timeoutSeconds(ctx context.Context, d time.Duration) (uint32, error) {
+	const op = "vault.timeoutSeconds"
+	switch {
+	case d == 0:
+		return 0, nil
+	case d < time.Second:
+		return 0, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("timeout must be at least one second: %s", d))
+	}
+	return uint32(d / time.Second), nil
+}
+
 func allocCredentialStore() *CredentialStore {
 	return &CredentialStore{
 		CredentialStore: &store.CredentialStore{},
@@ -59,10 +100,15 @@ func (cs *CredentialStore) clone() *CredentialStore {
 	if cs.clientCert != nil {
 		clientCertCopy = cs.clientCert.clone()
 	}
+	var appRoleCopy *AppRole
+	if cs.appRole != nil {
+		appRoleCopy = cs.appRole.clone()
+	}
 	cp := proto.Clone(cs.CredentialStore)
 	return &CredentialStore{
 		inputToken:      tokenCopy,
 		clientCert:      clientCertCopy,
+		appRole:         appRoleCopy,
 		CredentialStore: cp.(*store.CredentialStore),
 	}
 }
@@ -109,6 +155,34 @@ func (cs *CredentialStore) applyUpdate(new *CredentialStore, fieldMaskPaths []st
 			cp.TlsSkipVerify = new.TlsSkipVerify
 		case strings.EqualFold(tokenField, f):
 			cp.inputToken = new.inputToken
+		case strings.EqualFold(connectTimeoutField, f):
+			cp.ConnectTimeoutSeconds = new.ConnectTimeoutSeconds
+		case strings.EqualFold(requestTimeoutField, f):
+			cp.RequestTimeoutSeconds = new.RequestTimeoutSeconds
+		case strings.EqualFold(requestsPerSecField, f):
+			cp.RequestsPerSecond = new.RequestsPerSecond
+		case strings.EqualFold(tlsMinVersionField, f):
+			cp.TlsMinVersion = new.TlsMinVersion
+		case strings.EqualFold(appRoleRoleIdField, f):
+			if new.appRole == nil {
+				cp.appRole = nil
+				continue
+			}
+			if cp.appRole == nil {
+				cp.appRole = allocAppRole()
+			}
+			cp.appRole.RoleId = new.appRole.GetRoleId()
+			cp.appRole.StoreId = cs.GetPublicId()
+		case strings.EqualFold(appRoleSecretIdField, f):
+			if new.appRole == nil {
+				cp.appRole = nil
+				continue
+			}
+			if cp.appRole == nil {
+				cp.appRole = allocAppRole()
+			}
+			cp.appRole.SecretId = new.appRole.GetSecretId()
+			cp.appRole.StoreId = cs.GetPublicId()
 		}
 	}
 	return cp
@@ -165,24 +239,81 @@ func (cs *CredentialStore) ClientCertificate() *ClientCertificate {
 	return cs.clientCert
 }
 
-func (cs *CredentialStore) client() (*client, error) {
+// AppRole returns the AppRole if available.
+func (cs *CredentialStore) AppRole() *AppRole {
+	return cs.appRole
+}
+
+// AuthMethod returns the method the credential store uses to obtain its
+// Vault token. The method persisted for the credential store is returned if
+// it is set. Otherwise the method is determined from the AppRole and token
+// file of the credential store.
+func (cs *CredentialStore) AuthMethod() AuthMethod {
+	if m := cs.CredentialStore.GetAuthMethod(); m != "" {
+		return AuthMethod(m)
+	}
+	return cs.derivedAuthMethod()
+}
+
+// derivedAuthMethod returns the auth method determined from the AppRole and
+// token file of the credential store.
+func (cs *CredentialStore) derivedAuthMethod() AuthMethod {
+	switch {
+	case cs.appRole != nil:
+		return AppRoleAuthMethod
+	case cs.GetTokenFilePath() != "":
+		return TokenFileAuthMethod
+	}
+	return TokenAuthMethod
+}
+
+// Warnings returns the non-fatal problems with the configuration of the
+// credential store. A warning is returned if TLS verification is disabled
+// or if the Vault address uses https and no CA certificate is provided.
+func (cs *CredentialStore) Warnings() []string {
+	var warnings []string
+	if cs.GetTlsSkipVerify() {
+		warnings = append(warnings, "TLS verification disabled")
+	}
+	if len(cs.GetCaCert()) == 0 && strings.HasPrefix(strings.ToLower(cs.GetVaultAddress()), "https://") {
+		warnings = append(warnings, "no CA certificate provided, the Vault server's certificate is verified with the system's trusted CA certificates")
+	}
+	return warnings
+}
+
+// client returns a Vault client for cs. If cs reads its token from a file,
+// WithTokenFileDirectory must provide the repository's token file directory.
+// The client's requests are rate limited by WithRateLimiters.
+func (cs *CredentialStore) client(ctx context.Context, opt ...Option) (*client, error) {
 	const op = "vault.(CredentialStore).client"
+	opts := getOpts(opt...)
 	clientConfig := &clientConfig{
 		Addr:          cs.VaultAddress,
 		Token:         cs.inputToken,
 		CaCert:        cs.CaCert,
 		TlsServerName: cs.TlsServerName,
 		TlsSkipVerify: cs.TlsSkipVerify,
+		TlsMinVersion: cs.TlsMinVersion,
 		Namespace:     cs.Namespace,
+
+		ConnectTimeout: time.Duration(cs.ConnectTimeoutSeconds) * time.Second,
+		RequestTimeout: time.Duration(cs.RequestTimeoutSeconds) * time.Second,
+
+		StoreId:           cs.PublicId,
+		RequestsPerSecond: cs.RequestsPerSecond,
+		Limiters:          opts.withRateLimiters,
+
+		TokenFile:    cs.TokenFilePath,
+		TokenFileDir: opts.withTokenFileDirectory,
 	}
 	if cs.clientCert != nil {
 		clientConfig.ClientCert = cs.clientCert.GetCertificate()
 		clientConfig.ClientKey = cs.clientCert.GetCertificateKey()
 	}
 
-	c, err := newClient(clientConfig)
+	c, err := newClient(ctx, clientConfig)
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op)
+		return nil, errors.Wrap(ctx, err, op)
 	}
 	return c, nil
 }
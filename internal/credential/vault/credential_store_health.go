@@ -0,0 +1,164 @@
+package vault
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+)
+
+const (
+	// defaultHealthCheckWorkers is the default maximum number of credential
+	// stores checked concurrently by CredentialStoresHealth.
+	defaultHealthCheckWorkers = 5
+
+	// defaultHealthCheckTimeout is the default maximum duration of the
+	// check of a credential store by CredentialStoresHealth.
+	defaultHealthCheckTimeout = 10 * time.Second
+)
+
+// CredentialStoreHealth is the result of checking the Vault token of a
+// credential store.
+type CredentialStoreHealth struct {
+	StoreId string
+	// TokenValid is true if Vault accepted a lookup of the store's current
+	// token.
+	TokenValid bool
+	// ExpirationTime is the expiration time of the token reported by
+	// Vault. It is only set if TokenValid is true.
+	ExpirationTime time.Time
+	// Err is the reason the check of the store failed. It is nil if
+	// TokenValid is true.
+	Err error
+}
+
+// CredentialStoresHealth checks the Vault token of each credential store
+// in scopeId by looking up the token in Vault. A result is returned for
+// every store, including the stores whose check failed, in the same order
+// as ListCredentialStores.
+//
+// WithHealthCheckWorkers sets the maximum number of stores checked
+// concurrently and WithHealthCheckTimeout sets the maximum duration of the
+// check of each store. WithLimit is also supported.
+func (r *Repository) CredentialStoresHealth(ctx context.Context, scopeId string, opt ...Option) ([]*CredentialStoreHealth, error) {
+	const op = "vault.(Repository).CredentialStoresHealth"
+	if scopeId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	}
+	opts := getOpts(opt...)
+	stores, err := r.ListCredentialStores(ctx, []string{scopeId}, WithLimit(opts.withLimit))
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if len(stores) == 0 {
+		return nil, nil
+	}
+
+	var current []*privateStore
+	if err := r.reader.SearchWhere(ctx, &current, "scope_id = ? and token_status = ?", []interface{}{scopeId, CurrentToken}, db.WithLimit(-1)); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	databaseWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeDatabase)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to get database wrapper"))
+	}
+	byId := make(map[string]*privateStore, len(current))
+	for _, ps := range current {
+		if err := ps.decrypt(ctx, databaseWrapper); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+		byId[ps.PublicId] = ps
+	}
+
+	checks := make([]storeHealthCheck, 0, len(stores))
+	for _, cs := range stores {
+		checks = append(checks, storeHealthCheck{
+			storeId:      cs.GetPublicId(),
+			store:        byId[cs.GetPublicId()],
+			tokenFileDir: r.tokenFileDir,
+			limiters:     r.limiters,
+		})
+	}
+	return checkStoresHealth(ctx, checks, opts.withHealthCheckWorkers, opts.withHealthCheckTimeout), nil
+}
+
+// storeHealthCheck is a credential store to check. store is nil if the
+// credential store does not have a current token. tokenFileDir is the
+// directory the token file of store must be in and limiters holds the
+// rate limiter of store.
+type storeHealthCheck struct {
+	storeId      string
+	store        *privateStore
+	tokenFileDir string
+	limiters     *RateLimiters
+}
+
+// checkStoresHealth checks each of the credential stores in checks using up
+// to workers concurrent checks, each limited to timeout. The results are
+// in the same order as checks.
+func checkStoresHealth(ctx context.Context, checks []storeHealthCheck, workers int, timeout time.Duration) []*CredentialStoreHealth {
+	if workers <= 0 {
+		workers = defaultHealthCheckWorkers
+	}
+	if workers > len(checks) {
+		workers = len(checks)
+	}
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+
+	results := make([]*CredentialStoreHealth, len(checks))
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				results[i] = checkStoreHealth(ctx, checks[i], timeout)
+			}
+		}()
+	}
+	for i := range checks {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+	return results
+}
+
+// checkStoreHealth looks up the current token of a credential store in
+// Vault.
+func checkStoreHealth(ctx context.Context, check storeHealthCheck, timeout time.Duration) *CredentialStoreHealth {
+	const op = "vault.checkStoreHealth"
+	h := &CredentialStoreHealth{
+		StoreId: check.storeId,
+	}
+	if check.store == nil {
+		h.Err = errors.New(ctx, errors.RecordNotFound, op, "credential store does not have a current token")
+		return h
+	}
+	c, err := check.store.client(ctx, WithTokenFileDirectory(check.tokenFileDir), WithRateLimiters(check.limiters))
+	if err != nil {
+		h.Err = errors.Wrap(ctx, err, op)
+		return h
+	}
+	c.cl.SetClientTimeout(timeout)
+	now := time.Now()
+	t, err := c.lookupToken(ctx)
+	if err != nil {
+		h.Err = errors.Wrap(ctx, err, op)
+		return h
+	}
+	ttl, err := t.TokenTTL()
+	if err != nil {
+		h.Err = errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault token ttl"))
+		return h
+	}
+	h.TokenValid = true
+	h.ExpirationTime = now.Add(ttl)
+	return h
+}
@@ -0,0 +1,99 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkStoresHealth(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":{"ttl":3600,"renewable":true}}`)
+	}))
+	t.Cleanup(healthy.Close)
+
+	forbidden := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errors":["permission denied"]}`)
+	}))
+	t.Cleanup(forbidden.Close)
+
+	done := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(hanging.Close)
+	t.Cleanup(func() { close(done) })
+
+	newCheck := func(addr string) storeHealthCheck {
+		id, err := newCredentialStoreId()
+		require.NoError(err)
+		return storeHealthCheck{
+			storeId: id,
+			store: &privateStore{
+				PublicId:     id,
+				VaultAddress: addr,
+				Token:        TokenSecret("token"),
+			},
+		}
+	}
+	missingId, err := newCredentialStoreId()
+	require.NoError(err)
+
+	checks := []storeHealthCheck{
+		newCheck(healthy.URL),
+		newCheck(forbidden.URL),
+		newCheck(hanging.URL),
+		{storeId: missingId},
+		newCheck(healthy.URL),
+	}
+
+	start := time.Now()
+	got := checkStoresHealth(ctx, checks, 2, 500*time.Millisecond)
+	assert.Less(int64(time.Since(start)), int64(5*time.Second), "hanging store should time out")
+	require.Len(got, len(checks))
+	for i, h := range got {
+		assert.Equal(checks[i].storeId, h.StoreId, "results should be in the same order as the checks")
+	}
+
+	for _, i := range []int{0, 4} {
+		assert.True(got[i].TokenValid)
+		assert.NoError(got[i].Err)
+		assert.WithinDuration(start.Add(time.Hour), got[i].ExpirationTime, time.Minute)
+	}
+	for _, i := range []int{1, 2, 3} {
+		assert.False(got[i].TokenValid)
+		assert.Error(got[i].Err)
+		assert.True(got[i].ExpirationTime.IsZero())
+	}
+	assert.Truef(errors.Match(errors.T(errors.RecordNotFound), got[3].Err), "want err code: %q got: %q", errors.RecordNotFound, got[3].Err)
+}
+
+func Test_checkStoresHealth_Defaults(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	assert.Empty(checkStoresHealth(context.Background(), nil, 0, 0))
+
+	id, err := newCredentialStoreId()
+	require.NoError(t, err)
+	got := checkStoresHealth(context.Background(), []storeHealthCheck{{storeId: id}}, 0, 0)
+	assert.Len(got, 1)
+	assert.Equal(id, got[0].StoreId)
+	assert.False(got[0].TokenValid)
+}
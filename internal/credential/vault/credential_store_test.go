@@ -3,6 +3,7 @@ package vault
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/boundary/internal/credential/vault/store"
@@ -213,6 +214,101 @@ func TestCredentialStore_New(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid-with-tls-min-version",
+			args: args{
+				scopeId:      scope.PublicId,
+				vaultAddress: "https://vault.consul.service",
+				token:        []byte("token"),
+				opts: []Option{
+					WithTlsMinVersion(TlsVersion13),
+				},
+			},
+			want: &CredentialStore{
+				inputToken: []byte("token"),
+				CredentialStore: &store.CredentialStore{
+					ScopeId:       scope.PublicId,
+					VaultAddress:  "https://vault.consul.service",
+					TlsMinVersion: TlsVersion13,
+				},
+			},
+		},
+		{
+			name: "unsupported-tls-min-version",
+			args: args{
+				scopeId:      scope.PublicId,
+				vaultAddress: "https://vault.consul.service",
+				token:        []byte("token"),
+				opts: []Option{
+					WithTlsMinVersion("tls10"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid-with-timeouts",
+			args: args{
+				scopeId:      scope.PublicId,
+				vaultAddress: "https://vault.consul.service",
+				token:        []byte("token"),
+				opts: []Option{
+					WithConnectTimeout(5 * time.Second),
+					WithRequestTimeout(90 * time.Second),
+				},
+			},
+			want: &CredentialStore{
+				inputToken: []byte("token"),
+				CredentialStore: &store.CredentialStore{
+					ScopeId:               scope.PublicId,
+					VaultAddress:          "https://vault.consul.service",
+					ConnectTimeoutSeconds: 5,
+					RequestTimeoutSeconds: 90,
+				},
+			},
+		},
+		{
+			name: "valid-with-requests-per-second",
+			args: args{
+				scopeId:      scope.PublicId,
+				vaultAddress: "https://vault.consul.service",
+				token:        []byte("token"),
+				opts: []Option{
+					WithRequestsPerSecond(25),
+				},
+			},
+			want: &CredentialStore{
+				inputToken: []byte("token"),
+				CredentialStore: &store.CredentialStore{
+					ScopeId:           scope.PublicId,
+					VaultAddress:      "https://vault.consul.service",
+					RequestsPerSecond: 25,
+				},
+			},
+		},
+		{
+			name: "negative-connect-timeout",
+			args: args{
+				scopeId:      scope.PublicId,
+				vaultAddress: "https://vault.consul.service",
+				token:        []byte("token"),
+				opts: []Option{
+					WithConnectTimeout(-time.Second),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sub-second-request-timeout",
+			args: args{
+				scopeId:      scope.PublicId,
+				vaultAddress: "https://vault.consul.service",
+				token:        []byte("token"),
+				opts: []Option{
+					WithRequestTimeout(500 * time.Millisecond),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid-with-client-cert",
 			args: args{
@@ -238,7 +334,7 @@ func TestCredentialStore_New(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			assert, require := assert.New(t), require.New(t)
-			got, err := NewCredentialStore(tt.args.scopeId, tt.args.vaultAddress, tt.args.token, tt.args.opts...)
+			got, err := NewCredentialStore(context.Background(), tt.args.scopeId, tt.args.vaultAddress, tt.args.token, tt.args.opts...)
 			if tt.wantErr {
 				assert.Error(err)
 				require.Nil(got)
@@ -266,3 +362,51 @@ func TestCredentialStore_New(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialStore_Warnings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		addr     string
+		opts     []Option
+		wantWarn []string
+	}{
+		{
+			name: "http-no-ca-cert",
+			addr: "http://127.0.0.1:8200",
+		},
+		{
+			name: "https-with-ca-cert",
+			addr: "https://127.0.0.1:8200",
+			opts: []Option{WithCACert([]byte("ca cert"))},
+		},
+		{
+			name:     "https-no-ca-cert",
+			addr:     "https://127.0.0.1:8200",
+			wantWarn: []string{"no CA certificate provided, the Vault server's certificate is verified with the system's trusted CA certificates"},
+		},
+		{
+			name:     "tls-skip-verify",
+			addr:     "https://127.0.0.1:8200",
+			opts:     []Option{WithCACert([]byte("ca cert")), WithTlsSkipVerify(true)},
+			wantWarn: []string{"TLS verification disabled"},
+		},
+		{
+			name: "tls-skip-verify-no-ca-cert",
+			addr: "HTTPS://127.0.0.1:8200",
+			opts: []Option{WithTlsSkipVerify(true)},
+			wantWarn: []string{
+				"TLS verification disabled",
+				"no CA certificate provided, the Vault server's certificate is verified with the system's trusted CA certificates",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := NewCredentialStore(context.Background(), "p_1234567890", tt.addr, []byte("token"), tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWarn, cs.Warnings())
+		})
+	}
+}
@@ -9,6 +9,12 @@ const (
 	httpMethodField      = "HttpMethod"
 	httpRequestBodyField = "HttpRequestBody"
 
+	credentialJsonPointerField = "CredentialJsonPointer"
+	templatedVaultPathField    = "TemplatedVaultPath"
+	credentialMappingsField    = "CredentialMappings"
+	wrapTtlField               = "WrapTtlSeconds"
+	contentTypeField           = "ContentType"
+
 	certificateField    = "Certificate"
 	certificateKeyField = "CertificateKey"
 	vaultAddressField   = "VaultAddress"
@@ -16,5 +22,20 @@ const (
 	caCertField         = "CaCert"
 	tlsServerNameField  = "TlsServerName"
 	tlsSkipVerifyField  = "TlsSkipVerify"
+	tlsMinVersionField  = "TlsMinVersion"
 	tokenField          = "Token"
+
+	connectTimeoutField = "ConnectTimeoutSeconds"
+	requestTimeoutField = "RequestTimeoutSeconds"
+	requestsPerSecField = "RequestsPerSecond"
+
+	appRoleRoleIdField   = "AppRoleRoleId"
+	appRoleSecretIdField = "AppRoleSecretId"
+	authMethodField      = "AuthMethod"
+
+	publicIdField   = "PublicId"
+	storeIdField    = "StoreId"
+	createTimeField = "CreateTime"
+	updateTimeField = "UpdateTime"
+	versionField    = "Version"
 )
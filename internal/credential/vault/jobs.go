@@ -1,6 +1,7 @@
 package vault
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"time"
@@ -27,37 +28,45 @@ const (
 	renewalWindow    = 10 * time.Minute
 )
 
-func RegisterJobs(ctx context.Context, scheduler *scheduler.Scheduler, r db.Reader, w db.Writer, kms *kms.Kms) error {
+// RegisterJobs registers the jobs of the vault package with scheduler.
+// WithTokenFileDirectory option is used to set the directory the token files
+// of credential stores must be in. WithRateLimiters option is used to share
+// the rate limiters of the credential stores with the repositories; without
+// it the jobs share their own.
+func RegisterJobs(ctx context.Context, scheduler *scheduler.Scheduler, r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) error {
 	const op = "vault.RegisterJobs"
-	tokenRenewal, err := newTokenRenewalJob(r, w, kms)
+	if getOpts(opt...).withRateLimiters == nil {
+		opt = append(opt, WithRateLimiters(NewRateLimiters(0, 0)))
+	}
+	tokenRenewal, err := newTokenRenewalJob(r, w, kms, opt...)
 	if err != nil {
 		return errors.Wrap(ctx, err, op)
 	}
 	if err = scheduler.RegisterJob(ctx, tokenRenewal); err != nil {
 		return errors.Wrap(ctx, err, op, errors.WithMsg("token renewal job"))
 	}
-	tokenRevoke, err := newTokenRevocationJob(r, w, kms)
+	tokenRevoke, err := newTokenRevocationJob(r, w, kms, opt...)
 	if err != nil {
 		return errors.Wrap(ctx, err, op)
 	}
 	if err = scheduler.RegisterJob(ctx, tokenRevoke); err != nil {
 		return errors.Wrap(ctx, err, op, errors.WithMsg("token revocation job"))
 	}
-	credRenewal, err := newCredentialRenewalJob(r, w, kms)
+	credRenewal, err := newCredentialRenewalJob(r, w, kms, opt...)
 	if err != nil {
 		return errors.Wrap(ctx, err, op)
 	}
 	if err = scheduler.RegisterJob(ctx, credRenewal); err != nil {
 		return errors.Wrap(ctx, err, op, errors.WithMsg("credential renewal job"))
 	}
-	credRevoke, err := newCredentialRevocationJob(r, w, kms)
+	credRevoke, err := newCredentialRevocationJob(r, w, kms, opt...)
 	if err != nil {
 		return errors.Wrap(ctx, err, op)
 	}
 	if err = scheduler.RegisterJob(ctx, credRevoke); err != nil {
 		return errors.Wrap(ctx, err, op, errors.WithMsg("credential revocation job"))
 	}
-	credStoreCleanup, err := newCredentialStoreCleanupJob(r, w, kms)
+	credStoreCleanup, err := newCredentialStoreCleanupJob(r, w, kms, opt...)
 	if err != nil {
 		return errors.Wrap(ctx, err, op)
 	}
@@ -82,6 +91,12 @@ type TokenRenewalJob struct {
 	writer db.Writer
 	kms    *kms.Kms
 	limit  int
+	// tokenFileDir is the directory the token files of credential
+	// stores must be in.
+	tokenFileDir string
+	// limiters holds the rate limiter of each credential store which the
+	// job's Vault clients wait on.
+	limiters *RateLimiters
 
 	running      ua.Bool
 	numTokens    int
@@ -90,7 +105,8 @@ type TokenRenewalJob struct {
 
 // newTokenRenewalJob creates a new in-memory TokenRenewalJob.
 //
-// WithLimit is the only supported option.
+// WithLimit, WithTokenFileDirectory and WithRateLimiters are the supported
+// options.
 func newTokenRenewalJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*TokenRenewalJob, error) {
 	const op = "vault.newTokenRenewalJob"
 	switch {
@@ -107,11 +123,16 @@ func newTokenRenewalJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (
 		// zero signals the boundary defaults should be used.
 		opts.withLimit = db.DefaultLimit
 	}
+	if opts.withRateLimiters == nil {
+		opts.withRateLimiters = NewRateLimiters(0, 0)
+	}
 	return &TokenRenewalJob{
-		reader: r,
-		writer: w,
-		kms:    kms,
-		limit:  opts.withLimit,
+		reader:       r,
+		writer:       w,
+		kms:          kms,
+		limit:        opts.withLimit,
+		tokenFileDir: opts.withTokenFileDirectory,
+		limiters:     opts.withRateLimiters,
 	}, nil
 }
 
@@ -181,14 +202,53 @@ func (r *TokenRenewalJob) renewToken(ctx context.Context, s *privateStore) error
 		return nil
 	}
 
-	vc, err := s.client()
+	vc, err := s.client(ctx, WithTokenFileDirectory(r.tokenFileDir), WithRateLimiters(r.limiters))
 	if err != nil {
 		return errors.Wrap(ctx, err, op)
 	}
 
-	var respErr *vault.ResponseError
-	renewedToken, err := vc.renewToken()
-	if ok := errors.As(err, &respErr); ok && respErr.StatusCode == http.StatusForbidden {
+	if s.TokenFilePath != "" {
+		// The client of a store which reads its token from a file always
+		// uses the token in the file, so only the current token is renewed.
+		if s.TokenStatus != string(CurrentToken) {
+			return nil
+		}
+		fileToken, err := readTokenFile(ctx, r.tokenFileDir, s.TokenFilePath)
+		if err != nil {
+			return errors.Wrap(ctx, err, op)
+		}
+		if !bytes.Equal(fileToken, s.Token) {
+			// The token in the file was rotated, such as by a Vault
+			// agent. The rotated token replaces the current token, which
+			// is moved to the maintaining state.
+			if err := r.replaceCurrentToken(ctx, s, vc, fileToken); err != nil {
+				return errors.Wrap(ctx, err, op)
+			}
+			event.WriteSysEvent(ctx, op, "Vault credential store token file was rotated, replaced its current token", "credential store id", s.StoreId)
+			return nil
+		}
+	}
+
+	renewedToken, err := vc.renewToken(ctx)
+	if AuthMethod(s.AuthMethod) == AppRoleAuthMethod && s.TokenStatus == string(CurrentToken) {
+		var renewable bool
+		if err == nil {
+			renewable, _ = renewedToken.TokenIsRenewable()
+		}
+		if !renewable {
+			// The current token of a store which logs in with an AppRole
+			// cannot be renewed, so log in again to replace it. The
+			// replaced token is moved to the maintaining state.
+			if err := r.loginWithAppRole(ctx, s, vc); err != nil {
+				return errors.Wrap(ctx, err, op)
+			}
+			if err != nil && !errors.Match(errors.T(errors.VaultTokenExpired), err) {
+				// The replaced token is revoked by the token revocation job.
+				return nil
+			}
+		}
+	}
+	if errors.Match(errors.T(errors.VaultTokenExpired), err) {
 		// Vault returned a 403 when attempting a renew self, the token is either expired
 		// or malformed.  Set status to "expired" so credentials created with token can be
 		// cleaned up.
@@ -220,8 +280,13 @@ func (r *TokenRenewalJob) renewToken(ctx context.Context, s *privateStore) error
 	if err != nil {
 		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault token expiration"))
 	}
+	renewable, err := renewedToken.TokenIsRenewable()
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault token renewable"))
+	}
 
 	token.expiration = tokenExpires
+	token.IsRenewable = renewable
 	query, values := token.updateExpirationQuery()
 	numRows, err := r.writer.Exec(ctx, query, values)
 	if err != nil {
@@ -234,6 +299,91 @@ func (r *TokenRenewalJob) renewToken(ctx context.Context, s *privateStore) error
 	return nil
 }
 
+// loginWithAppRole logs in to Vault with the AppRole of s to obtain a new
+// token for s. The new token replaces the current token of s.
+func (r *TokenRenewalJob) loginWithAppRole(ctx context.Context, s *privateStore, vc *client) error {
+	const op = "vault.(TokenRenewalJob).loginWithAppRole"
+	token, err := vc.appRoleLogin(ctx, s.AppRoleRoleId, s.AppRoleSecretId)
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to log in to vault with approle"))
+	}
+	if err := r.replaceCurrentToken(ctx, s, vc, token); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	event.WriteSysEvent(ctx, op, "Vault credential store logged in with approle to replace its current token", "credential store id", s.StoreId)
+	return nil
+}
+
+// replaceCurrentToken stores token as the new current token of s. The
+// current token of s is moved to the maintaining state, so it is revoked
+// once the credentials issued with it are no longer used. vc is changed to
+// use token.
+func (r *TokenRenewalJob) replaceCurrentToken(ctx context.Context, s *privateStore, vc *client, token TokenSecret) error {
+	const op = "vault.(TokenRenewalJob).replaceCurrentToken"
+	vc.swapToken(token)
+	renewedToken, err := vc.renewToken(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to renew vault token"))
+	}
+	tokenExpires, err := renewedToken.TokenTTL()
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault token expiration"))
+	}
+	accessor, err := renewedToken.TokenAccessor()
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault token accessor"))
+	}
+	renewable, err := renewedToken.TokenIsRenewable()
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault token renewable"))
+	}
+	newTk, err := newToken(s.StoreId, token, []byte(accessor), tokenExpires)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	newTk.IsRenewable = renewable
+	databaseWrapper, err := r.kms.GetWrapper(ctx, s.ScopeId, kms.KeyPurposeDatabase)
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get database wrapper"))
+	}
+	if err := newTk.encrypt(ctx, databaseWrapper); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, s.ScopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get oplog wrapper"))
+	}
+
+	cs := allocCredentialStore()
+	cs.PublicId = s.StoreId
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			ticket, err := w.GetTicket(cs)
+			if err != nil {
+				return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get ticket"))
+			}
+			query, values := newTk.insertQuery()
+			rows, err := w.Exec(ctx, query, values)
+			if err != nil {
+				return errors.Wrap(ctx, err, op)
+			}
+			if rows > 1 {
+				return errors.New(ctx, errors.MultipleRecords, op, "more than 1 token would have been created")
+			}
+			msgs := []*oplog.Message{newTk.oplogMessage(db.CreateOp)}
+			metadata := cs.oplog(oplog.OpType_OP_TYPE_UPDATE)
+			if err := w.WriteOplogEntryWith(ctx, oplogWrapper, ticket, metadata, msgs); err != nil {
+				return errors.Wrap(ctx, err, op, errors.WithMsg("unable to write oplog"))
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	return nil
+}
+
 // NextRunIn queries the vault credential repo to determine when the next token renewal job should run.
 func (r *TokenRenewalJob) NextRunIn() (time.Duration, error) {
 	const op = "vault.(TokenRenewalJob).NextRunIn"
@@ -304,6 +454,9 @@ type TokenRevocationJob struct {
 	writer db.Writer
 	kms    *kms.Kms
 	limit  int
+	// limiters holds the rate limiter of each credential store which the
+	// job's Vault clients wait on.
+	limiters *RateLimiters
 
 	running      ua.Bool
 	numTokens    int
@@ -312,7 +465,7 @@ type TokenRevocationJob struct {
 
 // newTokenRevocationJob creates a new in-memory TokenRevocationJob.
 //
-// WithLimit is the only supported option.
+// WithLimit and WithRateLimiters are the supported options.
 func newTokenRevocationJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*TokenRevocationJob, error) {
 	const op = "vault.newTokenRevocationJob"
 	switch {
@@ -329,11 +482,15 @@ func newTokenRevocationJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option
 		// zero signals the boundary defaults should be used.
 		opts.withLimit = db.DefaultLimit
 	}
+	if opts.withRateLimiters == nil {
+		opts.withRateLimiters = NewRateLimiters(0, 0)
+	}
 	return &TokenRevocationJob{
-		reader: r,
-		writer: w,
-		kms:    kms,
-		limit:  opts.withLimit,
+		reader:   r,
+		writer:   w,
+		kms:      kms,
+		limit:    opts.withLimit,
+		limiters: opts.withRateLimiters,
 	}, nil
 }
 
@@ -411,20 +568,24 @@ func (r *TokenRevocationJob) revokeToken(ctx context.Context, s *privateStore) e
 		return nil
 	}
 
-	vc, err := s.client()
-	if err != nil {
-		return errors.Wrap(ctx, err, op)
-	}
+	// The token of a store which reads its token from a file is owned by
+	// whatever writes the file, such as a Vault agent, so it is not
+	// revoked in Vault.
+	if s.TokenFilePath == "" {
+		vc, err := s.client(ctx, WithRateLimiters(r.limiters))
+		if err != nil {
+			return errors.Wrap(ctx, err, op)
+		}
 
-	var respErr *vault.ResponseError
-	err = vc.revokeToken()
-	if ok := errors.As(err, &respErr); ok && respErr.StatusCode == http.StatusForbidden {
-		// Vault returned a 403 when attempting a revoke self, the token is already expired.
-		// Clobber error and set status to "revoked" below.
-		err = nil
-	}
-	if err != nil {
-		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to revoke vault token"))
+		err = vc.revokeToken(ctx)
+		if errors.Match(errors.T(errors.VaultTokenExpired), err) {
+			// Vault returned a 403 when attempting a revoke self, the token is already expired.
+			// Clobber error and set status to "revoked" below.
+			err = nil
+		}
+		if err != nil {
+			return errors.Wrap(ctx, err, op, errors.WithMsg("unable to revoke vault token"))
+		}
 	}
 
 	query, values := token.updateStatusQuery(RevokedToken)
@@ -468,6 +629,12 @@ type CredentialRenewalJob struct {
 	writer db.Writer
 	kms    *kms.Kms
 	limit  int
+	// tokenFileDir is the directory the token files of credential
+	// stores must be in.
+	tokenFileDir string
+	// limiters holds the rate limiter of each credential store which the
+	// job's Vault clients wait on.
+	limiters *RateLimiters
 
 	running      ua.Bool
 	numCreds     int
@@ -476,7 +643,8 @@ type CredentialRenewalJob struct {
 
 // newCredentialRenewalJob creates a new in-memory CredentialRenewalJob.
 //
-// WithLimit is the only supported option.
+// WithLimit, WithTokenFileDirectory and WithRateLimiters are the supported
+// options.
 func newCredentialRenewalJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*CredentialRenewalJob, error) {
 	const op = "vault.newCredentialRenewalJob"
 	switch {
@@ -493,11 +661,16 @@ func newCredentialRenewalJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Opti
 		// zero signals the boundary defaults should be used.
 		opts.withLimit = db.DefaultLimit
 	}
+	if opts.withRateLimiters == nil {
+		opts.withRateLimiters = NewRateLimiters(0, 0)
+	}
 	return &CredentialRenewalJob{
-		reader: r,
-		writer: w,
-		kms:    kms,
-		limit:  opts.withLimit,
+		reader:       r,
+		writer:       w,
+		kms:          kms,
+		limit:        opts.withLimit,
+		tokenFileDir: opts.withTokenFileDirectory,
+		limiters:     opts.withRateLimiters,
 	}, nil
 }
 
@@ -562,7 +735,7 @@ func (r *CredentialRenewalJob) renewCred(ctx context.Context, c *privateCredenti
 		return errors.Wrap(ctx, err, op)
 	}
 
-	vc, err := c.client()
+	vc, err := c.client(ctx, WithTokenFileDirectory(r.tokenFileDir), WithRateLimiters(r.limiters))
 	if err != nil {
 		return errors.Wrap(ctx, err, op)
 	}
@@ -571,7 +744,7 @@ func (r *CredentialRenewalJob) renewCred(ctx context.Context, c *privateCredenti
 	var respErr *vault.ResponseError
 	// Subtract last renewal time from previous expiration time to get lease duration
 	leaseDuration := c.ExpirationTime.AsTime().Sub(c.LastRenewalTime.AsTime())
-	renewedCred, err := vc.renewLease(c.ExternalId, leaseDuration)
+	renewedCred, err := vc.renewLease(ctx, c.ExternalId, leaseDuration)
 	if ok := errors.As(err, &respErr); ok && respErr.StatusCode == http.StatusBadRequest {
 		// Vault returned a 400 when attempting a renew lease, the lease is either expired
 		// or the leaseId is malformed.  Set status to "expired".
@@ -632,6 +805,12 @@ type CredentialRevocationJob struct {
 	writer db.Writer
 	kms    *kms.Kms
 	limit  int
+	// tokenFileDir is the directory the token files of credential
+	// stores must be in.
+	tokenFileDir string
+	// limiters holds the rate limiter of each credential store which the
+	// job's Vault clients wait on.
+	limiters *RateLimiters
 
 	running      ua.Bool
 	numCreds     int
@@ -640,7 +819,8 @@ type CredentialRevocationJob struct {
 
 // newCredentialRevocationJob creates a new in-memory CredentialRevocationJob.
 //
-// WithLimit is the only supported option.
+// WithLimit, WithTokenFileDirectory and WithRateLimiters are the supported
+// options.
 func newCredentialRevocationJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*CredentialRevocationJob, error) {
 	const op = "vault.newCredentialRevocationJob"
 	switch {
@@ -657,11 +837,16 @@ func newCredentialRevocationJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...O
 		// zero signals the boundary defaults should be used.
 		opts.withLimit = db.DefaultLimit
 	}
+	if opts.withRateLimiters == nil {
+		opts.withRateLimiters = NewRateLimiters(0, 0)
+	}
 	return &CredentialRevocationJob{
-		reader: r,
-		writer: w,
-		kms:    kms,
-		limit:  opts.withLimit,
+		reader:       r,
+		writer:       w,
+		kms:          kms,
+		limit:        opts.withLimit,
+		tokenFileDir: opts.withTokenFileDirectory,
+		limiters:     opts.withRateLimiters,
 	}, nil
 }
 
@@ -721,14 +906,14 @@ func (r *CredentialRevocationJob) revokeCred(ctx context.Context, c *privateCred
 		return errors.Wrap(ctx, err, op)
 	}
 
-	vc, err := c.client()
+	vc, err := c.client(ctx, WithTokenFileDirectory(r.tokenFileDir), WithRateLimiters(r.limiters))
 	if err != nil {
 		return errors.Wrap(ctx, err, op)
 	}
 
 	cred := c.toCredential()
 	var respErr *vault.ResponseError
-	err = vc.revokeLease(c.ExternalId)
+	err = vc.revokeLease(ctx, c.ExternalId)
 	if ok := errors.As(err, &respErr); ok && respErr.StatusCode == http.StatusBadRequest {
 		// Vault returned a 400 when attempting a revoke lease, the lease is already expired.
 		// Clobber error and set status to "revoked" below.
@@ -773,6 +958,9 @@ type CredentialStoreCleanupJob struct {
 	reader db.Reader
 	writer db.Writer
 	kms    *kms.Kms
+	// limiters holds the rate limiter of each credential store which the
+	// job's Vault clients wait on.
+	limiters *RateLimiters
 
 	limit        int
 	running      ua.Bool
@@ -782,7 +970,7 @@ type CredentialStoreCleanupJob struct {
 
 // newCredentialStoreCleanupJob creates a new in-memory CredentialStoreCleanupJob.
 //
-// No options are supported.
+// WithLimit and WithRateLimiters are the supported options.
 func newCredentialStoreCleanupJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ...Option) (*CredentialStoreCleanupJob, error) {
 	const op = "vault.newCredentialStoreCleanupJob"
 	switch {
@@ -799,11 +987,15 @@ func newCredentialStoreCleanupJob(r db.Reader, w db.Writer, kms *kms.Kms, opt ..
 		// zero signals the boundary defaults should be used.
 		opts.withLimit = db.DefaultLimit
 	}
+	if opts.withRateLimiters == nil {
+		opts.withRateLimiters = NewRateLimiters(0, 0)
+	}
 	return &CredentialStoreCleanupJob{
-		reader: r,
-		writer: w,
-		kms:    kms,
-		limit:  opts.withLimit,
+		reader:   r,
+		writer:   w,
+		kms:      kms,
+		limit:    opts.withLimit,
+		limiters: opts.withRateLimiters,
 	}, nil
 }
 
@@ -858,6 +1050,9 @@ func (r *CredentialStoreCleanupJob) Run(ctx context.Context) error {
 		if err != nil {
 			event.WriteError(ctx, op, err, event.WithInfoMsg("error deleting credential store", "credential store id", store.PublicId))
 		}
+		// The token revocation job may have added a rate limiter for the
+		// store after it was soft deleted.
+		r.limiters.remove(store.PublicId)
 
 		r.numProcessed++
 	}
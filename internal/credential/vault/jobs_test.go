@@ -1,9 +1,12 @@
 package vault
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"io/ioutil"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -102,9 +105,9 @@ func testVaultCred(t *testing.T,
 	var err error
 	switch Method(cl.HttpMethod) {
 	case MethodGet:
-		secret, err = client.get(cl.VaultPath)
+		secret, err = client.get(context.Background(), cl.VaultPath)
 	case MethodPost:
-		secret, err = client.post(cl.VaultPath, cl.HttpRequestBody)
+		secret, err = client.post(context.Background(), cl.VaultPath, cl.HttpRequestBody, cl.ContentType)
 	}
 	require.NoError(err)
 	require.NotNil(secret)
@@ -270,10 +273,10 @@ func TestTokenRenewalJob_RunLimits(t *testing.T) {
 			assert, require := assert.New(t), require.New(t)
 
 			_, token := v.CreateToken(t)
-			in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+			in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 			require.NoError(err)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kmsCache, sche)
+			repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 			require.NoError(err)
 			cs, err := repo.CreateCredentialStore(context.Background(), in)
 			require.NoError(err)
@@ -326,10 +329,10 @@ func TestTokenRenewalJob_Run(t *testing.T) {
 	// Create 24 hour token
 	_, token := v.CreateToken(t, WithTokenPeriod(24*time.Hour))
 
-	in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 	require.NoError(err)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 	cs, err := repo.CreateCredentialStore(context.Background(), in)
 	require.NoError(err)
@@ -436,7 +439,7 @@ func TestTokenRenewalJob_RunExpired(t *testing.T) {
 	// Create 1s token so it expires in vault before we can renew it
 	_, ct := v.CreateToken(t, WithTokenPeriod(time.Second))
 
-	in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(ct))
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(ct))
 	assert.NoError(err)
 	require.NotNil(in)
 
@@ -446,7 +449,7 @@ func TestTokenRenewalJob_RunExpired(t *testing.T) {
 	err = sche.RegisterJob(context.Background(), r)
 	require.NoError(err)
 
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 	cs, err := repo.CreateCredentialStore(context.Background(), in)
 	require.NoError(err)
@@ -465,6 +468,124 @@ func TestTokenRenewalJob_RunExpired(t *testing.T) {
 	assert.Equal(string(ExpiredToken), token.Status)
 }
 
+func TestTokenRenewalJob_RunAppRoleLogin(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	v := NewTestVaultServer(t)
+
+	// Log in with a 1s token so it expires in vault before we can renew it
+	roleId, secretId := v.EnableAppRole(t, WithTokenPeriod(time.Second))
+	appRole, err := NewAppRole(context.Background(), roleId, SecretIdSecret(secretId))
+	require.NoError(err)
+
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, nil, WithAppRole(appRole))
+	assert.NoError(err)
+	require.NotNil(in)
+
+	r, err := newTokenRenewalJob(rw, rw, kmsCache)
+	require.NoError(err)
+
+	err = sche.RegisterJob(context.Background(), r)
+	require.NoError(err)
+
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
+	require.NoError(err)
+	cs, err := repo.CreateCredentialStore(context.Background(), in)
+	require.NoError(err)
+	origHmac := cs.Token().GetTokenHmac()
+
+	// Sleep to move clock and expire token
+	time.Sleep(time.Second * 2)
+
+	// Token should have expired in vault, run should log in with the
+	// approle to replace it
+	err = r.Run(context.Background())
+	require.NoError(err)
+	assert.Equal(1, r.numTokens)
+
+	var tokens []*Token
+	require.NoError(rw.SearchWhere(context.Background(), &tokens, "store_id = ?", []interface{}{cs.GetPublicId()}))
+	require.Len(tokens, 2)
+	for _, tk := range tokens {
+		if bytes.Equal(origHmac, tk.GetTokenHmac()) {
+			assert.Equal(string(ExpiredToken), tk.Status)
+			continue
+		}
+		assert.Equal(string(CurrentToken), tk.Status)
+	}
+}
+
+func TestTokenRenewalJob_RunRotatedTokenFile(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	v := NewTestVaultServer(t)
+
+	_, token := v.CreateToken(t)
+	tokenDir := t.TempDir()
+	tokenFile := filepath.Join(tokenDir, "token")
+	require.NoError(ioutil.WriteFile(tokenFile, []byte(token), 0o600))
+
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, nil, WithTokenFile(tokenFile))
+	assert.NoError(err)
+	require.NotNil(in)
+
+	r, err := newTokenRenewalJob(rw, rw, kmsCache, WithTokenFileDirectory(tokenDir))
+	require.NoError(err)
+
+	err = sche.RegisterJob(context.Background(), r)
+	require.NoError(err)
+
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche, WithTokenFileDirectory(tokenDir))
+	require.NoError(err)
+	cs, err := repo.CreateCredentialStore(context.Background(), in)
+	require.NoError(err)
+	origHmac := cs.Token().GetTokenHmac()
+
+	// Run with the same token in the file should only renew the token
+	err = r.Run(context.Background())
+	require.NoError(err)
+	assert.Equal(1, r.numTokens)
+
+	var tokens []*Token
+	require.NoError(rw.SearchWhere(context.Background(), &tokens, "store_id = ?", []interface{}{cs.GetPublicId()}))
+	require.Len(tokens, 1)
+
+	// Rotate the token in the file, run should replace the current token
+	_, rotated := v.CreateToken(t)
+	require.NoError(ioutil.WriteFile(tokenFile, []byte(rotated), 0o600))
+	err = r.Run(context.Background())
+	require.NoError(err)
+
+	tokens = nil
+	require.NoError(rw.SearchWhere(context.Background(), &tokens, "store_id = ?", []interface{}{cs.GetPublicId()}))
+	require.Len(tokens, 2)
+	for _, tk := range tokens {
+		if bytes.Equal(origHmac, tk.GetTokenHmac()) {
+			assert.Equal(string(MaintainingToken), tk.Status)
+			continue
+		}
+		assert.Equal(string(CurrentToken), tk.Status)
+	}
+
+	ps, err := repo.lookupPrivateStore(context.Background(), cs.GetPublicId())
+	require.NoError(err)
+	assert.Equal(TokenSecret(rotated), ps.Token)
+}
+
 func TestTokenRenewalJob_NextRunIn(t *testing.T) {
 	t.Parallel()
 
@@ -559,10 +680,10 @@ func TestTokenRenewalJob_NextRunIn(t *testing.T) {
 
 			if !tt.skipCredStore {
 				_, token := v.CreateToken(t)
-				in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+				in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 				require.NoError(err)
 				sche := scheduler.TestScheduler(t, conn, wrapper)
-				repo, err := NewRepository(rw, rw, kmsCache, sche)
+				repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 				require.NoError(err)
 				cs, err := repo.CreateCredentialStore(context.Background(), in)
 				require.NoError(err)
@@ -716,10 +837,10 @@ func TestTokenRevocationJob_RunLimits(t *testing.T) {
 			assert, require := assert.New(t), require.New(t)
 
 			_, token := v.CreateToken(t)
-			in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+			in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 			require.NoError(err)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kmsCache, sche)
+			repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 			require.NoError(err)
 			cs, err := repo.CreateCredentialStore(context.Background(), in)
 			require.NoError(err)
@@ -771,10 +892,10 @@ func TestTokenRevocationJob_Run(t *testing.T) {
 	v.MountDatabase(t)
 
 	_, ct := v.CreateToken(t)
-	in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(ct))
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(ct))
 	require.NoError(err)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 	cs, err := repo.CreateCredentialStore(context.Background(), in)
 	require.NoError(err)
@@ -802,7 +923,7 @@ func TestTokenRevocationJob_Run(t *testing.T) {
 
 	// Create cred lib and session for credential
 	libPath := path.Join("database", "creds", "opened")
-	cl, err := NewCredentialLibrary(cs.PublicId, libPath, WithMethod(MethodGet))
+	cl, err := NewCredentialLibrary(context.Background(), cs.PublicId, libPath, WithMethod(MethodGet))
 	require.NoError(err)
 	cl.PublicId, err = newCredentialLibraryId()
 	require.NoError(err)
@@ -986,16 +1107,16 @@ func TestCredentialRenewalJob_RunLimits(t *testing.T) {
 	v := NewTestVaultServer(t, WithDockerNetwork(true))
 	v.MountDatabase(t)
 	_, ct := v.CreateToken(t)
-	in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(ct))
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(ct))
 	require.NoError(t, err)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(t, err)
 	cs, err := repo.CreateCredentialStore(context.Background(), in)
 	require.NoError(t, err)
 
 	libPath := path.Join("database", "creds", "opened")
-	cl, err := NewCredentialLibrary(cs.PublicId, libPath, WithMethod(MethodGet))
+	cl, err := NewCredentialLibrary(context.Background(), cs.PublicId, libPath, WithMethod(MethodGet))
 	require.NoError(t, err)
 	cl.PublicId, err = newCredentialLibraryId()
 	require.NoError(t, err)
@@ -1096,17 +1217,17 @@ func TestCredentialRenewalJob_Run(t *testing.T) {
 	org, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	kmsCache := kms.TestKms(t, conn, wrapper)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 
 	_, token := v.CreateToken(t, WithPolicies([]string{"default", "boundary-controller", "database"}))
-	credStoreIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+	credStoreIn, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 	require.NoError(err)
 	cs, err := repo.CreateCredentialStore(context.Background(), credStoreIn)
 	require.NoError(err)
 
 	libPath := path.Join("database", "creds", "opened")
-	libIn, err := NewCredentialLibrary(cs.GetPublicId(), libPath)
+	libIn, err := NewCredentialLibrary(context.Background(), cs.GetPublicId(), libPath)
 	require.NoError(err)
 	cl, err := repo.CreateCredentialLibrary(context.Background(), prj.GetPublicId(), libIn)
 	require.NoError(err)
@@ -1202,17 +1323,17 @@ func TestCredentialRenewalJob_RunExpired(t *testing.T) {
 	org, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	kmsCache := kms.TestKms(t, conn, wrapper)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 
 	_, token := v.CreateToken(t, WithPolicies([]string{"default", "boundary-controller", "database"}))
-	credStoreIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+	credStoreIn, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 	require.NoError(err)
 	cs, err := repo.CreateCredentialStore(context.Background(), credStoreIn)
 	require.NoError(err)
 
 	libPath := path.Join("database", "creds", "opened")
-	libIn, err := NewCredentialLibrary(cs.GetPublicId(), libPath)
+	libIn, err := NewCredentialLibrary(context.Background(), cs.GetPublicId(), libPath)
 	require.NoError(err)
 	cl, err := repo.CreateCredentialLibrary(context.Background(), prj.GetPublicId(), libIn)
 	require.NoError(err)
@@ -1276,16 +1397,16 @@ func TestCredentialRenewalJob_NextRunIn(t *testing.T) {
 	v.MountDatabase(t)
 
 	_, ct := v.CreateToken(t)
-	in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(ct))
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(ct))
 	require.NoError(t, err)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(t, err)
 	cs, err := repo.CreateCredentialStore(context.Background(), in)
 	require.NoError(t, err)
 
 	libPath := path.Join("database", "creds", "opened")
-	libIn, err := NewCredentialLibrary(cs.GetPublicId(), libPath)
+	libIn, err := NewCredentialLibrary(context.Background(), cs.GetPublicId(), libPath)
 	require.NoError(t, err)
 	cl, err := repo.CreateCredentialLibrary(context.Background(), prj.GetPublicId(), libIn)
 	require.NoError(t, err)
@@ -1513,16 +1634,16 @@ func TestCredentialRevocationJob_RunLimits(t *testing.T) {
 	v := NewTestVaultServer(t, WithDockerNetwork(true))
 	v.MountDatabase(t)
 	_, ct := v.CreateToken(t, WithPolicies([]string{"default", "boundary-controller", "database"}))
-	in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(ct))
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(ct))
 	require.NoError(t, err)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(t, err)
 	cs, err := repo.CreateCredentialStore(context.Background(), in)
 	require.NoError(t, err)
 
 	libPath := path.Join("database", "creds", "opened")
-	cl, err := NewCredentialLibrary(cs.PublicId, libPath, WithMethod(MethodGet))
+	cl, err := NewCredentialLibrary(context.Background(), cs.PublicId, libPath, WithMethod(MethodGet))
 	require.NoError(t, err)
 	cl.PublicId, err = newCredentialLibraryId()
 	require.NoError(t, err)
@@ -1625,17 +1746,17 @@ func TestCredentialRevocationJob_Run(t *testing.T) {
 	org, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	kmsCache := kms.TestKms(t, conn, wrapper)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 
 	_, token := v.CreateToken(t, WithPolicies([]string{"default", "boundary-controller", "database"}))
-	credStoreIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+	credStoreIn, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 	require.NoError(err)
 	cs, err := repo.CreateCredentialStore(context.Background(), credStoreIn)
 	require.NoError(err)
 
 	libPath := path.Join("database", "creds", "opened")
-	libIn, err := NewCredentialLibrary(cs.GetPublicId(), libPath)
+	libIn, err := NewCredentialLibrary(context.Background(), cs.GetPublicId(), libPath)
 	require.NoError(err)
 	cl, err := repo.CreateCredentialLibrary(context.Background(), prj.GetPublicId(), libIn)
 	require.NoError(err)
@@ -1715,17 +1836,17 @@ func TestCredentialRevocationJob_RunDeleted(t *testing.T) {
 	org, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	kmsCache := kms.TestKms(t, conn, wrapper)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 
 	_, token := v.CreateToken(t, WithPolicies([]string{"default", "boundary-controller", "database"}))
-	credStoreIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+	credStoreIn, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 	require.NoError(err)
 	cs, err := repo.CreateCredentialStore(context.Background(), credStoreIn)
 	require.NoError(err)
 
 	libPath := path.Join("database", "creds", "opened")
-	libIn, err := NewCredentialLibrary(cs.GetPublicId(), libPath)
+	libIn, err := NewCredentialLibrary(context.Background(), cs.GetPublicId(), libPath)
 	require.NoError(err)
 	cl, err := repo.CreateCredentialLibrary(context.Background(), prj.GetPublicId(), libIn)
 	require.NoError(err)
@@ -1903,16 +2024,16 @@ func TestCredentialStoreCleanupJob_Run(t *testing.T) {
 	v := NewTestVaultServer(t)
 
 	_, ct := v.CreateToken(t)
-	in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(ct))
+	in, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(ct))
 	require.NoError(err)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 	cs1, err := repo.CreateCredentialStore(context.Background(), in)
 	require.NoError(err)
 
 	_, ct = v.CreateToken(t)
-	in, err = NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(ct))
+	in, err = NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(ct))
 	require.NoError(err)
 	cs2, err := repo.CreateCredentialStore(context.Background(), in)
 	require.NoError(err)
@@ -2093,17 +2214,17 @@ func TestCredentialCleanupJob_Run(t *testing.T) {
 	org, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	kmsCache := kms.TestKms(t, conn, wrapper)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kmsCache, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
 	require.NoError(err)
 
 	_, token := v.CreateToken(t, WithPolicies([]string{"default", "boundary-controller", "database"}))
-	credStoreIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token))
+	credStoreIn, err := NewCredentialStore(context.Background(), prj.GetPublicId(), v.Addr, []byte(token))
 	require.NoError(err)
 	cs, err := repo.CreateCredentialStore(context.Background(), credStoreIn)
 	require.NoError(err)
 
 	libPath := path.Join("database", "creds", "opened")
-	libIn, err := NewCredentialLibrary(cs.GetPublicId(), libPath)
+	libIn, err := NewCredentialLibrary(context.Background(), cs.GetPublicId(), libPath)
 	require.NoError(err)
 	cl, err := repo.CreateCredentialLibrary(context.Background(), prj.GetPublicId(), libIn)
 	require.NoError(err)
@@ -0,0 +1,72 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// parseJsonPointer parses p as an RFC 6901 JSON pointer and returns its
+// unescaped reference tokens. p must be non-empty and begin with a '/'.
+// An errors.InvalidParameter error is returned if p is not a valid JSON
+// pointer.
+func parseJsonPointer(ctx context.Context, p string) ([]string, error) {
+	const op = "vault.parseJsonPointer"
+	if p == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "empty json pointer")
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("json pointer %q must begin with '/'", p))
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		for j := 0; j < len(t); j++ {
+			if t[j] != '~' {
+				continue
+			}
+			if j+1 == len(t) || (t[j+1] != '0' && t[j+1] != '1') {
+				return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("json pointer %q contains an invalid escape sequence", p))
+			}
+		}
+		// ~1 must be unescaped before ~0, see RFC 6901 section 4.
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// resolveJsonPointer returns the value in data referenced by the JSON
+// pointer p. data must be composed of the types produced by decoding JSON
+// into an interface{}.
+func resolveJsonPointer(ctx context.Context, p string, data interface{}) (interface{}, error) {
+	const op = "vault.resolveJsonPointer"
+	tokens, err := parseJsonPointer(ctx, p)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	cur := data
+	for _, t := range tokens {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			next, ok := v[t]
+			if !ok {
+				return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("json pointer %q: key %q not found", p, t))
+			}
+			cur = next
+		case []interface{}:
+			if t == "" || (len(t) > 1 && t[0] == '0') {
+				return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("json pointer %q: invalid array index %q", p, t))
+			}
+			i, err := strconv.Atoi(t)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("json pointer %q: invalid array index %q", p, t))
+			}
+			cur = v[i]
+		default:
+			return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("json pointer %q: cannot reference %q in a scalar value", p, t))
+		}
+	}
+	return cur, nil
+}
@@ -0,0 +1,130 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseJsonPointer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "/data", want: []string{"data"}},
+		{in: "/data/foo/bar", want: []string{"data", "foo", "bar"}},
+		{in: "/", want: []string{""}},
+		{in: "/a~1b/m~0n", want: []string{"a/b", "m~n"}},
+		{in: "/~01", want: []string{"~1"}},
+		{in: "", wantErr: true},
+		{in: "data/foo", wantErr: true},
+		{in: "/data~", wantErr: true},
+		{in: "/data~2", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			assert := assert.New(t)
+			got, err := parseJsonPointer(context.Background(), tt.in)
+			if tt.wantErr {
+				assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+				assert.Nil(got)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func Test_resolveJsonPointer(t *testing.T) {
+	t.Parallel()
+	// A fake Vault response for a secret with nested data.
+	const response = `{
+		"data": {
+			"foo": {
+				"bar": {
+					"username": "user",
+					"password": "pass"
+				},
+				"keys": ["k0", "k1"]
+			},
+			"a/b": "slash"
+		}
+	}`
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(response), &data))
+
+	tests := []struct {
+		name    string
+		p       string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "nested-object",
+			p:    "/data/foo/bar",
+			want: map[string]interface{}{"username": "user", "password": "pass"},
+		},
+		{
+			name: "nested-string",
+			p:    "/data/foo/bar/password",
+			want: "pass",
+		},
+		{
+			name: "array-index",
+			p:    "/data/foo/keys/1",
+			want: "k1",
+		},
+		{
+			name: "escaped-key",
+			p:    "/data/a~1b",
+			want: "slash",
+		},
+		{
+			name:    "missing-key",
+			p:       "/data/foo/baz",
+			wantErr: true,
+		},
+		{
+			name:    "index-out-of-range",
+			p:       "/data/foo/keys/2",
+			wantErr: true,
+		},
+		{
+			name:    "index-leading-zero",
+			p:       "/data/foo/keys/01",
+			wantErr: true,
+		},
+		{
+			name:    "past-scalar",
+			p:       "/data/foo/bar/password/x",
+			wantErr: true,
+		},
+		{
+			name:    "invalid-pointer",
+			p:       "data/foo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got, err := resolveJsonPointer(context.Background(), tt.p, data)
+			if tt.wantErr {
+				assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+				assert.Nil(got)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package vault
 
+import "time"
+
 // getOpts - iterate the inbound Options and return a struct
 func getOpts(opt ...Option) options {
 	opts := getDefaultOptions()
@@ -14,16 +16,60 @@ type Option func(*options)
 
 // options = how options are represented
 type options struct {
-	withName          string
-	withDescription   string
-	withLimit         int
-	withCACert        []byte
-	withNamespace     string
-	withTlsServerName string
-	withTlsSkipVerify bool
-	withClientCert    *ClientCertificate
-	withMethod        Method
-	withRequestBody   []byte
+	withName              string
+	withDescription       string
+	withLimit             int
+	withMaxLimit          int
+	withCACert            []byte
+	withNamespace         string
+	withTlsServerName     string
+	withTlsSkipVerify     bool
+	withTlsMinVersion     string
+	withConnectTimeout    time.Duration
+	withRequestTimeout    time.Duration
+	withRequestsPerSecond uint32
+	withClientCert        *ClientCertificate
+	withAppRole           *AppRole
+	withMethod            Method
+	withRequestBody       []byte
+
+	withCredentialJsonPointer string
+	withTemplatedVaultPath    bool
+	withCredentialMappings    []*CredentialMapping
+	withWrapTtl               time.Duration
+	withTemplate              bool
+
+	withDefaultHttpMethod  Method
+	withPreflightNameCheck bool
+	withPkiBodyValidation  bool
+	withStoreType          string
+
+	withHealthCheckWorkers int
+	withHealthCheckTimeout time.Duration
+
+	withRateLimiters *RateLimiters
+
+	withSkipOplog      bool
+	withAllowSkipOplog bool
+
+	withMaxUpdateAttempts int
+
+	withTimingObservations bool
+
+	withCreatedAfter  time.Time
+	withCreatedBefore time.Time
+	withUpdatedAfter  time.Time
+	withUpdatedBefore time.Time
+
+	withFilter     string
+	withStrongRead bool
+
+	withLiveRequest bool
+
+	withContentType string
+
+	withTokenFile          string
+	withTokenFileDirectory string
 }
 
 func getDefaultOptions() options {
@@ -53,6 +99,15 @@ func WithLimit(l int) Option {
 	}
 }
 
+// WithMaxLimit provides an option to cap the number of results returned by
+// the ListX methods of a Repository. The cap also applies when WithLimit < 0
+// requests unlimited results. If WithMaxLimit <= 0, results are not capped.
+func WithMaxLimit(l int) Option {
+	return func(o *options) {
+		o.withMaxLimit = l
+	}
+}
+
 // WithCACert provides an optional PEM-encoded certificate
 // to verify the Vault server's SSL certificate.
 func WithCACert(cert []byte) Option {
@@ -61,7 +116,9 @@ func WithCACert(cert []byte) Option {
 	}
 }
 
-// WithNamespace provides an optional Vault namespace.
+// WithNamespace provides an optional Vault namespace. For a credential
+// library, the namespace is used in place of the namespace of its
+// credential store.
 func WithNamespace(namespace string) Option {
 	return func(o *options) {
 		o.withNamespace = namespace
@@ -86,6 +143,42 @@ func WithTlsSkipVerify(skipVerify bool) Option {
 	}
 }
 
+// WithTlsMinVersion provides an optional minimum TLS version to use when
+// connecting to Vault. It must be TlsVersion12 or TlsVersion13. If not
+// set, TLS 1.2 is the minimum version.
+func WithTlsMinVersion(version string) Option {
+	return func(o *options) {
+		o.withTlsMinVersion = version
+	}
+}
+
+// WithConnectTimeout provides an optional maximum duration to wait for a
+// connection to the Vault server to be established. The duration is
+// stored in whole seconds.
+func WithConnectTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.withConnectTimeout = d
+	}
+}
+
+// WithRequestTimeout provides an optional maximum duration to wait for a
+// request to the Vault server to complete. The duration is stored in whole
+// seconds.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.withRequestTimeout = d
+	}
+}
+
+// WithRequestsPerSecond provides an optional maximum rate of requests to
+// the Vault server for a credential store. If zero, requests are not rate
+// limited.
+func WithRequestsPerSecond(rps uint32) Option {
+	return func(o *options) {
+		o.withRequestsPerSecond = rps
+	}
+}
+
 // WithClientCert provides an optional ClientCertificate to use for TLS
 // authentication to a Vault server.
 func WithClientCert(clientCert *ClientCertificate) Option {
@@ -94,6 +187,14 @@ func WithClientCert(clientCert *ClientCertificate) Option {
 	}
 }
 
+// WithAppRole provides an optional AppRole a credential store uses to log
+// in to a Vault server and obtain its Vault token.
+func WithAppRole(appRole *AppRole) Option {
+	return func(o *options) {
+		o.withAppRole = appRole
+	}
+}
+
 // WithMethod provides an optional Method to use for communicating with
 // Vault.
 func WithMethod(m Method) Option {
@@ -103,9 +204,242 @@ func WithMethod(m Method) Option {
 }
 
 // WithRequestBody provides an optional request body for sending to Vault
-// when requesting credentials using HTTP Post.
+// when requesting credentials using HTTP POST, PUT, or PATCH.
 func WithRequestBody(b []byte) Option {
 	return func(o *options) {
 		o.withRequestBody = b
 	}
 }
+
+// WithCredentialJsonPointer provides an optional RFC 6901 JSON pointer
+// which selects the credential from the data of a Vault response.
+func WithCredentialJsonPointer(p string) Option {
+	return func(o *options) {
+		o.withCredentialJsonPointer = p
+	}
+}
+
+// WithTemplatedVaultPath provides an option to render the vault path of a
+// credential library as a template with the values of the session a
+// credential is issued for. The {{.Username}} and {{.Target}} variables are
+// supported.
+func WithTemplatedVaultPath(b bool) Option {
+	return func(o *options) {
+		o.withTemplatedVaultPath = b
+	}
+}
+
+// WithCredentialMappings provides optional mappings which map the data of
+// a Vault response to multiple typed credentials.
+func WithCredentialMappings(m ...*CredentialMapping) Option {
+	return func(o *options) {
+		o.withCredentialMappings = m
+	}
+}
+
+// WithWrapTtl provides an optional TTL of the response-wrapping token
+// Vault returns in place of the response to a credential library's
+// request. It must be at least one second.
+func WithWrapTtl(d time.Duration) Option {
+	return func(o *options) {
+		o.withWrapTtl = d
+	}
+}
+
+// WithTemplate provides an option to create a credential library as a
+// template for CreateCredentialLibraryFromTemplate. A template is never
+// used to issue credentials and cannot be added to a target. When listing
+// credential libraries, it provides an option to list only templates.
+func WithTemplate(b bool) Option {
+	return func(o *options) {
+		o.withTemplate = b
+	}
+}
+
+// WithDefaultHttpMethod provides an optional Method a Repository applies to
+// credential libraries when an HTTP method is not specified on create or is
+// deleted on update. If not provided, DefaultHttpMethod is used.
+func WithDefaultHttpMethod(m Method) Option {
+	return func(o *options) {
+		o.withDefaultHttpMethod = m
+	}
+}
+
+// WithPreflightNameCheck provides an option to check for an existing
+// credential library with the same name in the credential store before
+// attempting to create a new credential library. The database unique
+// constraint remains the authority on name uniqueness.
+func WithPreflightNameCheck(check bool) Option {
+	return func(o *options) {
+		o.withPreflightNameCheck = check
+	}
+}
+
+// WithPkiBodyValidation provides an option to validate that the
+// HttpRequestBody of a credential library is a well-formed request for a
+// Vault PKI issue or sign endpoint.
+func WithPkiBodyValidation(validate bool) Option {
+	return func(o *options) {
+		o.withPkiBodyValidation = validate
+	}
+}
+
+// WithStoreType provides an option to only list credential stores, or
+// credential libraries in credential stores, of the provided type.
+func WithStoreType(t string) Option {
+	return func(o *options) {
+		o.withStoreType = t
+	}
+}
+
+// WithHealthCheckWorkers provides an option to set the maximum number of
+// credential stores checked concurrently by CredentialStoresHealth.
+func WithHealthCheckWorkers(n int) Option {
+	return func(o *options) {
+		o.withHealthCheckWorkers = n
+	}
+}
+
+// WithHealthCheckTimeout provides an option to set the maximum duration of
+// the check of each credential store by CredentialStoresHealth.
+func WithHealthCheckTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.withHealthCheckTimeout = d
+	}
+}
+
+// WithRateLimiters provides an option to set the rate limiters the Vault
+// clients of credential stores wait on before each request to Vault.
+func WithRateLimiters(l *RateLimiters) Option {
+	return func(o *options) {
+		o.withRateLimiters = l
+	}
+}
+
+// WithSkipOplog provides an option to create or delete a credential library
+// without writing an oplog entry. Skipping the oplog is dangerous: the
+// change cannot be replicated or audited from the oplog. It is only
+// intended for controlled migrations and bulk imports, and for ephemeral
+// libraries such as scratch data created by tooling. It is rejected unless
+// the Repository was created with WithAllowSkipOplog. By default an oplog
+// entry is always written.
+func WithSkipOplog(skip bool) Option {
+	return func(o *options) {
+		o.withSkipOplog = skip
+	}
+}
+
+// WithAllowSkipOplog provides an option to allow the WithSkipOplog option
+// to be used with a Repository. It should never be set for a Repository
+// used by the controller's API handlers.
+func WithAllowSkipOplog(allow bool) Option {
+	return func(o *options) {
+		o.withAllowSkipOplog = allow
+	}
+}
+
+// WithMaxUpdateAttempts provides an option to set the maximum number of
+// times a repository update is attempted when it fails with a transaction
+// conflict. If zero or less, the default of 3 attempts is used.
+func WithMaxUpdateAttempts(n int) Option {
+	return func(o *options) {
+		o.withMaxUpdateAttempts = n
+	}
+}
+
+// WithTimingObservations provides an option to write an observation event
+// with the elapsed duration of each of a Repository's create, update,
+// lookup, delete, and list operations.
+func WithTimingObservations(enabled bool) Option {
+	return func(o *options) {
+		o.withTimingObservations = enabled
+	}
+}
+
+// WithCreatedAfter provides an option to only list the resources created
+// after t.
+func WithCreatedAfter(t time.Time) Option {
+	return func(o *options) {
+		o.withCreatedAfter = t
+	}
+}
+
+// WithCreatedBefore provides an option to only list the resources created
+// before t.
+func WithCreatedBefore(t time.Time) Option {
+	return func(o *options) {
+		o.withCreatedBefore = t
+	}
+}
+
+// WithUpdatedAfter provides an option to only list the resources updated
+// after t.
+func WithUpdatedAfter(t time.Time) Option {
+	return func(o *options) {
+		o.withUpdatedAfter = t
+	}
+}
+
+// WithUpdatedBefore provides an option to only list the resources updated
+// before t.
+func WithUpdatedBefore(t time.Time) Option {
+	return func(o *options) {
+		o.withUpdatedBefore = t
+	}
+}
+
+// WithFilter provides an option to only list the resources which match the
+// filter expression f. See filter.NewEvaluator for the syntax of f.
+func WithFilter(f string) Option {
+	return func(o *options) {
+		o.withFilter = f
+	}
+}
+
+// WithStrongRead provides an option for the list and lookup methods to
+// read from the repository's db.Writer instead of its db.Reader. It
+// guarantees a read sees the writes made with the repository, even if the
+// db.Reader is a replica which lags behind the primary.
+func WithStrongRead(strong bool) Option {
+	return func(o *options) {
+		o.withStrongRead = strong
+	}
+}
+
+// WithLiveRequest provides an option to allow a Repository method to make a
+// live request to Vault. Methods which require it return an error unless it
+// is set.
+func WithLiveRequest(allow bool) Option {
+	return func(o *options) {
+		o.withLiveRequest = allow
+	}
+}
+
+// WithContentType provides an optional Content-Type of the request body a
+// credential library sends to Vault with the POST and PUT methods. It must
+// be ContentTypeJson or ContentTypeForm.
+func WithContentType(ct string) Option {
+	return func(o *options) {
+		o.withContentType = ct
+	}
+}
+
+// WithTokenFile provides an optional path of a file, such as the token sink
+// of a Vault agent, from which a credential store reads its Vault token
+// before each request to Vault. The file must be readable by the
+// controller.
+func WithTokenFile(path string) Option {
+	return func(o *options) {
+		o.withTokenFile = path
+	}
+}
+
+// WithTokenFileDirectory provides an option to set the directory credential
+// stores can read their token files from. A token file must be in the
+// directory or one of its subdirectories. If it's not set, credential
+// stores cannot read their token from a file.
+func WithTokenFileDirectory(dir string) Option {
+	return func(o *options) {
+		o.withTokenFileDirectory = dir
+	}
+}
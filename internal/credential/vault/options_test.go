@@ -1,7 +1,9 @@
 package vault
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -51,6 +53,30 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withTlsSkipVerify = true
 		assert.Equal(t, opts, testOpts)
 	})
+	t.Run("WithTlsMinVersion", func(t *testing.T) {
+		opts := getOpts(WithTlsMinVersion(TlsVersion13))
+		testOpts := getDefaultOptions()
+		testOpts.withTlsMinVersion = TlsVersion13
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithConnectTimeout", func(t *testing.T) {
+		opts := getOpts(WithConnectTimeout(5 * time.Second))
+		testOpts := getDefaultOptions()
+		testOpts.withConnectTimeout = 5 * time.Second
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithRequestsPerSecond", func(t *testing.T) {
+		opts := getOpts(WithRequestsPerSecond(10))
+		testOpts := getDefaultOptions()
+		testOpts.withRequestsPerSecond = 10
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithRequestTimeout", func(t *testing.T) {
+		opts := getOpts(WithRequestTimeout(30 * time.Second))
+		testOpts := getDefaultOptions()
+		testOpts.withRequestTimeout = 30 * time.Second
+		assert.Equal(t, opts, testOpts)
+	})
 	t.Run("WithClientCert", func(t *testing.T) {
 		testOpts := getDefaultOptions()
 		assert.Nil(t, testOpts.withClientCert)
@@ -65,6 +91,93 @@ func Test_GetOpts(t *testing.T) {
 		assert.Equal(t, cert, opts.withClientCert.Certificate)
 		assert.Equal(t, key, opts.withClientCert.CertificateKey)
 	})
+	t.Run("WithStoreType", func(t *testing.T) {
+		opts := getOpts(WithStoreType("vault"))
+		testOpts := getDefaultOptions()
+		testOpts.withStoreType = "vault"
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithHealthCheckWorkers", func(t *testing.T) {
+		opts := getOpts(WithHealthCheckWorkers(3))
+		testOpts := getDefaultOptions()
+		testOpts.withHealthCheckWorkers = 3
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithHealthCheckTimeout", func(t *testing.T) {
+		opts := getOpts(WithHealthCheckTimeout(time.Second))
+		testOpts := getDefaultOptions()
+		testOpts.withHealthCheckTimeout = time.Second
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithRateLimiters", func(t *testing.T) {
+		l := NewRateLimiters(2.5, 5)
+		opts := getOpts(WithRateLimiters(l))
+		testOpts := getDefaultOptions()
+		testOpts.withRateLimiters = l
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithCredentialMappings", func(t *testing.T) {
+		m := &CredentialMapping{
+			Name:            "db",
+			Type:            UserPasswordCredentialType,
+			UsernamePointer: "/username",
+			PasswordPointer: "/password",
+		}
+		opts := getOpts(WithCredentialMappings(m))
+		testOpts := getDefaultOptions()
+		testOpts.withCredentialMappings = []*CredentialMapping{m}
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithWrapTtl", func(t *testing.T) {
+		opts := getOpts(WithWrapTtl(time.Minute))
+		testOpts := getDefaultOptions()
+		testOpts.withWrapTtl = time.Minute
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithSkipOplog", func(t *testing.T) {
+		opts := getOpts(WithSkipOplog(true))
+		testOpts := getDefaultOptions()
+		testOpts.withSkipOplog = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithAllowSkipOplog", func(t *testing.T) {
+		opts := getOpts(WithAllowSkipOplog(true))
+		testOpts := getDefaultOptions()
+		testOpts.withAllowSkipOplog = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithCredentialJsonPointer", func(t *testing.T) {
+		opts := getOpts(WithCredentialJsonPointer("/data/foo"))
+		testOpts := getDefaultOptions()
+		testOpts.withCredentialJsonPointer = "/data/foo"
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithAppRole", func(t *testing.T) {
+		appRole, err := NewAppRole(context.Background(), "role-id", SecretIdSecret("secret-id"))
+		require.NoError(t, err)
+		opts := getOpts(WithAppRole(appRole))
+		testOpts := getDefaultOptions()
+		testOpts.withAppRole = appRole
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithTemplatedVaultPath", func(t *testing.T) {
+		opts := getOpts(WithTemplatedVaultPath(true))
+		testOpts := getDefaultOptions()
+		testOpts.withTemplatedVaultPath = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithTemplate", func(t *testing.T) {
+		opts := getOpts(WithTemplate(true))
+		testOpts := getDefaultOptions()
+		testOpts.withTemplate = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithMaxLimit", func(t *testing.T) {
+		opts := getOpts(WithMaxLimit(5))
+		testOpts := getDefaultOptions()
+		testOpts.withMaxLimit = 5
+		assert.Equal(t, opts, testOpts)
+	})
 	t.Run("WithMethod_Get", func(t *testing.T) {
 		opts := getOpts(WithMethod(MethodGet))
 		testOpts := getDefaultOptions()
@@ -77,10 +190,91 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withMethod = MethodPost
 		assert.Equal(t, opts, testOpts)
 	})
+	t.Run("WithDefaultHttpMethod", func(t *testing.T) {
+		opts := getOpts(WithDefaultHttpMethod(MethodPost))
+		testOpts := getDefaultOptions()
+		testOpts.withDefaultHttpMethod = MethodPost
+		assert.Equal(t, opts, testOpts)
+	})
 	t.Run("WithRequestBody", func(t *testing.T) {
 		opts := getOpts(WithRequestBody([]byte("body")))
 		testOpts := getDefaultOptions()
 		testOpts.withRequestBody = []byte("body")
 		assert.Equal(t, opts, testOpts)
 	})
+	t.Run("WithPreflightNameCheck", func(t *testing.T) {
+		opts := getOpts(WithPreflightNameCheck(true))
+		testOpts := getDefaultOptions()
+		testOpts.withPreflightNameCheck = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithPkiBodyValidation", func(t *testing.T) {
+		opts := getOpts(WithPkiBodyValidation(true))
+		testOpts := getDefaultOptions()
+		testOpts.withPkiBodyValidation = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithMaxUpdateAttempts", func(t *testing.T) {
+		opts := getOpts(WithMaxUpdateAttempts(5))
+		testOpts := getDefaultOptions()
+		testOpts.withMaxUpdateAttempts = 5
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithTimeRanges", func(t *testing.T) {
+		now := time.Now()
+		opts := getOpts(
+			WithCreatedAfter(now.Add(-2*time.Hour)),
+			WithCreatedBefore(now.Add(-time.Hour)),
+			WithUpdatedAfter(now.Add(-time.Minute)),
+			WithUpdatedBefore(now),
+		)
+		testOpts := getDefaultOptions()
+		testOpts.withCreatedAfter = now.Add(-2 * time.Hour)
+		testOpts.withCreatedBefore = now.Add(-time.Hour)
+		testOpts.withUpdatedAfter = now.Add(-time.Minute)
+		testOpts.withUpdatedBefore = now
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithFilter", func(t *testing.T) {
+		opts := getOpts(WithFilter(`"/item/name" == "x"`))
+		testOpts := getDefaultOptions()
+		testOpts.withFilter = `"/item/name" == "x"`
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithTimingObservations", func(t *testing.T) {
+		opts := getOpts(WithTimingObservations(true))
+		testOpts := getDefaultOptions()
+		testOpts.withTimingObservations = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithStrongRead", func(t *testing.T) {
+		opts := getOpts(WithStrongRead(true))
+		testOpts := getDefaultOptions()
+		testOpts.withStrongRead = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithLiveRequest", func(t *testing.T) {
+		opts := getOpts(WithLiveRequest(true))
+		testOpts := getDefaultOptions()
+		testOpts.withLiveRequest = true
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithContentType", func(t *testing.T) {
+		opts := getOpts(WithContentType(ContentTypeForm))
+		testOpts := getDefaultOptions()
+		testOpts.withContentType = ContentTypeForm
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithTokenFile", func(t *testing.T) {
+		opts := getOpts(WithTokenFile("/var/run/vault/token"))
+		testOpts := getDefaultOptions()
+		testOpts.withTokenFile = "/var/run/vault/token"
+		assert.Equal(t, opts, testOpts)
+	})
+	t.Run("WithTokenFileDirectory", func(t *testing.T) {
+		opts := getOpts(WithTokenFileDirectory("/var/run/boundary"))
+		testOpts := getDefaultOptions()
+		testOpts.withTokenFileDirectory = "/var/run/boundary"
+		assert.Equal(t, opts, testOpts)
+	})
 }
@@ -0,0 +1,27 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// validatePkiRequestBody returns an errors.InvalidParameter error if body
+// is not a JSON object with a non-empty common_name string field. The Vault
+// PKI issue and sign endpoints reject requests without a common_name.
+func validatePkiRequestBody(ctx context.Context, body []byte) error {
+	const op = "vault.validatePkiRequestBody"
+	if len(body) == 0 {
+		return errors.New(ctx, errors.InvalidParameter, op, "missing pki request body")
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithCode(errors.InvalidParameter), errors.WithMsg("pki request body is not a JSON object"))
+	}
+	cn, ok := fields["common_name"].(string)
+	if !ok || cn == "" {
+		return errors.New(ctx, errors.InvalidParameter, op, "pki request body missing common_name")
+	}
+	return nil
+}
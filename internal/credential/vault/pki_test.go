@@ -0,0 +1,68 @@
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validatePkiRequestBody(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			body: `{"common_name":"boundary.example.com"}`,
+		},
+		{
+			name: "valid-with-extra-fields",
+			body: `{"common_name":"boundary.example.com","ttl":"1h","alt_names":"a.example.com"}`,
+		},
+		{
+			name:    "empty",
+			wantErr: true,
+		},
+		{
+			name:    "not-json",
+			body:    "common_name=boundary.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "not-an-object",
+			body:    `["boundary.example.com"]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing-common-name",
+			body:    `{"ttl":"1h"}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty-common-name",
+			body:    `{"common_name":""}`,
+			wantErr: true,
+		},
+		{
+			name:    "common-name-not-a-string",
+			body:    `{"common_name":42}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			err := validatePkiRequestBody(context.Background(), []byte(tt.body))
+			if tt.wantErr {
+				assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
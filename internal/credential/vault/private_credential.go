@@ -2,6 +2,7 @@ package vault
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/credential/vault/store"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
@@ -44,6 +45,14 @@ type privateCredential struct {
 	CtClientKey          []byte
 	ClientKeyHmac        []byte
 	ClientKeyId          string
+
+	ConnectTimeoutSeconds uint32
+	RequestTimeoutSeconds uint32
+	StoreId               string
+	RequestsPerSecond     uint32
+	TlsMinVersion         string
+
+	TokenFilePath string
 }
 
 func (pc *privateCredential) decrypt(ctx context.Context, cipher wrapping.Wrapper) error {
@@ -78,15 +87,30 @@ func (pc *privateCredential) decrypt(ctx context.Context, cipher wrapping.Wrappe
 	return nil
 }
 
-func (pc *privateCredential) client() (*client, error) {
+// client returns a Vault client using the store of pc. Use
+// WithTokenFileDirectory if the store reads its token from a file and
+// WithRateLimiters to rate limit its requests.
+func (pc *privateCredential) client(ctx context.Context, opt ...Option) (*client, error) {
 	const op = "vault.(privateCredential).client"
+	opts := getOpts(opt...)
 	clientConfig := &clientConfig{
 		Addr:          pc.VaultAddress,
 		Token:         pc.Token,
 		CaCert:        pc.CaCert,
 		TlsServerName: pc.TlsServerName,
 		TlsSkipVerify: pc.TlsSkipVerify,
+		TlsMinVersion: pc.TlsMinVersion,
 		Namespace:     pc.Namespace,
+
+		ConnectTimeout: time.Duration(pc.ConnectTimeoutSeconds) * time.Second,
+		RequestTimeout: time.Duration(pc.RequestTimeoutSeconds) * time.Second,
+
+		StoreId:           pc.StoreId,
+		RequestsPerSecond: pc.RequestsPerSecond,
+		Limiters:          opts.withRateLimiters,
+
+		TokenFile:    pc.TokenFilePath,
+		TokenFileDir: opts.withTokenFileDirectory,
 	}
 
 	if pc.ClientKey != nil {
@@ -94,9 +118,9 @@ func (pc *privateCredential) client() (*client, error) {
 		clientConfig.ClientKey = pc.ClientKey
 	}
 
-	client, err := newClient(clientConfig)
+	client, err := newClient(ctx, clientConfig)
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithMsg("unable to create vault client"))
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to create vault client"))
 	}
 	return client, nil
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/credential"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
@@ -21,7 +22,7 @@ type actualCredential struct {
 	id         string
 	sessionId  string
 	lib        *privateLibrary
-	secretData map[string]interface{}
+	secretData credential.SecretData
 	purpose    credential.Purpose
 }
 
@@ -59,6 +60,20 @@ type privateLibrary struct {
 	CtClientKey     []byte
 	ClientKeyId     string
 	Purpose         credential.Purpose `gorm:"-"`
+
+	CredentialJsonPointer string
+	TemplatedVaultPath    bool
+	CredentialMappings    []byte
+
+	ConnectTimeoutSeconds uint32
+	RequestTimeoutSeconds uint32
+	RequestsPerSecond     uint32
+	TlsMinVersion         string
+
+	WrapTtlSeconds uint32
+	ContentType    string
+
+	TokenFilePath string
 }
 
 func (pl *privateLibrary) clone() *privateLibrary {
@@ -90,6 +105,20 @@ func (pl *privateLibrary) clone() *privateLibrary {
 		CtClientKey:     append(pl.CtClientKey[:0:0], pl.CtClientKey...),
 		ClientKeyId:     pl.ClientKeyId,
 		Purpose:         pl.Purpose,
+
+		CredentialJsonPointer: pl.CredentialJsonPointer,
+		TemplatedVaultPath:    pl.TemplatedVaultPath,
+		CredentialMappings:    append(pl.CredentialMappings[:0:0], pl.CredentialMappings...),
+
+		ConnectTimeoutSeconds: pl.ConnectTimeoutSeconds,
+		RequestTimeoutSeconds: pl.RequestTimeoutSeconds,
+		RequestsPerSecond:     pl.RequestsPerSecond,
+		TlsMinVersion:         pl.TlsMinVersion,
+
+		WrapTtlSeconds: pl.WrapTtlSeconds,
+		ContentType:    pl.ContentType,
+
+		TokenFilePath: pl.TokenFilePath,
 	}
 }
 
@@ -134,15 +163,31 @@ func (pl *privateLibrary) decrypt(ctx context.Context, cipher wrapping.Wrapper)
 	return nil
 }
 
-func (pl *privateLibrary) client() (*client, error) {
+// client returns a Vault client using the store of pl. Use
+// WithTokenFileDirectory if the store reads its token from a file, and
+// WithRateLimiters to share the store's rate limiter.
+func (pl *privateLibrary) client(ctx context.Context, opt ...Option) (*client, error) {
 	const op = "vault.(privateLibrary).client"
+	opts := getOpts(opt...)
 	clientConfig := &clientConfig{
 		Addr:          pl.VaultAddress,
 		Token:         pl.Token,
 		CaCert:        pl.CaCert,
 		TlsServerName: pl.TlsServerName,
 		TlsSkipVerify: pl.TlsSkipVerify,
+		TlsMinVersion: pl.TlsMinVersion,
 		Namespace:     pl.Namespace,
+
+		ConnectTimeout: time.Duration(pl.ConnectTimeoutSeconds) * time.Second,
+		RequestTimeout: time.Duration(pl.RequestTimeoutSeconds) * time.Second,
+		WrapTtl:        time.Duration(pl.WrapTtlSeconds) * time.Second,
+
+		StoreId:           pl.StoreId,
+		RequestsPerSecond: pl.RequestsPerSecond,
+		Limiters:          opts.withRateLimiters,
+
+		TokenFile:    pl.TokenFilePath,
+		TokenFileDir: opts.withTokenFileDirectory,
 	}
 
 	if pl.ClientKey != nil {
@@ -150,9 +195,9 @@ func (pl *privateLibrary) client() (*client, error) {
 		clientConfig.ClientKey = pl.ClientKey
 	}
 
-	client, err := newClient(clientConfig)
+	client, err := newClient(ctx, clientConfig)
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithMsg("unable to create vault client"))
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to create vault client"))
 	}
 	return client, nil
 }
@@ -2,6 +2,9 @@ package vault
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/hashicorp/boundary/internal/credential"
@@ -45,7 +48,7 @@ func TestRepository_getPrivateLibraries(t *testing.T) {
 			kms := kms.TestKms(t, conn, wrapper)
 
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			require.NoError(err)
 			require.NotNil(repo)
 
@@ -62,7 +65,7 @@ func TestRepository_getPrivateLibraries(t *testing.T) {
 
 			_, token := v.CreateToken(t)
 
-			credStoreIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token), opts...)
+			credStoreIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(token), opts...)
 			assert.NoError(err)
 			require.NotNil(credStoreIn)
 			origStore, err := repo.CreateCredentialStore(ctx, credStoreIn)
@@ -78,7 +81,7 @@ func TestRepository_getPrivateLibraries(t *testing.T) {
 			libs := make(map[string]*CredentialLibrary, 3)
 			var requests []credential.Request
 			{
-				libIn, err := NewCredentialLibrary(origStore.GetPublicId(), "/vault/path")
+				libIn, err := NewCredentialLibrary(ctx, origStore.GetPublicId(), "/vault/path")
 				assert.NoError(err)
 				require.NotNil(libIn)
 				lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), libIn)
@@ -89,7 +92,7 @@ func TestRepository_getPrivateLibraries(t *testing.T) {
 				requests = append(requests, req)
 			}
 			{
-				libIn, err := NewCredentialLibrary(origStore.GetPublicId(), "/vault/path", WithMethod(MethodPost))
+				libIn, err := NewCredentialLibrary(ctx, origStore.GetPublicId(), "/vault/path", WithMethod(MethodPost))
 				assert.NoError(err)
 				require.NotNil(libIn)
 				lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), libIn)
@@ -100,7 +103,7 @@ func TestRepository_getPrivateLibraries(t *testing.T) {
 				requests = append(requests, req)
 			}
 			{
-				libIn, err := NewCredentialLibrary(origStore.GetPublicId(), "/vault/path", WithMethod(MethodPost), WithRequestBody([]byte(`{"common_name":"boundary.com"}`)))
+				libIn, err := NewCredentialLibrary(ctx, origStore.GetPublicId(), "/vault/path", WithMethod(MethodPost), WithRequestBody([]byte(`{"common_name":"boundary.com"}`)))
 				assert.NoError(err)
 				require.NotNil(libIn)
 				lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), libIn)
@@ -134,6 +137,68 @@ func TestRepository_getPrivateLibraries(t *testing.T) {
 	}
 }
 
+func TestRepository_getPrivateLibraries_Namespace(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+	assert, require := assert.New(t), require.New(t)
+
+	// a fake Vault server which records the namespace of each request
+	var gotNamespace string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotNamespace = r.Header.Get("X-Vault-Namespace")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(err)
+	require.NotNil(repo)
+
+	cs := TestCredentialStore(t, conn, wrapper, prj.GetPublicId(), srv.URL, "token", "accessor", WithNamespace("store-ns"))
+
+	libIn, err := NewCredentialLibrary(ctx, cs.GetPublicId(), "/vault/path")
+	require.NoError(err)
+	storeNsLib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), libIn)
+	require.NoError(err)
+	libIn, err = NewCredentialLibrary(ctx, cs.GetPublicId(), "/vault/path", WithNamespace("library-ns"))
+	require.NoError(err)
+	libNsLib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), libIn)
+	require.NoError(err)
+	assert.Equal("library-ns", libNsLib.GetNamespace())
+
+	effectiveNamespace := func(libraryId string) string {
+		t.Helper()
+		requests := []credential.Request{{SourceId: libraryId, Purpose: credential.ApplicationPurpose}}
+		gotLibs, err := repo.getPrivateLibraries(ctx, requests)
+		require.NoError(err)
+		require.Len(gotLibs, 1)
+		client, err := gotLibs[0].client(ctx)
+		require.NoError(err)
+		_, err = client.get(context.Background(), gotLibs[0].VaultPath)
+		require.NoError(err)
+		assert.Equal(gotLibs[0].Namespace, gotNamespace)
+		return gotNamespace
+	}
+
+	assert.Equal("store-ns", effectiveNamespace(storeNsLib.GetPublicId()))
+	assert.Equal("library-ns", effectiveNamespace(libNsLib.GetPublicId()))
+
+	// clearing the namespace of the library reverts to the namespace of
+	// the store
+	libNsLib.Namespace = ""
+	updated, _, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), libNsLib, libNsLib.GetVersion(), []string{namespaceField})
+	require.NoError(err)
+	assert.Empty(updated.GetNamespace())
+	assert.Equal("store-ns", effectiveNamespace(libNsLib.GetPublicId()))
+}
+
 func TestRequestMap(t *testing.T) {
 	type args struct {
 		requests []credential.Request
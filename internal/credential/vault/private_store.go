@@ -3,6 +3,7 @@ package vault
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
@@ -94,6 +95,20 @@ type privateStore struct {
 	ClientKey            KeySecret
 	CtClientKey          []byte
 	ClientCertKeyHmac    []byte
+	AppRoleRoleId        string
+	AppRoleSecretId      SecretIdSecret
+	CtAppRoleSecretId    []byte
+	AppRoleSecretIdHmac  []byte
+	AppRoleKeyId         string
+
+	ConnectTimeoutSeconds uint32
+	RequestTimeoutSeconds uint32
+	RequestsPerSecond     uint32
+	TlsMinVersion         string
+
+	TokenFilePath    string
+	AuthMethod       string
+	TokenIsRenewable bool
 }
 
 func allocPrivateStore() *privateStore {
@@ -115,6 +130,12 @@ func (ps *privateStore) toCredentialStore() *CredentialStore {
 	cs.CaCert = ps.CaCert
 	cs.TlsServerName = ps.TlsServerName
 	cs.TlsSkipVerify = ps.TlsSkipVerify
+	cs.ConnectTimeoutSeconds = ps.ConnectTimeoutSeconds
+	cs.RequestTimeoutSeconds = ps.RequestTimeoutSeconds
+	cs.RequestsPerSecond = ps.RequestsPerSecond
+	cs.TlsMinVersion = ps.TlsMinVersion
+	cs.TokenFilePath = ps.TokenFilePath
+	cs.CredentialStore.AuthMethod = ps.AuthMethod
 	cs.privateToken = ps.token()
 	if ps.ClientCert != nil {
 		cert := allocClientCertificate()
@@ -140,6 +161,7 @@ func (ps *privateStore) token() *Token {
 		tk.CtToken = ps.CtToken
 		tk.KeyId = ps.TokenKeyId
 		tk.Status = ps.TokenStatus
+		tk.IsRenewable = ps.TokenIsRenewable
 
 		return tk
 	}
@@ -177,18 +199,47 @@ func (ps *privateStore) decrypt(ctx context.Context, cipher wrapping.Wrapper) er
 		}
 		ps.ClientKey = pckv.Key
 	}
+
+	if ps.CtAppRoleSecretId != nil {
+		type psi struct {
+			SecretId   []byte `wrapping:"pt,secret_id_data"`
+			CtSecretId []byte `wrapping:"ct,secret_id_data"`
+		}
+		psiv := &psi{
+			CtSecretId: ps.CtAppRoleSecretId,
+		}
+		if err := structwrapping.UnwrapStruct(ctx, cipher, psiv, nil); err != nil {
+			return errors.Wrap(ctx, err, op, errors.WithCode(errors.Decrypt), errors.WithMsg("approle secret id"))
+		}
+		ps.AppRoleSecretId = psiv.SecretId
+	}
 	return nil
 }
 
-func (ps *privateStore) client() (*client, error) {
+// client returns a Vault client for ps. Use WithTokenFileDirectory if ps
+// reads its token from a file. Requests are rate limited with the
+// limiters of WithRateLimiters.
+func (ps *privateStore) client(ctx context.Context, opt ...Option) (*client, error) {
 	const op = "vault.(privateStore).client"
+	opts := getOpts(opt...)
 	clientConfig := &clientConfig{
 		Addr:          ps.VaultAddress,
 		Token:         ps.Token,
 		CaCert:        ps.CaCert,
 		TlsServerName: ps.TlsServerName,
 		TlsSkipVerify: ps.TlsSkipVerify,
+		TlsMinVersion: ps.TlsMinVersion,
 		Namespace:     ps.Namespace,
+
+		ConnectTimeout: time.Duration(ps.ConnectTimeoutSeconds) * time.Second,
+		RequestTimeout: time.Duration(ps.RequestTimeoutSeconds) * time.Second,
+
+		StoreId:           ps.PublicId,
+		RequestsPerSecond: ps.RequestsPerSecond,
+		Limiters:          opts.withRateLimiters,
+
+		TokenFile:    ps.TokenFilePath,
+		TokenFileDir: opts.withTokenFileDirectory,
 	}
 
 	if ps.ClientKey != nil {
@@ -196,9 +247,9 @@ func (ps *privateStore) client() (*client, error) {
 		clientConfig.ClientKey = ps.ClientKey
 	}
 
-	client, err := newClient(clientConfig)
+	client, err := newClient(ctx, clientConfig)
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithMsg("unable to create vault client"))
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to create vault client"))
 	}
 	return client, nil
 }
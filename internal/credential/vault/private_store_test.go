@@ -41,7 +41,7 @@ func TestRepository_lookupPrivateStore(t *testing.T) {
 			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			require.NoError(err)
 			require.NotNil(repo)
 			_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
@@ -61,7 +61,7 @@ func TestRepository_lookupPrivateStore(t *testing.T) {
 
 			_, token := v.CreateToken(t)
 
-			credStoreIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token), opts...)
+			credStoreIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(token), opts...)
 			assert.NoError(err)
 			require.NotNil(credStoreIn)
 			orig, err := repo.CreateCredentialStore(ctx, credStoreIn)
@@ -9,7 +9,8 @@ insert into credential_vault_token (
   key_id, -- $4
   status, -- $5
   last_renewal_time, -- $6
-  expiration_time -- $7
+  expiration_time, -- $7
+  is_renewable -- $8
 ) values (
   @1, -- token_hmac
   @2, -- token
@@ -17,7 +18,8 @@ insert into credential_vault_token (
   @4, -- key_id
   @5, -- status
   @6, -- last_renewal_time
-  wt_add_seconds_to_now(@7)  -- expiration_time
+  wt_add_seconds_to_now(@7),  -- expiration_time
+  @8 -- is_renewable
 );
 `
 
@@ -82,6 +84,24 @@ on conflict (store_id) do update
 returning *;
 `
 
+	upsertAppRoleQuery = `
+insert into credential_vault_approle
+  (store_id, role_id, secret_id, secret_id_hmac, key_id)
+values
+  (@store_id, @role_id, @secret_id, @secret_id_hmac, @key_id)
+on conflict (store_id) do update
+  set role_id        = excluded.role_id,
+      secret_id      = excluded.secret_id,
+      secret_id_hmac = excluded.secret_id_hmac,
+      key_id         = excluded.key_id
+returning *;
+`
+
+	deleteAppRoleQuery = `
+delete from credential_vault_approle
+ where store_id = ?;
+`
+
 	deleteClientCertQuery = `
 delete from credential_vault_client_certificate
  where store_id = ?;
@@ -106,7 +126,8 @@ returning *;
 	updateTokenExpirationQuery = `
 update credential_vault_token
    set last_renewal_time = now(),
-       expiration_time   = wt_add_seconds_to_now(?)
+       expiration_time   = wt_add_seconds_to_now(?),
+       is_renewable      = ?
  where token_hmac = ?;
 `
 
@@ -181,6 +202,32 @@ delete_time is not null
    )
 `
 
+	scopeDefaultNamespaceQuery = `
+select namespace
+  from credential_vault_scope_default
+ where scope_id = @scope_id;
+`
+
+	listLibraryIdsQuery = `
+select public_id
+  from credential_vault_library
+ where store_id = @store_id
+   and not is_template
+ order by public_id
+ limit @limit; -- a null limit returns all rows
+`
+
+	sessionVaultPathDataQuery = `
+select coalesce(u.name, u.public_id) as username,
+       t.name                        as target
+  from session s
+  left join iam_user u
+         on s.user_id = u.public_id
+  left join target_all_subtypes t
+         on s.target_id = t.public_id
+ where s.public_id = @session_id;
+`
+
 	credCleanupQuery = `
 delete from credential_vault_credential 
  where session_id is null
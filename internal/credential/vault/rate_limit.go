@@ -0,0 +1,104 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitWait is the maximum duration a request to Vault waits for
+// the rate limiter of its credential store before it fails with an
+// errors.VaultRateLimited error.
+const maxRateLimitWait = 5 * time.Second
+
+// RateLimiters holds the token bucket rate limiter of each credential
+// store. A Vault client is created for each operation on a credential
+// store, so every client created with the same RateLimiters waits on the
+// same limiter for a store. A single RateLimiters should be shared by the
+// repositories and jobs of a controller.
+type RateLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+
+	// defaultLimit and defaultBurst are the rate limit of the credential
+	// stores which do not have a RequestsPerSecond. The requests of those
+	// stores are not rate limited if defaultLimit is zero.
+	defaultLimit rate.Limit
+	defaultBurst int
+}
+
+// NewRateLimiters creates a new RateLimiters. The requests to Vault of a
+// credential store with a RequestsPerSecond are limited to that rate. The
+// requests of a credential store without one are limited to
+// requestsPerSecond requests per second with bursts of up to burst
+// requests. If requestsPerSecond is zero, those requests are not rate
+// limited. If burst is less than one, a burst of one request is used.
+func NewRateLimiters(requestsPerSecond float64, burst int) *RateLimiters {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiters{
+		limiters:     make(map[string]*rate.Limiter),
+		defaultLimit: rate.Limit(requestsPerSecond),
+		defaultBurst: burst,
+	}
+}
+
+// get returns the rate limiter for storeId. requestsPerSecond is the
+// RequestsPerSecond of the store. The limit and burst of an existing
+// limiter are updated if they have changed. If the store's requests are
+// not rate limited, the limiter for storeId is removed and nil is
+// returned.
+func (l *RateLimiters) get(storeId string, requestsPerSecond uint32) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit, burst := rate.Limit(requestsPerSecond), 1
+	if requestsPerSecond == 0 {
+		limit, burst = l.defaultLimit, l.defaultBurst
+	}
+	if limit <= 0 {
+		delete(l.limiters, storeId)
+		return nil
+	}
+	lim, ok := l.limiters[storeId]
+	if !ok {
+		lim = rate.NewLimiter(limit, burst)
+		l.limiters[storeId] = lim
+		return lim
+	}
+	if lim.Limit() != limit {
+		lim.SetLimit(limit)
+	}
+	if lim.Burst() != burst {
+		lim.SetBurst(burst)
+	}
+	return lim
+}
+
+// remove removes the rate limiter for storeId.
+func (l *RateLimiters) remove(storeId string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.limiters, storeId)
+}
+
+// waitLimiter blocks until the rate limiter of c allows a request to
+// Vault. An errors.VaultRateLimited error is returned if the request
+// would have to wait longer than maxRateLimitWait or past the deadline of
+// ctx.
+func (c *client) waitLimiter(ctx context.Context) error {
+	const op = "vault.(client).waitLimiter"
+	if c.limiter == nil {
+		return nil
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, c.maxLimiterWait)
+	defer cancel()
+	if err := c.limiter.Wait(waitCtx); err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithCode(errors.VaultRateLimited), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiters_get(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	l := NewRateLimiters(0, 0)
+
+	assert.Nil(l.get("csvlt_unlimited", 0))
+	assert.Empty(l.limiters)
+
+	lim := l.get("csvlt_limited", 10)
+	assert.NotNil(lim)
+	assert.Equal(rate.Limit(10), lim.Limit())
+	assert.Equal(1, lim.Burst())
+	assert.Same(lim, l.get("csvlt_limited", 10), "limiter should be shared by the clients of a store")
+
+	assert.Same(lim, l.get("csvlt_limited", 20), "limiter should be updated in place")
+	assert.Equal(rate.Limit(20), lim.Limit())
+
+	assert.Nil(l.get("csvlt_limited", 0))
+	assert.Empty(l.limiters)
+
+	assert.NotNil(l.get("csvlt_deleted", 10))
+	l.remove("csvlt_deleted")
+	assert.Empty(l.limiters)
+
+	l = NewRateLimiters(2.5, 5)
+	lim = l.get("csvlt_default", 0)
+	assert.NotNil(lim, "stores without a limit should use the default limit")
+	assert.Equal(rate.Limit(2.5), lim.Limit())
+	assert.Equal(5, lim.Burst())
+
+	assert.Same(lim, l.get("csvlt_default", 10), "store limit should replace the default limit")
+	assert.Equal(rate.Limit(10), lim.Limit())
+	assert.Equal(1, lim.Burst())
+
+	assert.Equal(1, NewRateLimiters(2.5, 0).get("csvlt_default", 0).Burst(), "burst should be at least one request")
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	// newFakeVault returns a fake Vault server which counts the requests it
+	// receives.
+	newFakeVault := func(t *testing.T) (*httptest.Server, *int32) {
+		t.Helper()
+		var count int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&count, 1)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"data":{"username":"user","password":"pass"}}`)
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &count
+	}
+
+	t.Run("paces-requests", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		srv, count := newFakeVault(t)
+		storeId, err := newCredentialStoreId()
+		require.NoError(err)
+
+		const requests = 5
+		const rps = 10
+		limiters := NewRateLimiters(0, 0)
+		start := time.Now()
+		for i := 0; i < requests; i++ {
+			// a new client is created for each request to verify the
+			// clients for a store share a limiter
+			c, err := newClient(context.Background(), &clientConfig{
+				Addr:              srv.URL,
+				Token:             TokenSecret("token"),
+				StoreId:           storeId,
+				RequestsPerSecond: rps,
+				Limiters:          limiters,
+			})
+			require.NoError(err)
+			_, err = c.get(context.Background(), "secret/data/foo")
+			require.NoError(err)
+		}
+		elapsed := time.Since(start)
+		assert.Equal(int32(requests), atomic.LoadInt32(count))
+		// the first request is allowed immediately, each following request
+		// waits 1/rps seconds
+		want := time.Duration(requests-1) * time.Second / rps
+		assert.GreaterOrEqual(int64(elapsed), int64(want-10*time.Millisecond), "want at least %s, got %s", want, elapsed)
+	})
+
+	t.Run("exceeds-max-wait", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		srv, count := newFakeVault(t)
+		storeId, err := newCredentialStoreId()
+		require.NoError(err)
+
+		c, err := newClient(context.Background(), &clientConfig{
+			Addr:              srv.URL,
+			Token:             TokenSecret("token"),
+			StoreId:           storeId,
+			RequestsPerSecond: 1,
+			Limiters:          NewRateLimiters(0, 0),
+		})
+		require.NoError(err)
+		c.maxLimiterWait = 100 * time.Millisecond
+
+		_, err = c.get(context.Background(), "secret/data/foo")
+		require.NoError(err)
+
+		start := time.Now()
+		_, err = c.get(context.Background(), "secret/data/foo")
+		require.Error(err)
+		assert.Less(int64(time.Since(start)), int64(time.Second))
+		assert.Truef(errors.Match(errors.T(errors.VaultRateLimited), err), "want err code: %q got: %q", errors.VaultRateLimited, err)
+		assert.Equal(int32(1), atomic.LoadInt32(count), "rate limited request should not be sent to vault")
+	})
+
+	t.Run("context-canceled", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		srv, count := newFakeVault(t)
+		storeId, err := newCredentialStoreId()
+		require.NoError(err)
+
+		c, err := newClient(context.Background(), &clientConfig{
+			Addr:              srv.URL,
+			Token:             TokenSecret("token"),
+			StoreId:           storeId,
+			RequestsPerSecond: 1,
+			Limiters:          NewRateLimiters(0, 0),
+		})
+		require.NoError(err)
+
+		_, err = c.get(context.Background(), "secret/data/foo")
+		require.NoError(err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		start := time.Now()
+		_, err = c.get(ctx, "secret/data/foo")
+		require.Error(err)
+		assert.Less(int64(time.Since(start)), int64(c.maxLimiterWait))
+		assert.Truef(errors.Match(errors.T(errors.VaultRateLimited), err), "want err code: %q got: %q", errors.VaultRateLimited, err)
+		assert.Equal(int32(1), atomic.LoadInt32(count), "rate limited request should not be sent to vault")
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		srv, count := newFakeVault(t)
+		storeId, err := newCredentialStoreId()
+		require.NoError(err)
+
+		c, err := newClient(context.Background(), &clientConfig{
+			Addr:    srv.URL,
+			Token:   TokenSecret("token"),
+			StoreId: storeId,
+		})
+		require.NoError(err)
+		assert.Nil(c.limiter)
+		for i := 0; i < 5; i++ {
+			_, err = c.get(context.Background(), "secret/data/foo")
+			require.NoError(err)
+		}
+		assert.Equal(int32(5), atomic.LoadInt32(count))
+	})
+}
+
+func TestRateLimiters_isolated(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":{"username":"user","password":"pass"}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	assert, require := assert.New(t), require.New(t)
+	const burst = 3
+	storeA, err := newCredentialStoreId()
+	require.NoError(err)
+	storeB, err := newCredentialStoreId()
+	require.NoError(err)
+
+	limiters := NewRateLimiters(1, burst)
+	get := func(l *RateLimiters, storeId string) error {
+		// a new client is created for each request, as the repository
+		// does for each operation
+		c, err := newClient(ctx, &clientConfig{
+			Addr:     srv.URL,
+			Token:    TokenSecret("token"),
+			StoreId:  storeId,
+			Limiters: l,
+		})
+		require.NoError(err)
+		c.maxLimiterWait = 10 * time.Millisecond
+		_, err = c.get(ctx, "secret/data/foo")
+		return err
+	}
+
+	var allowed, throttled int
+	for i := 0; i < burst+2; i++ {
+		err := get(limiters, storeA)
+		if err != nil {
+			assert.Truef(errors.Match(errors.T(errors.VaultRateLimited), err), "want err code: %q got: %q", errors.VaultRateLimited, err)
+			throttled++
+			continue
+		}
+		allowed++
+	}
+	assert.Equal(burst, allowed)
+	assert.Equal(2, throttled)
+
+	// the budget of storeA is exhausted but storeB is unaffected
+	for i := 0; i < burst; i++ {
+		assert.NoError(get(limiters, storeB))
+	}
+
+	// storeA is not limited by other rate limiters
+	assert.NoError(get(NewRateLimiters(1, burst), storeA))
+	assert.Equal(int32(2*burst+1), atomic.LoadInt32(&count))
+}
+
+func TestClient_RateLimitTokenExpired(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+
+	// Vault responds with a 403 to the credential request and to the
+	// lookup of the expired token.
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errors":["permission denied"]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	storeId, err := newCredentialStoreId()
+	require.NoError(err)
+	c, err := newClient(context.Background(), &clientConfig{
+		Addr:              srv.URL,
+		Token:             TokenSecret("token"),
+		StoreId:           storeId,
+		RequestsPerSecond: 1,
+		Limiters:          NewRateLimiters(0, 0),
+	})
+	require.NoError(err)
+	c.maxLimiterWait = 100 * time.Millisecond
+
+	// Classifying the error of the credential request must not wait on the
+	// rate limiter or take a token from it.
+	start := time.Now()
+	_, err = c.get(context.Background(), "secret/data/foo")
+	require.Error(err)
+	assert.Less(int64(time.Since(start)), int64(c.maxLimiterWait))
+	assert.Truef(errors.Match(errors.T(errors.VaultTokenExpired), err), "want err code: %q got: %q", errors.VaultTokenExpired, err)
+	assert.Equal(int32(2), atomic.LoadInt32(&count))
+}
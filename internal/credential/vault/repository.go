@@ -1,14 +1,21 @@
 package vault
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/observability/event"
+	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/scheduler"
 )
 
 // A Repository stores and retrieves the persistent types in the vault
-// package. It is not safe to use a repository concurrently.
+// package. A Repository holds no mutable state after it is created and is
+// safe to use concurrently.
 type Repository struct {
 	reader    db.Reader
 	writer    db.Writer
@@ -17,23 +24,62 @@ type Repository struct {
 	// defaultLimit provides a default for limiting the number of results
 	// returned from the repo
 	defaultLimit int
+	// maxLimit caps the limit of the ListX methods when greater than zero
+	maxLimit int
+	// defaultHttpMethod is the Method applied to credential libraries when
+	// an HTTP method is not specified
+	defaultHttpMethod Method
+	// limiters holds the rate limiter of each credential store which the
+	// repository's Vault clients wait on.
+	limiters *RateLimiters
+	// tokenFileDir is the directory credential stores can read their token
+	// files from. Token files cannot be used if it's empty.
+	tokenFileDir string
+	// allowSkipOplog allows the WithSkipOplog option to be used with the
+	// repository.
+	allowSkipOplog bool
+	// maxUpdateAttempts is the maximum number of times an update is
+	// attempted when it fails with a transaction conflict.
+	maxUpdateAttempts int
+	// updateBackoff is the backoff between the attempts of an update.
+	updateBackoff db.Backoff
+	// observeTimings enables the observation events written by
+	// observeTiming.
+	observeTimings bool
 }
 
-// NewRepository creates a new Repository. The returned repository should
-// only be used for one transaction and it is not safe for concurrent go
-// routines to access it. WithLimit option is used as a repo wide default
-// limit applied to all ListX methods.
-func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, scheduler *scheduler.Scheduler, opt ...Option) (*Repository, error) {
+// defaultMaxUpdateAttempts is the default maximum number of times an
+// update is attempted when it fails with a transaction conflict.
+const defaultMaxUpdateAttempts = 3
+
+// NewRepository creates a new Repository. The returned repository is safe
+// for concurrent go routines to access and can be shared rather than
+// created for each transaction. WithLimit option is used as a repo wide default
+// limit applied to all ListX methods. WithMaxLimit option is used as a repo
+// wide cap on the limit of all ListX methods. WithDefaultHttpMethod option is used
+// as a repo wide default HTTP method for credential libraries. WithRateLimiters
+// option is used to share the rate limiters of the credential stores with
+// other repositories and jobs; a repository without it has its own.
+// WithTokenFileDirectory option is used to set the directory credential
+// stores can read their token files from.
+// WithAllowSkipOplog option is used to allow the WithSkipOplog option on
+// the repo's methods which support it.
+// WithMaxUpdateAttempts option is used to set the maximum number of
+// attempts of an update which fails with a transaction conflict.
+// WithTimingObservations option is used to write an observation event with
+// the elapsed duration of each create, update, lookup, delete, and list
+// operation.
+func NewRepository(ctx context.Context, r db.Reader, w db.Writer, kms *kms.Kms, scheduler *scheduler.Scheduler, opt ...Option) (*Repository, error) {
 	const op = "vault.NewRepository"
 	switch {
 	case r == nil:
-		return nil, errors.NewDeprecated(errors.InvalidParameter, op, "db.Reader")
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "db.Reader")
 	case w == nil:
-		return nil, errors.NewDeprecated(errors.InvalidParameter, op, "db.Writer")
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "db.Writer")
 	case kms == nil:
-		return nil, errors.NewDeprecated(errors.InvalidParameter, op, "kms")
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "kms")
 	case scheduler == nil:
-		return nil, errors.NewDeprecated(errors.InvalidParameter, op, "scheduler")
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "scheduler")
 	}
 
 	opts := getOpts(opt...)
@@ -41,12 +87,122 @@ func NewRepository(r db.Reader, w db.Writer, kms *kms.Kms, scheduler *scheduler.
 		// zero signals the boundary defaults should be used.
 		opts.withLimit = db.DefaultLimit
 	}
+	switch {
+	case opts.withDefaultHttpMethod == "":
+		opts.withDefaultHttpMethod = DefaultHttpMethod
+	case !opts.withDefaultHttpMethod.Valid():
+		return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unsupported default http method: %s", opts.withDefaultHttpMethod))
+	}
+
+	if opts.withRateLimiters == nil {
+		opts.withRateLimiters = NewRateLimiters(0, 0)
+	}
+
+	if opts.withMaxUpdateAttempts <= 0 {
+		opts.withMaxUpdateAttempts = defaultMaxUpdateAttempts
+	}
 
 	return &Repository{
-		reader:       r,
-		writer:       w,
-		kms:          kms,
-		scheduler:    scheduler,
-		defaultLimit: opts.withLimit,
+		reader:            r,
+		writer:            w,
+		kms:               kms,
+		scheduler:         scheduler,
+		defaultLimit:      opts.withLimit,
+		maxLimit:          opts.withMaxLimit,
+		defaultHttpMethod: opts.withDefaultHttpMethod,
+		limiters:          opts.withRateLimiters,
+		tokenFileDir:      opts.withTokenFileDirectory,
+		allowSkipOplog:    opts.withAllowSkipOplog,
+		maxUpdateAttempts: opts.withMaxUpdateAttempts,
+		updateBackoff:     db.ExpBackoff{},
+		observeTimings:    opts.withTimingObservations,
 	}, nil
 }
+
+// retryOnConflict calls fn until it returns nil, an error which is not an
+// errors.TransactionConflict error, or it has been called
+// r.maxUpdateAttempts times. It waits for a short backoff before each
+// retry. All other errors, such as errors.NotUnique, are returned
+// immediately. fn must run a complete transaction so a retry starts with
+// a new transaction.
+func (r *Repository) retryOnConflict(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.maxUpdateAttempts || !errors.Match(errors.T(errors.TransactionConflict), err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(r.updateBackoff.Duration(uint(attempt))):
+		}
+	}
+}
+
+// observeTiming writes an observation event with the name of the
+// operation op and the time elapsed since start. It is meant to be
+// deferred at the start of an operation. Nothing is written if the
+// repository was not created with WithTimingObservations or there is no
+// eventer in ctx and no system eventer.
+func (r *Repository) observeTiming(ctx context.Context, op errors.Op, start time.Time) {
+	if !r.observeTimings {
+		return
+	}
+	if _, ok := event.EventerFromContext(ctx); !ok && event.SysEventer() == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	if err := event.WriteObservation(ctx, event.Op(op), event.WithHeader("operation", string(op), "elapsed", elapsed.String())); err != nil {
+		event.WriteError(ctx, event.Op(op), err, event.WithInfoMsg("unable to write timing observation"))
+	}
+}
+
+// oplogOptions returns the db options to write an oplog entry with metadata
+// for a change in scopeId. If WithSkipOplog is set in opts, no options are
+// returned and an oplog entry is not written. An errors.InvalidParameter
+// error is returned if WithSkipOplog is set and the repository was not
+// created with WithAllowSkipOplog.
+func (r *Repository) oplogOptions(ctx context.Context, scopeId string, metadata oplog.Metadata, opts options) ([]db.Option, error) {
+	const op = "vault.(Repository).oplogOptions"
+	if opts.withSkipOplog {
+		if !r.allowSkipOplog {
+			return nil, errors.New(ctx, errors.InvalidParameter, op, "skipping the oplog is not allowed for this repository")
+		}
+		return nil, nil
+	}
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to get oplog wrapper"))
+	}
+	return []db.Option{db.WithOplog(oplogWrapper, metadata)}, nil
+}
+
+// readerFor returns the db.Reader for a list or lookup method. If
+// WithStrongRead is set in opts and the repository's db.Writer is also a
+// db.Reader, the db.Writer is returned so the read sees all of the writes
+// made with the repository. Otherwise the repository's db.Reader is
+// returned.
+func (r *Repository) readerFor(opts options) db.Reader {
+	if opts.withStrongRead {
+		if reader, ok := r.writer.(db.Reader); ok {
+			return reader
+		}
+	}
+	return r.reader
+}
+
+// listLimit returns the limit for a ListX method. A non-zero WithLimit in
+// opts overrides the repository's default limit. The result is capped by
+// the repository's max limit, if one is set, even when it signals
+// unlimited results.
+func (r *Repository) listLimit(opts options) int {
+	limit := r.defaultLimit
+	if opts.withLimit != 0 {
+		// non-zero signals an override of the default limit for the repo.
+		limit = opts.withLimit
+	}
+	if r.maxLimit > 0 && (limit < 0 || limit > r.maxLimit) {
+		limit = r.maxLimit
+	}
+	return limit
+}
@@ -1,18 +1,34 @@
 package vault
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/boundary/internal/credential"
 	"github.com/hashicorp/boundary/internal/db"
 	dbcommon "github.com/hashicorp/boundary/internal/db/common"
 	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/filter"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/observability/event"
 	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/types/subtypes"
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 )
 
+// updateLibraryAuditOperation is the operation of the audit event written
+// when a credential library is updated.
+const updateLibraryAuditOperation = "update-credential-library"
+
+// redactedHttpRequestBody replaces the values of a credential library's
+// HttpRequestBody in audit events.
+const redactedHttpRequestBody = "[REDACTED: Vault http_request_body]"
+
 // CreateCredentialLibrary inserts l into the repository and returns a new
 // CredentialLibrary containing the credential library's PublicId. l is not
 // changed. l must contain a valid StoreId. l must not contain a PublicId.
@@ -22,30 +38,49 @@ import (
 // unique within l.StoreId.
 //
 // Both l.CreateTime and l.UpdateTime are ignored.
-func (r *Repository) CreateCredentialLibrary(ctx context.Context, scopeId string, l *CredentialLibrary, _ ...Option) (*CredentialLibrary, error) {
+//
+// l.StoreId must be a credential store in scopeId. An errors.RecordNotFound
+// error is returned if the credential store is not found and an
+// errors.InvalidParameter error is returned if it is in a different scope.
+//
+// If l.CredentialJsonPointer is set, it must be a valid RFC 6901 JSON
+// pointer.
+//
+// If l.CredentialMappings is set, it must be a valid JSON encoded list of
+// CredentialMapping and l.CredentialJsonPointer must not be set.
+//
+// If l.ContentType is set, it must be ContentTypeJson or ContentTypeForm.
+//
+// If l.TemplatedVaultPath is true, l.VaultPath must be a valid template
+// which only references the {{.Username}} and {{.Target}} variables. The
+// template is rendered with the values of the session when a credential is
+// issued.
+//
+// If WithPkiBodyValidation is true, l.HttpRequestBody must be a JSON object
+// containing a non-empty common_name as required by the Vault PKI issue
+// and sign endpoints.
+//
+// If WithPreflightNameCheck is true and l.Name is set, an errors.NotUnique
+// error is returned without attempting the insert when a credential library
+// named l.Name already exists in l.StoreId.
+//
+// If WithSkipOplog is true, an oplog entry is not written for the insert.
+// It is only allowed if the repository was created with WithAllowSkipOplog.
+func (r *Repository) CreateCredentialLibrary(ctx context.Context, scopeId string, l *CredentialLibrary, opt ...Option) (*CredentialLibrary, error) {
 	const op = "vault.(Repository).CreateCredentialLibrary"
-	if l == nil {
-		return nil, errors.New(ctx, errors.InvalidParameter, op, "nil CredentialLibrary")
-	}
-	if l.CredentialLibrary == nil {
-		return nil, errors.New(ctx, errors.InvalidParameter, op, "nil embedded l")
-	}
-	if l.StoreId == "" {
-		return nil, errors.New(ctx, errors.InvalidParameter, op, "no store id")
-	}
-	if l.VaultPath == "" {
-		return nil, errors.New(ctx, errors.InvalidParameter, op, "no vault path")
-	}
-	if l.PublicId != "" {
-		return nil, errors.New(ctx, errors.InvalidParameter, op, "public id not empty")
+	defer r.observeTiming(ctx, op, time.Now())
+	l, err := r.prepareNewCredentialLibrary(ctx, scopeId, l, opt...)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
 	}
-	if scopeId == "" {
-		return nil, errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	if err := r.checkStoreInScope(ctx, l.StoreId, scopeId); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
 	}
-	l = l.clone()
-
-	if l.HttpMethod == "" {
-		l.HttpMethod = string(MethodGet)
+	opts := getOpts(opt...)
+	if opts.withPreflightNameCheck && l.Name != "" {
+		if err := r.checkLibraryNameUnique(ctx, l); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
 	}
 
 	id, err := newCredentialLibraryId()
@@ -54,16 +89,16 @@ func (r *Repository) CreateCredentialLibrary(ctx context.Context, scopeId string
 	}
 	l.PublicId = id
 
-	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	oplogOpts, err := r.oplogOptions(ctx, scopeId, l.oplog(oplog.OpType_OP_TYPE_CREATE), opts)
 	if err != nil {
-		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to get oplog wrapper"))
+		return nil, errors.Wrap(ctx, err, op)
 	}
 
 	var newCredentialLibrary *CredentialLibrary
 	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
 		func(_ db.Reader, w db.Writer) error {
 			newCredentialLibrary = l.clone()
-			err := w.Create(ctx, newCredentialLibrary, db.WithOplog(oplogWrapper, l.oplog(oplog.OpType_OP_TYPE_CREATE)))
+			err := w.Create(ctx, newCredentialLibrary, oplogOpts...)
 			if err != nil {
 				return errors.Wrap(ctx, err, op)
 			}
@@ -80,49 +115,278 @@ func (r *Repository) CreateCredentialLibrary(ctx context.Context, scopeId string
 	return newCredentialLibrary, nil
 }
 
+// ValidateCredentialLibrary validates l as CreateCredentialLibrary would
+// without creating it. It returns nil if l is valid or the first error
+// found. The error codes match those returned by CreateCredentialLibrary
+// for the same l and options with the following difference. If l.Name
+// is set, an errors.NotUnique error is returned if a credential library
+// with the same name exists in l.StoreId, as if WithPreflightNameCheck was
+// true. l is not changed.
+func (r *Repository) ValidateCredentialLibrary(ctx context.Context, scopeId string, l *CredentialLibrary, opt ...Option) error {
+	const op = "vault.(Repository).ValidateCredentialLibrary"
+	l, err := r.prepareNewCredentialLibrary(ctx, scopeId, l, opt...)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	if err := checkRequestBodyAllowed(ctx, l); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+
+	if err := r.checkStoreInScope(ctx, l.StoreId, scopeId); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+
+	if l.Name != "" {
+		if err := r.checkLibraryNameUnique(ctx, l); err != nil {
+			return errors.Wrap(ctx, err, op)
+		}
+	}
+	return nil
+}
+
+// prepareNewCredentialLibrary validates the fields of l which do not
+// require a database lookup and returns a clone of l with a normalized
+// HttpMethod.
+func (r *Repository) prepareNewCredentialLibrary(ctx context.Context, scopeId string, l *CredentialLibrary, opt ...Option) (*CredentialLibrary, error) {
+	const op = "vault.(Repository).prepareNewCredentialLibrary"
+	if l == nil {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "nil CredentialLibrary")
+	}
+	if l.CredentialLibrary == nil {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "nil embedded l")
+	}
+	if l.StoreId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no store id", errors.WithField(storeIdField))
+	}
+	if l.VaultPath == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no vault path", errors.WithField(vaultPathField))
+	}
+	if l.PublicId != "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "public id not empty")
+	}
+	if scopeId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	}
+	l = l.clone()
+
+	if l.HttpMethod == "" {
+		l.HttpMethod = string(r.defaultHttpMethod)
+	}
+	m, err := ParseMethod(ctx, l.HttpMethod)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	l.HttpMethod = string(m)
+
+	if l.CredentialJsonPointer != "" {
+		if _, err := parseJsonPointer(ctx, l.CredentialJsonPointer); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
+	if err := validLibraryNamespace(ctx, l.Namespace); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if err := validLibraryContentType(ctx, l.ContentType); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if len(l.CredentialMappings) > 0 {
+		if l.CredentialJsonPointer != "" {
+			return nil, errors.New(ctx, errors.InvalidParameter, op, "credential mappings and credential json pointer cannot both be set")
+		}
+		if _, err := decodeCredentialMappings(ctx, l.CredentialMappings); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
+	if l.TemplatedVaultPath {
+		if err := validateVaultPath(ctx, l.VaultPath); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
+
+	opts := getOpts(opt...)
+	if opts.withPkiBodyValidation {
+		if err := validatePkiRequestBody(ctx, l.HttpRequestBody); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
+	return l, nil
+}
+
+// checkRequestBodyAllowed returns an errors.CheckConstraint error if l has
+// an HTTP request body and its HTTP method does not allow one. It matches
+// the http_request_body_only_allowed_with_body_method constraint on the
+// credential_vault_library table.
+func checkRequestBodyAllowed(ctx context.Context, l *CredentialLibrary) error {
+	const op = "vault.checkRequestBodyAllowed"
+	if len(l.HttpRequestBody) > 0 && !Method(l.HttpMethod).AllowsRequestBody() {
+		return errors.New(ctx, errors.CheckConstraint, op, "http request body is not allowed with the GET method")
+	}
+	return nil
+}
+
+// checkStoreInScope returns an errors.RecordNotFound error if the
+// credential store storeId does not exist and an errors.InvalidParameter
+// error if it is not in scopeId.
+func (r *Repository) checkStoreInScope(ctx context.Context, storeId, scopeId string) error {
+	const op = "vault.(Repository).checkStoreInScope"
+	cs, err := r.LookupCredentialStore(ctx, storeId)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	if cs == nil {
+		return errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("credential store %s not found", storeId))
+	}
+	if cs.GetScopeId() != scopeId {
+		return errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("credential store %s is not in scope %s", storeId, scopeId))
+	}
+	return nil
+}
+
+// checkLibraryNameUnique returns an errors.NotUnique error if a credential
+// library named l.Name exists in l.StoreId.
+func (r *Repository) checkLibraryNameUnique(ctx context.Context, l *CredentialLibrary) error {
+	const op = "vault.(Repository).checkLibraryNameUnique"
+	var libs []*CredentialLibrary
+	err := r.reader.SearchWhere(ctx, &libs, "store_id = ? and name = ?", []interface{}{l.StoreId, l.Name}, db.WithLimit(1))
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to check for duplicate name"))
+	}
+	if len(libs) > 0 {
+		return errors.New(ctx, errors.NotUnique, op, fmt.Sprintf("a credential library named %s already exists in credential store %s", l.Name, l.StoreId))
+	}
+	return nil
+}
+
+// CreateCredentialLibraryFromTemplate creates a new CredentialLibrary from
+// the credential library template templateId. A template is a credential
+// library created with the WithTemplate option. The new credential library
+// is created in the template's credential store with the template's
+// HttpMethod, ContentType, and TemplatedVaultPath. Each placeholder token,
+// such as {{common_name}}, in the template's VaultPath and HttpRequestBody
+// is replaced with its value in vars. Values replacing placeholders in a
+// JSON HttpRequestBody are escaped as the contents of a JSON string and
+// values replacing placeholders in a form HttpRequestBody are query
+// escaped. An errors.InvalidParameter error is returned if templateId is
+// not a template or if any placeholder is not resolved by vars.
+//
+// The name and description of the template are not copied. WithName and
+// WithDescription can be used to set them on the new credential library.
+// All options are passed to CreateCredentialLibrary.
+func (r *Repository) CreateCredentialLibraryFromTemplate(ctx context.Context, scopeId string, templateId string, vars map[string]string, opt ...Option) (*CredentialLibrary, error) {
+	const op = "vault.(Repository).CreateCredentialLibraryFromTemplate"
+	if templateId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no template id")
+	}
+	if scopeId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	}
+	tmpl, err := r.LookupCredentialLibrary(ctx, templateId)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if tmpl == nil {
+		return nil, errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("template %s not found", templateId))
+	}
+	if !tmpl.IsTemplate {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("credential library %s is not a template", templateId))
+	}
+
+	path, err := expandTemplate(ctx, tmpl.VaultPath, vars, nil)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("vault path"))
+	}
+	var body []byte
+	if len(tmpl.HttpRequestBody) > 0 {
+		b, err := expandTemplate(ctx, string(tmpl.HttpRequestBody), vars, requestBodyEscaper(tmpl.ContentType))
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op, errors.WithMsg("http request body"))
+		}
+		body = []byte(b)
+	}
+
+	opts := getOpts(opt...)
+	l, err := NewCredentialLibrary(ctx, tmpl.StoreId, path,
+		WithName(opts.withName),
+		WithDescription(opts.withDescription),
+		WithMethod(Method(tmpl.HttpMethod)),
+		WithRequestBody(body),
+		WithContentType(tmpl.ContentType),
+		WithTemplatedVaultPath(tmpl.TemplatedVaultPath))
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	return r.CreateCredentialLibrary(ctx, scopeId, l, opt...)
+}
+
 // UpdateCredentialLibrary updates the repository entry for l.PublicId with
 // the values in l for the fields listed in fieldMaskPaths. It returns a
 // new CredentialLibrary containing the updated values and a count of the
 // number of records updated. l is not changed.
 //
 // l must contain a valid PublicId. Only Name, Description, VaultPath,
-// HttpMethod, and HttpRequestBody can be updated. If l.Name is set to a
-// non-empty string, it must be unique within l.StoreId.
+// HttpMethod, HttpRequestBody, CredentialJsonPointer, TemplatedVaultPath,
+// CredentialMappings, WrapTtlSeconds, Namespace, and ContentType can be
+// updated. If l.Name is set to a non-empty string, it must be unique within
+// l.StoreId. If l.Namespace is set, it must not contain only whitespace.
+// Setting l.Namespace to NULL reverts the library to the namespace of its
+// credential store. If l.ContentType is set, it must be ContentTypeJson or
+// ContentTypeForm. Setting l.ContentType to NULL reverts the library to
+// DefaultContentType. If l.CredentialJsonPointer is
+// set to a non-empty string, it must be a valid RFC 6901 JSON pointer. If
+// l.CredentialMappings is set, it must be a valid JSON encoded list of
+// CredentialMapping. The update fails if the updated library has both
+// credential mappings and a credential JSON pointer. If the updated library has a templated vault path, the updated VaultPath
+// must be a valid template as described in CreateCredentialLibrary.
 //
 // An attribute of l will be set to NULL in the database if the attribute
 // in l is the zero value and it is included in fieldMaskPaths except for
 // HttpMethod.  If HttpMethod is in the fieldMaskPath but l.HttpMethod
-// is not set it will be set to the value "GET".  If storage has a value
-// for HttpRequestBody when l.HttpMethod is set to GET the update will fail.
-func (r *Repository) UpdateCredentialLibrary(ctx context.Context, scopeId string, l *CredentialLibrary, version uint32, fieldMaskPaths []string, _ ...Option) (*CredentialLibrary, int, error) {
+// is not set it will be set to the repository's default HTTP method, which
+// is DefaultHttpMethod unless the repository was created with
+// WithDefaultHttpMethod.  If storage has a value for HttpRequestBody when
+// l.HttpMethod is set to GET the update will fail.
+//
+// An update which fails with an errors.TransactionConflict error is
+// retried up to the repository's maximum number of update attempts, which
+// can be set with WithMaxUpdateAttempts.
+func (r *Repository) UpdateCredentialLibrary(ctx context.Context, scopeId string, l *CredentialLibrary, version uint32, fieldMaskPaths []string, opt ...Option) (*CredentialLibrary, int, error) {
 	const op = "vault.(Repository).UpdateCredentialLibrary"
+	updated, _, rowsUpdated, err := r.updateCredentialLibrary(ctx, op, scopeId, l, version, fieldMaskPaths, opt...)
+	return updated, rowsUpdated, err
+}
+
+// UpdateCredentialLibraryWithChanges is the same as UpdateCredentialLibrary
+// except it also returns the names of the fields in fieldMaskPaths whose
+// values differ from their values before the update. Fields in
+// fieldMaskPaths set to the value they already had are not included. The
+// returned field names are in the same form as the names accepted in
+// fieldMaskPaths.
+func (r *Repository) UpdateCredentialLibraryWithChanges(ctx context.Context, scopeId string, l *CredentialLibrary, version uint32, fieldMaskPaths []string, opt ...Option) (*CredentialLibrary, []string, int, error) {
+	const op = "vault.(Repository).UpdateCredentialLibraryWithChanges"
+	return r.updateCredentialLibrary(ctx, op, scopeId, l, version, fieldMaskPaths, opt...)
+}
+
+func (r *Repository) updateCredentialLibrary(ctx context.Context, op errors.Op, scopeId string, l *CredentialLibrary, version uint32, fieldMaskPaths []string, _ ...Option) (*CredentialLibrary, []string, int, error) {
+	defer r.observeTiming(ctx, op, time.Now())
 	if l == nil {
-		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing CredentialLibrary")
+		return nil, nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing CredentialLibrary")
 	}
 	if l.CredentialLibrary == nil {
-		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing embedded CredentialLibrary")
+		return nil, nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing embedded CredentialLibrary")
 	}
 	if l.PublicId == "" {
-		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidPublicId, op, "missing public id")
+		return nil, nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidPublicId, op, "missing public id")
 	}
 	if version == 0 {
-		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing version")
+		return nil, nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing version")
 	}
 	if scopeId == "" {
-		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing scope id")
+		return nil, nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing scope id")
 	}
 	l = l.clone()
 
-	for _, f := range fieldMaskPaths {
-		switch {
-		case strings.EqualFold(nameField, f):
-		case strings.EqualFold(descriptionField, f):
-		case strings.EqualFold(vaultPathField, f):
-		case strings.EqualFold(httpMethodField, f):
-		case strings.EqualFold(httpRequestBodyField, f):
-		default:
-			return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidFieldMask, op, f)
-		}
+	if err := validFieldMask(ctx, fieldMaskPaths); err != nil {
+		return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
 	}
 	var dbMask, nullFields []string
 	dbMask, nullFields = dbcommon.BuildUpdatePaths(
@@ -132,64 +396,633 @@ func (r *Repository) UpdateCredentialLibrary(ctx context.Context, scopeId string
 			vaultPathField:       l.VaultPath,
 			httpMethodField:      l.HttpMethod,
 			httpRequestBodyField: l.HttpRequestBody,
+
+			credentialJsonPointerField: l.CredentialJsonPointer,
+			templatedVaultPathField:    l.TemplatedVaultPath,
+			credentialMappingsField:    l.CredentialMappings,
+			wrapTtlField:               l.WrapTtlSeconds,
+			namespaceField:             l.Namespace,
+			contentTypeField:           l.ContentType,
 		},
 		fieldMaskPaths,
-		nil,
+		[]string{
+			templatedVaultPathField,
+		},
 	)
 
-	if strutil.StrListContains(nullFields, httpMethodField) {
+	switch {
+	case strutil.StrListContains(nullFields, httpMethodField):
 		dbMask = append(dbMask, httpMethodField)
 		nullFields = strutil.StrListDelete(nullFields, httpMethodField)
-		l.HttpMethod = string(MethodGet)
+		l.HttpMethod = string(r.defaultHttpMethod)
+	case strutil.StrListContains(dbMask, httpMethodField):
+		m, err := ParseMethod(ctx, l.HttpMethod)
+		if err != nil {
+			return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+		}
+		l.HttpMethod = string(m)
 	}
 
 	if len(dbMask) == 0 && len(nullFields) == 0 {
-		return nil, db.NoRowsAffected, errors.New(ctx, errors.EmptyFieldMask, op, "missing field mask")
+		return nil, nil, db.NoRowsAffected, errors.New(ctx, errors.EmptyFieldMask, op, "missing field mask")
+	}
+
+	if strutil.StrListContains(dbMask, credentialJsonPointerField) {
+		if _, err := parseJsonPointer(ctx, l.CredentialJsonPointer); err != nil {
+			return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+		}
+	}
+	if strutil.StrListContains(dbMask, credentialMappingsField) {
+		if _, err := decodeCredentialMappings(ctx, l.CredentialMappings); err != nil {
+			return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+		}
+	}
+	if strutil.StrListContains(dbMask, namespaceField) {
+		if err := validLibraryNamespace(ctx, l.Namespace); err != nil {
+			return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+		}
+	}
+	if strutil.StrListContains(dbMask, contentTypeField) {
+		if err := validLibraryContentType(ctx, l.ContentType); err != nil {
+			return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+		}
+	}
+
+	updatePath := strutil.StrListContains(dbMask, vaultPathField)
+	updateTemplated := strutil.StrListContains(dbMask, templatedVaultPathField)
+	if updatePath || updateTemplated {
+		vaultPath, templated := l.VaultPath, l.TemplatedVaultPath
+		if !updatePath || !updateTemplated {
+			// the templated vault path must be validated against the
+			// current value of the field not being updated
+			cur, err := r.LookupCredentialLibrary(ctx, l.PublicId)
+			if err != nil {
+				return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+			}
+			if cur == nil {
+				return nil, nil, db.NoRowsAffected, errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("credential library %s", l.PublicId))
+			}
+			if !updatePath {
+				vaultPath = cur.VaultPath
+			}
+			if !updateTemplated {
+				templated = cur.TemplatedVaultPath
+			}
+		}
+		if templated {
+			if err := validateVaultPath(ctx, vaultPath); err != nil {
+				return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+			}
+		}
 	}
 
 	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
 	if err != nil {
-		return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithCode(errors.Encrypt),
+		return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithCode(errors.Encrypt),
 			errors.WithMsg("unable to get oplog wrapper"))
 	}
 
 	var rowsUpdated int
+	var changes []string
+	var orig, returnedCredentialLibrary *CredentialLibrary
+	err = r.retryOnConflict(ctx, func() error {
+		_, err := r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+			func(reader db.Reader, w db.Writer) error {
+				changes = nil
+				orig = allocCredentialLibrary()
+				orig.PublicId = l.PublicId
+				if err := reader.LookupByPublicId(ctx, orig); err != nil {
+					if errors.IsNotFoundError(err) {
+						// let the update report the missing library
+						orig = nil
+					} else {
+						return errors.Wrap(ctx, err, op, errors.WithMsg("unable to lookup credential library"))
+					}
+				}
+				returnedCredentialLibrary = l.clone()
+				var err error
+				rowsUpdated, err = w.Update(ctx, returnedCredentialLibrary, dbMask, nullFields,
+					db.WithOplog(oplogWrapper, l.oplog(oplog.OpType_OP_TYPE_UPDATE)),
+					db.WithVersion(&version))
+				if err == nil && rowsUpdated > 1 {
+					return errors.New(ctx, errors.MultipleRecords, op, "more than 1 resource would have been updated")
+				}
+				if err == nil && rowsUpdated == 1 && orig != nil {
+					changes = changedLibraryFields(orig, returnedCredentialLibrary, append(dbMask, nullFields...))
+				}
+				return err
+			},
+		)
+		return err
+	})
+
+	if err != nil {
+		if errors.IsUniqueError(err) {
+			return nil, nil, db.NoRowsAffected, errors.New(ctx, errors.NotUnique, op,
+				fmt.Sprintf("name %s already exists: %s", l.Name, l.PublicId))
+		}
+		return nil, nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg(l.PublicId))
+	}
+
+	if rowsUpdated == 1 && orig != nil {
+		// Writing the audit event is best effort and an error should not
+		// cause the update to fail.
+		if err := writeUpdateLibraryAuditEvent(ctx, orig, returnedCredentialLibrary, fieldMaskPaths, changes); err != nil {
+			event.WriteError(ctx, event.Op(op), err, event.WithInfoMsg("unable to write credential library update audit event", "library_id", l.PublicId))
+		}
+	}
+
+	return returnedCredentialLibrary, changes, rowsUpdated, nil
+}
+
+// writeUpdateLibraryAuditEvent writes an audit event recording the field
+// mask paths of an update to a credential library and the values before and
+// after the update of each of the changed fields. The values of the
+// HttpRequestBody field are redacted.
+func writeUpdateLibraryAuditEvent(ctx context.Context, orig, updated *CredentialLibrary, fieldMaskPaths, changes []string) error {
+	const op = "vault.writeUpdateLibraryAuditEvent"
+	paths := make([]interface{}, 0, len(fieldMaskPaths))
+	for _, p := range fieldMaskPaths {
+		paths = append(paths, p)
+	}
+	changed := make(map[string]interface{}, len(changes))
+	for _, f := range changes {
+		changed[f] = map[string]interface{}{
+			"before": libraryAuditValue(orig, f),
+			"after":  libraryAuditValue(updated, f),
+		}
+	}
+	if err := writeAuditEvent(ctx, updateLibraryAuditOperation, map[string]interface{}{
+		"library_id":       orig.GetPublicId(),
+		"store_id":         orig.GetStoreId(),
+		"field_mask_paths": paths,
+		"changed_fields":   changed,
+	}); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	return nil
+}
+
+// libraryAuditValue returns the value of field in l to include in an audit
+// event.
+func libraryAuditValue(l *CredentialLibrary, field string) interface{} {
+	switch field {
+	case nameField:
+		return l.Name
+	case descriptionField:
+		return l.Description
+	case vaultPathField:
+		return l.VaultPath
+	case httpMethodField:
+		return l.HttpMethod
+	case httpRequestBodyField:
+		return redactedHttpRequestBody
+	case credentialJsonPointerField:
+		return l.CredentialJsonPointer
+	case templatedVaultPathField:
+		return l.TemplatedVaultPath
+	case credentialMappingsField:
+		// the mappings only contain json pointers, not secrets
+		return string(l.CredentialMappings)
+	case wrapTtlField:
+		return float64(l.WrapTtlSeconds)
+	case namespaceField:
+		return l.Namespace
+	case contentTypeField:
+		return l.ContentType
+	}
+	return nil
+}
+
+// changedLibraryFields returns the names of the fields in fields which
+// have different values in orig and updated.
+func changedLibraryFields(orig, updated *CredentialLibrary, fields []string) []string {
+	var changed []string
+	for _, f := range fields {
+		var same bool
+		switch f {
+		case nameField:
+			same = orig.Name == updated.Name
+		case descriptionField:
+			same = orig.Description == updated.Description
+		case vaultPathField:
+			same = orig.VaultPath == updated.VaultPath
+		case httpMethodField:
+			same = orig.HttpMethod == updated.HttpMethod
+		case httpRequestBodyField:
+			same = bytes.Equal(orig.HttpRequestBody, updated.HttpRequestBody)
+		case credentialJsonPointerField:
+			same = orig.CredentialJsonPointer == updated.CredentialJsonPointer
+		case templatedVaultPathField:
+			same = orig.TemplatedVaultPath == updated.TemplatedVaultPath
+		case credentialMappingsField:
+			same = bytes.Equal(orig.CredentialMappings, updated.CredentialMappings)
+		case wrapTtlField:
+			same = orig.WrapTtlSeconds == updated.WrapTtlSeconds
+		case namespaceField:
+			same = orig.Namespace == updated.Namespace
+		case contentTypeField:
+			same = orig.ContentType == updated.ContentType
+		}
+		if !same {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
+
+// MoveCredentialLibrary moves the credential library libraryId to the
+// credential store destStoreId and returns the updated CredentialLibrary.
+// The credential library's current credential store and destStoreId must
+// both be in scopeId. If the credential library has a name, it must be
+// unique within destStoreId; an errors.NotUnique error is returned if it
+// is not.
+func (r *Repository) MoveCredentialLibrary(ctx context.Context, scopeId, libraryId, destStoreId string, _ ...Option) (*CredentialLibrary, error) {
+	const op = "vault.(Repository).MoveCredentialLibrary"
+	switch {
+	case scopeId == "":
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	case libraryId == "":
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no library id")
+	case destStoreId == "":
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no destination store id")
+	}
+
+	l, err := r.LookupCredentialLibrary(ctx, libraryId)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if l == nil {
+		return nil, errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("credential library %s not found", libraryId))
+	}
+	if l.StoreId == destStoreId {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("credential library %s is already in credential store %s", libraryId, destStoreId))
+	}
+	for _, id := range []string{l.StoreId, destStoreId} {
+		if err := r.checkStoreInScope(ctx, id, scopeId); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
+
+	if l.Name != "" {
+		var libs []*CredentialLibrary
+		err := r.reader.SearchWhere(ctx, &libs, "store_id = ? and name = ?", []interface{}{destStoreId, l.Name}, db.WithLimit(1))
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to check for duplicate name"))
+		}
+		if len(libs) > 0 {
+			return nil, errors.New(ctx, errors.NotUnique, op, fmt.Sprintf("a credential library named %s already exists in credential store %s", l.Name, destStoreId))
+		}
+	}
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.Encrypt),
+			errors.WithMsg("unable to get oplog wrapper"))
+	}
+
+	l.StoreId = destStoreId
+	version := l.Version
 	var returnedCredentialLibrary *CredentialLibrary
 	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
 		func(_ db.Reader, w db.Writer) error {
 			returnedCredentialLibrary = l.clone()
-			var err error
-			rowsUpdated, err = w.Update(ctx, returnedCredentialLibrary, dbMask, nullFields,
+			rowsUpdated, err := w.Update(ctx, returnedCredentialLibrary, []string{storeIdField}, nil,
 				db.WithOplog(oplogWrapper, l.oplog(oplog.OpType_OP_TYPE_UPDATE)),
 				db.WithVersion(&version))
-			if err == nil && rowsUpdated > 1 {
-				return errors.New(ctx, errors.MultipleRecords, op, "more than 1 resource would have been updated")
+			if err != nil {
+				return errors.Wrap(ctx, err, op)
 			}
-			return err
+			if rowsUpdated != 1 {
+				return errors.New(ctx, errors.MultipleRecords, op, fmt.Sprintf("updated credential library and %d rows updated", rowsUpdated))
+			}
+			return nil
 		},
 	)
-
 	if err != nil {
 		if errors.IsUniqueError(err) {
-			return nil, db.NoRowsAffected, errors.New(ctx, errors.NotUnique, op,
-				fmt.Sprintf("name %s already exists: %s", l.Name, l.PublicId))
+			return nil, errors.New(ctx, errors.NotUnique, op,
+				fmt.Sprintf("name %s already exists in credential store %s", l.Name, destStoreId))
 		}
-		return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg(l.PublicId))
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg(libraryId))
 	}
+	return returnedCredentialLibrary, nil
+}
 
-	return returnedCredentialLibrary, rowsUpdated, nil
+// PrefixCredentialLibraryNames prepends prefix to the name of each named
+// credential library in the credential store storeId and returns the
+// number of credential libraries renamed. Credential libraries without a
+// name are not changed. The store must be in scopeId.
+//
+// All of the credential libraries are renamed in a single transaction. If
+// any rename fails, none of the credential libraries are renamed and an
+// errors.NotUnique error is returned if the failure was a name collision.
+func (r *Repository) PrefixCredentialLibraryNames(ctx context.Context, scopeId, storeId, prefix string, _ ...Option) (int, error) {
+	const op = "vault.(Repository).PrefixCredentialLibraryNames"
+	switch {
+	case scopeId == "":
+		return db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	case storeId == "":
+		return db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "no store id")
+	case prefix == "":
+		return db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "no prefix")
+	}
+	if err := r.checkStoreInScope(ctx, storeId, scopeId); err != nil {
+		return db.NoRowsAffected, errors.Wrap(ctx, err, op)
+	}
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithCode(errors.Encrypt),
+			errors.WithMsg("unable to get oplog wrapper"))
+	}
+
+	var renamed int
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			renamed = 0
+			var libs []*CredentialLibrary
+			// Longer names are renamed first so a library is never renamed
+			// to the current name of another library in the store which
+			// has yet to be renamed.
+			if err := reader.SearchWhere(ctx, &libs, "store_id = ? and name is not null", []interface{}{storeId},
+				db.WithLimit(-1), db.WithOrder("length(name) desc, public_id")); err != nil {
+				return errors.Wrap(ctx, err, op)
+			}
+			for _, l := range libs {
+				version := l.Version
+				ul := l.clone()
+				ul.Name = prefix + l.Name
+				rowsUpdated, err := w.Update(ctx, ul, []string{nameField}, nil,
+					db.WithOplog(oplogWrapper, ul.oplog(oplog.OpType_OP_TYPE_UPDATE)),
+					db.WithVersion(&version))
+				if err != nil {
+					if errors.IsUniqueError(err) {
+						return errors.New(ctx, errors.NotUnique, op,
+							fmt.Sprintf("name %s already exists in credential store %s", ul.Name, storeId))
+					}
+					return errors.Wrap(ctx, err, op, errors.WithMsg(l.PublicId))
+				}
+				if rowsUpdated != 1 {
+					return errors.New(ctx, errors.MultipleRecords, op, fmt.Sprintf("updated credential library and %d rows updated", rowsUpdated))
+				}
+				renamed++
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return db.NoRowsAffected, errors.Wrap(ctx, err, op)
+	}
+	return renamed, nil
+}
+
+// libraryExportVersion is the version of the document format written by
+// ExportCredentialLibraries.
+const libraryExportVersion = 1
+
+// libraryExport is the portable JSON document written by
+// ExportCredentialLibraries and read by ImportCredentialLibraries.
+type libraryExport struct {
+	Version   int               `json:"version"`
+	Libraries []exportedLibrary `json:"libraries"`
+}
+
+// exportedLibrary contains the portable fields of a credential library.
+type exportedLibrary struct {
+	Name                  string               `json:"name,omitempty"`
+	Description           string               `json:"description,omitempty"`
+	HttpMethod            string               `json:"http_method,omitempty"`
+	VaultPath             string               `json:"vault_path"`
+	HttpRequestBody       string               `json:"http_request_body,omitempty"`
+	CredentialJsonPointer string               `json:"credential_json_pointer,omitempty"`
+	TemplatedVaultPath    bool                 `json:"templated_vault_path,omitempty"`
+	CredentialMappings    []*CredentialMapping `json:"credential_mappings,omitempty"`
+	WrapTtlSeconds        uint32               `json:"wrap_ttl_seconds,omitempty"`
+	Namespace             string               `json:"namespace,omitempty"`
+	ContentType           string               `json:"content_type,omitempty"`
+	IsTemplate            bool                 `json:"is_template,omitempty"`
+}
+
+// ExportCredentialLibraries returns a portable JSON document containing
+// the credential libraries, including templates, in the credential store
+// storeId. Every persisted field of each library is exported, including
+// its HTTP request body. The secrets used to issue credentials, such as the
+// Vault token and client certificate key, belong to the credential store
+// and are never exported. A request body is exported as is, so a document
+// with libraries whose request bodies contain sensitive values must be
+// protected like the libraries themselves. The document can be imported
+// into any credential store with ImportCredentialLibraries. An errors.RecordNotFound error is returned if
+// storeId does not exist.
+func (r *Repository) ExportCredentialLibraries(ctx context.Context, storeId string) ([]byte, error) {
+	const op = "vault.(Repository).ExportCredentialLibraries"
+	if storeId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no store id")
+	}
+	cs, err := r.LookupCredentialStore(ctx, storeId)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if cs == nil {
+		return nil, errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("credential store %s not found", storeId))
+	}
+
+	var libs []*CredentialLibrary
+	if err := r.reader.SearchWhere(ctx, &libs, "store_id = ?", []interface{}{storeId},
+		db.WithLimit(-1), db.WithOrder("create_time, public_id")); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+
+	doc := libraryExport{
+		Version:   libraryExportVersion,
+		Libraries: make([]exportedLibrary, 0, len(libs)),
+	}
+	for _, l := range libs {
+		el := exportedLibrary{
+			Name:                  l.Name,
+			Description:           l.Description,
+			HttpMethod:            l.HttpMethod,
+			VaultPath:             l.VaultPath,
+			HttpRequestBody:       string(l.HttpRequestBody),
+			CredentialJsonPointer: l.CredentialJsonPointer,
+			TemplatedVaultPath:    l.TemplatedVaultPath,
+			WrapTtlSeconds:        l.WrapTtlSeconds,
+			Namespace:             l.Namespace,
+			ContentType:           l.ContentType,
+			IsTemplate:            l.IsTemplate,
+		}
+		if len(l.CredentialMappings) > 0 {
+			if el.CredentialMappings, err = decodeCredentialMappings(ctx, l.CredentialMappings); err != nil {
+				return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("library: %s", l.PublicId)))
+			}
+		}
+		doc.Libraries = append(doc.Libraries, el)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.Encode))
+	}
+	return data, nil
+}
+
+// ImportCredentialLibraries creates a credential library in the credential
+// store storeId for each library in data and returns the new credential
+// libraries. data must be a document written by ExportCredentialLibraries.
+// storeId must be in scopeId. Every library is validated as
+// CreateCredentialLibrary would before any library is created, and all of
+// the libraries are created in a single transaction. If any library is
+// invalid or cannot be created, no libraries are created.
+func (r *Repository) ImportCredentialLibraries(ctx context.Context, scopeId, storeId string, data []byte) ([]*CredentialLibrary, error) {
+	const op = "vault.(Repository).ImportCredentialLibraries"
+	switch {
+	case scopeId == "":
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	case storeId == "":
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no store id")
+	case len(data) == 0:
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no data")
+	}
+
+	var doc libraryExport
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.InvalidParameter), errors.WithMsg("unable to decode data"))
+	}
+	if doc.Version != libraryExportVersion {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unsupported export version: %d", doc.Version))
+	}
+
+	if err := r.checkStoreInScope(ctx, storeId, scopeId); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+
+	libs := make([]*CredentialLibrary, 0, len(doc.Libraries))
+	for i, el := range doc.Libraries {
+		l, err := NewCredentialLibrary(ctx, storeId, el.VaultPath,
+			WithName(el.Name),
+			WithDescription(el.Description),
+			WithMethod(Method(el.HttpMethod)),
+			WithRequestBody([]byte(el.HttpRequestBody)),
+			WithCredentialJsonPointer(el.CredentialJsonPointer),
+			WithTemplatedVaultPath(el.TemplatedVaultPath),
+			WithCredentialMappings(el.CredentialMappings...),
+			WithWrapTtl(time.Duration(el.WrapTtlSeconds)*time.Second),
+			WithNamespace(el.Namespace),
+			WithContentType(el.ContentType),
+			WithTemplate(el.IsTemplate))
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("library %d", i)))
+		}
+		l, err = r.prepareNewCredentialLibrary(ctx, scopeId, l)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("library %d", i)))
+		}
+		if err := checkRequestBodyAllowed(ctx, l); err != nil {
+			return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("library %d", i)))
+		}
+		id, err := newCredentialLibraryId()
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+		l.PublicId = id
+		libs = append(libs, l)
+	}
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.Encrypt),
+			errors.WithMsg("unable to get oplog wrapper"))
+	}
+
+	var imported []*CredentialLibrary
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(_ db.Reader, w db.Writer) error {
+			imported = make([]*CredentialLibrary, 0, len(libs))
+			for _, l := range libs {
+				nl := l.clone()
+				if err := w.Create(ctx, nl, db.WithOplog(oplogWrapper, nl.oplog(oplog.OpType_OP_TYPE_CREATE))); err != nil {
+					if errors.IsUniqueError(err) {
+						return errors.New(ctx, errors.NotUnique, op,
+							fmt.Sprintf("name %s already exists in credential store %s", nl.Name, storeId))
+					}
+					return errors.Wrap(ctx, err, op)
+				}
+				imported = append(imported, nl)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("in credential store: %s", storeId)))
+	}
+	return imported, nil
+}
+
+// updatableLibraryFields are the CredentialLibrary fields which can be
+// included in the field mask of UpdateCredentialLibrary.
+var updatableLibraryFields = []string{
+	nameField,
+	descriptionField,
+	vaultPathField,
+	httpMethodField,
+	httpRequestBodyField,
+	credentialJsonPointerField,
+	templatedVaultPathField,
+	credentialMappingsField,
+	wrapTtlField,
+	namespaceField,
+	contentTypeField,
+}
+
+// readOnlyLibraryFields are the CredentialLibrary fields which are set by
+// the repository or the database and can never be updated.
+var readOnlyLibraryFields = []string{
+	publicIdField,
+	storeIdField,
+	createTimeField,
+	updateTimeField,
+	versionField,
+}
+
+// validFieldMask returns an errors.InvalidFieldMask error if masks
+// contains a read-only or unknown CredentialLibrary field. Field names are
+// case insensitive.
+func validFieldMask(ctx context.Context, masks []string) error {
+	const op = "vault.validFieldMask"
+	for _, f := range masks {
+		switch {
+		case containsFold(updatableLibraryFields, f):
+		case containsFold(readOnlyLibraryFields, f):
+			return errors.New(ctx, errors.InvalidFieldMask, op, fmt.Sprintf("%s: read-only field", f))
+		default:
+			return errors.New(ctx, errors.InvalidFieldMask, op, f)
+		}
+	}
+	return nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
 }
 
 // LookupCredentialLibrary returns the CredentialLibrary for publicId.
 // Returns nil, nil if no CredentialLibrary is found for publicId.
-func (r *Repository) LookupCredentialLibrary(ctx context.Context, publicId string, _ ...Option) (*CredentialLibrary, error) {
+// WithStrongRead is the only option supported.
+func (r *Repository) LookupCredentialLibrary(ctx context.Context, publicId string, opt ...Option) (*CredentialLibrary, error) {
 	const op = "vault.(Repository).LookupCredentialLibrary"
+	defer r.observeTiming(ctx, op, time.Now())
 	if publicId == "" {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "no public id")
 	}
 	l := allocCredentialLibrary()
 	l.PublicId = publicId
-	if err := r.reader.LookupByPublicId(ctx, l); err != nil {
+	if err := r.readerFor(getOpts(opt...)).LookupByPublicId(ctx, l); err != nil {
 		if errors.IsNotFoundError(err) {
 			return nil, nil
 		}
@@ -199,9 +1032,12 @@ func (r *Repository) LookupCredentialLibrary(ctx context.Context, publicId strin
 }
 
 // DeleteCredentialLibrary deletes publicId from the repository and returns
-// the number of records deleted.
-func (r *Repository) DeleteCredentialLibrary(ctx context.Context, scopeId string, publicId string, _ ...Option) (int, error) {
+// the number of records deleted. If WithSkipOplog is true, an oplog entry
+// is not written for the delete. It is only allowed if the repository was
+// created with WithAllowSkipOplog.
+func (r *Repository) DeleteCredentialLibrary(ctx context.Context, scopeId string, publicId string, opt ...Option) (int, error) {
 	const op = "vault.(Repository).DeleteCredentialLibrary"
+	defer r.observeTiming(ctx, op, time.Now())
 	if publicId == "" {
 		return db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "no public id")
 	}
@@ -212,9 +1048,9 @@ func (r *Repository) DeleteCredentialLibrary(ctx context.Context, scopeId string
 	l := allocCredentialLibrary()
 	l.PublicId = publicId
 
-	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	oplogOpts, err := r.oplogOptions(ctx, scopeId, l.oplog(oplog.OpType_OP_TYPE_DELETE), getOpts(opt...))
 	if err != nil {
-		return db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg("unable to get oplog wrapper"))
+		return db.NoRowsAffected, errors.Wrap(ctx, err, op)
 	}
 
 	var rowsDeleted int
@@ -222,7 +1058,7 @@ func (r *Repository) DeleteCredentialLibrary(ctx context.Context, scopeId string
 		ctx, db.StdRetryCnt, db.ExpBackoff{},
 		func(_ db.Reader, w db.Writer) (err error) {
 			dl := l.clone()
-			rowsDeleted, err = w.Delete(ctx, dl, db.WithOplog(oplogWrapper, l.oplog(oplog.OpType_OP_TYPE_DELETE)))
+			rowsDeleted, err = w.Delete(ctx, dl, oplogOpts...)
 			if err == nil && rowsDeleted > 1 {
 				return errors.New(ctx, errors.MultipleRecords, op, "more than 1 CredentialLibrary would have been deleted")
 			}
@@ -238,22 +1074,131 @@ func (r *Repository) DeleteCredentialLibrary(ctx context.Context, scopeId string
 }
 
 // ListCredentialLibraries returns a slice of CredentialLibraries for the
-// storeId. WithLimit is the only option supported.
+// storeId. WithLimit, WithStoreType, WithTemplate, WithCreatedAfter,
+// WithCreatedBefore, WithUpdatedAfter, and WithUpdatedBefore are the only
+// options supported.
+// The limit is capped by the repository's WithMaxLimit, if set. If
+// WithStoreType is set, only libraries in a credential store of that type
+// are returned. If it is set to a registered credential store type other
+// than vault, an empty slice is returned. An unknown store type returns an
+// errors.InvalidParameter error.
+//
+// The time range options only return the libraries created or updated
+// within the range. They can be combined with each other. An
+// errors.InvalidParameter error is returned if an after time is not
+// before the before time of the same range.
+//
+// WithFilter only returns the libraries which match a filter expression,
+// such as "/item/name" == "x", evaluated against the fields of each
+// library. The limit is applied to the matching libraries. An
+// errors.InvalidParameter error is returned if the expression is invalid.
+//
+// Templates are not returned unless WithTemplate is set, in which case only
+// templates are returned.
+//
+// WithStrongRead reads the libraries with the repository's db.Writer so a
+// library created with the repository is always listed.
 func (r *Repository) ListCredentialLibraries(ctx context.Context, storeId string, opt ...Option) ([]*CredentialLibrary, error) {
 	const op = "vault.(Repository).ListCredentialLibraries"
+	defer r.observeTiming(ctx, op, time.Now())
 	if storeId == "" {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "no storeId")
 	}
 	opts := getOpts(opt...)
-	limit := r.defaultLimit
-	if opts.withLimit != 0 {
-		// non-zero signals an override of the default limit for the repo.
-		limit = opts.withLimit
+	f, err := filter.NewEvaluator(opts.withFilter)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.InvalidParameter))
+	}
+	if opts.withStoreType != "" {
+		switch credential.SubtypeFromType(opts.withStoreType) {
+		case Subtype:
+			// every credential library in this repository belongs to a
+			// vault credential store, so no additional filtering is needed
+		case subtypes.UnknownSubtype:
+			return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unknown store type: %s", opts.withStoreType))
+		default:
+			return nil, nil
+		}
+	}
+	where := "store_id = ? and is_template = ?"
+	args := []interface{}{storeId, opts.withTemplate}
+	for _, rng := range []struct {
+		column        string
+		after, before time.Time
+	}{
+		{"create_time", opts.withCreatedAfter, opts.withCreatedBefore},
+		{"update_time", opts.withUpdatedAfter, opts.withUpdatedBefore},
+	} {
+		if !rng.after.IsZero() && !rng.before.IsZero() && !rng.after.Before(rng.before) {
+			return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("%s after %s is not before %s", rng.column, rng.after, rng.before))
+		}
+		if !rng.after.IsZero() {
+			where += fmt.Sprintf(" and %s > ?", rng.column)
+			args = append(args, rng.after)
+		}
+		if !rng.before.IsZero() {
+			where += fmt.Sprintf(" and %s < ?", rng.column)
+			args = append(args, rng.before)
+		}
+	}
+	limit := r.listLimit(opts)
+	searchLimit := limit
+	if opts.withFilter != "" {
+		// the limit is applied to the libraries which match the filter
+		searchLimit = -1
 	}
 	var libs []*CredentialLibrary
-	err := r.reader.SearchWhere(ctx, &libs, "store_id = ?", []interface{}{storeId}, db.WithLimit(limit))
+	if err := r.readerFor(opts).SearchWhere(ctx, &libs, where, args, db.WithLimit(searchLimit)); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if opts.withFilter == "" {
+		return libs, nil
+	}
+	matched := libs[:0]
+	for _, l := range libs {
+		if limit > 0 && len(matched) == limit {
+			break
+		}
+		if f.Match(l.CredentialLibrary) {
+			matched = append(matched, l)
+		}
+	}
+	return matched, nil
+}
+
+// ListCredentialLibraryIds returns the public ids of the credential
+// libraries in storeId ordered by public id. Templates are not included.
+// Only the public ids are read
+// from the database, which makes it cheaper than ListCredentialLibraries
+// when only the ids are needed. WithLimit and WithStrongRead are the only
+// options supported. The limit is capped by the repository's WithMaxLimit,
+// if set.
+func (r *Repository) ListCredentialLibraryIds(ctx context.Context, storeId string, opt ...Option) ([]string, error) {
+	const op = "vault.(Repository).ListCredentialLibraryIds"
+	defer r.observeTiming(ctx, op, time.Now())
+	if storeId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no storeId")
+	}
+	opts := getOpts(opt...)
+	var limit interface{}
+	if l := r.listLimit(opts); l > 0 {
+		limit = l
+	}
+	rows, err := r.readerFor(opts).Query(ctx, listLibraryIdsQuery, []interface{}{sql.Named("store_id", storeId), sql.Named("limit", limit)})
 	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("failed for %s", storeId)))
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, errors.Wrap(ctx, err, op)
 	}
-	return libs, nil
+	return ids, nil
 }
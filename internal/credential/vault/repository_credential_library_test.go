@@ -2,6 +2,10 @@ package vault
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,11 +34,12 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
 
 	tests := []struct {
-		name    string
-		in      *CredentialLibrary
-		opts    []Option
-		want    *CredentialLibrary
-		wantErr errors.Code
+		name         string
+		in           *CredentialLibrary
+		opts         []Option
+		want         *CredentialLibrary
+		wantErr      errors.Code
+		wantErrField string
 	}{
 		{
 			name:    "nil-CredentialLibrary",
@@ -50,7 +55,8 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 			in: &CredentialLibrary{
 				CredentialLibrary: &store.CredentialLibrary{},
 			},
-			wantErr: errors.InvalidParameter,
+			wantErr:      errors.InvalidParameter,
+			wantErrField: storeIdField,
 		},
 		{
 			name: "invalid-public-id-set",
@@ -69,7 +75,8 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 					StoreId: cs.GetPublicId(),
 				},
 			},
-			wantErr: errors.InvalidParameter,
+			wantErr:      errors.InvalidParameter,
+			wantErrField: vaultPathField,
 		},
 		{
 			name: "valid-no-options",
@@ -166,6 +173,250 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid-PUT-http-body",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "PUT",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte(`{"common_name":"boundary.com"}`),
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "PUT",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte(`{"common_name":"boundary.com"}`),
+				},
+			},
+		},
+		{
+			name: "valid-PATCH-http-body",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "patch",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte(`{"common_name":"boundary.com"}`),
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "PATCH",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte(`{"common_name":"boundary.com"}`),
+				},
+			},
+		},
+		{
+			name: "valid-credential-json-pointer",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:               cs.GetPublicId(),
+					HttpMethod:            "GET",
+					VaultPath:             "/some/path",
+					CredentialJsonPointer: "/data/foo/bar",
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:               cs.GetPublicId(),
+					HttpMethod:            "GET",
+					VaultPath:             "/some/path",
+					CredentialJsonPointer: "/data/foo/bar",
+				},
+			},
+		},
+		{
+			name: "invalid-credential-json-pointer",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:               cs.GetPublicId(),
+					HttpMethod:            "GET",
+					VaultPath:             "/some/path",
+					CredentialJsonPointer: "data/foo~2",
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "valid-credential-mappings",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:            cs.GetPublicId(),
+					HttpMethod:         "GET",
+					VaultPath:          "/some/path",
+					CredentialMappings: []byte(`[{"name":"db","type":"user_password","username_pointer":"/username","password_pointer":"/password"}]`),
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:            cs.GetPublicId(),
+					HttpMethod:         "GET",
+					VaultPath:          "/some/path",
+					CredentialMappings: []byte(`[{"name":"db","type":"user_password","username_pointer":"/username","password_pointer":"/password"}]`),
+				},
+			},
+		},
+		{
+			name: "invalid-credential-mappings",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:            cs.GetPublicId(),
+					HttpMethod:         "GET",
+					VaultPath:          "/some/path",
+					CredentialMappings: []byte(`[{"name":"db","type":"user_password","username_pointer":"/username"}]`),
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "credential-mappings-with-credential-json-pointer",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:               cs.GetPublicId(),
+					HttpMethod:            "GET",
+					VaultPath:             "/some/path",
+					CredentialJsonPointer: "/data",
+					CredentialMappings:    []byte(`[{"name":"db","type":"user_password","username_pointer":"/username","password_pointer":"/password"}]`),
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "valid-wrap-ttl",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:        cs.GetPublicId(),
+					HttpMethod:     "GET",
+					VaultPath:      "/some/path",
+					WrapTtlSeconds: 300,
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:        cs.GetPublicId(),
+					HttpMethod:     "GET",
+					VaultPath:      "/some/path",
+					WrapTtlSeconds: 300,
+				},
+			},
+		},
+		{
+			name: "valid-namespace",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.GetPublicId(),
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+					Namespace:  "library-ns",
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.GetPublicId(),
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+					Namespace:  "library-ns",
+				},
+			},
+		},
+		{
+			name: "valid-form-content-type",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "POST",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("common_name=boundary.com"),
+					ContentType:     ContentTypeForm,
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "POST",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("common_name=boundary.com"),
+					ContentType:     ContentTypeForm,
+				},
+			},
+		},
+		{
+			name: "unsupported-content-type",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:     cs.GetPublicId(),
+					HttpMethod:  "POST",
+					VaultPath:   "/some/path",
+					ContentType: "text/plain",
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "whitespace-namespace",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.GetPublicId(),
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+					Namespace:  "  ",
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "valid-templated-vault-path",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:            cs.GetPublicId(),
+					HttpMethod:         "GET",
+					VaultPath:          "/database/creds/{{.Target}}-{{.Username}}",
+					TemplatedVaultPath: true,
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:            cs.GetPublicId(),
+					HttpMethod:         "GET",
+					VaultPath:          "/database/creds/{{.Target}}-{{.Username}}",
+					TemplatedVaultPath: true,
+				},
+			},
+		},
+		{
+			name: "invalid-templated-vault-path-unknown-variable",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:            cs.GetPublicId(),
+					HttpMethod:         "GET",
+					VaultPath:          "/database/creds/{{.Role}}",
+					TemplatedVaultPath: true,
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "valid-untemplated-vault-path-not-validated",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.GetPublicId(),
+					HttpMethod: "GET",
+					VaultPath:  "/database/creds/{{.Role}}",
+				},
+			},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.GetPublicId(),
+					HttpMethod: "GET",
+					VaultPath:  "/database/creds/{{.Role}}",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -175,12 +426,13 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			require.NoError(err)
 			require.NotNil(repo)
 			got, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), tt.in, tt.opts...)
 			if tt.wantErr != 0 {
 				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Equal(tt.wantErrField, errors.Field(err))
 				assert.Nil(got)
 				return
 			}
@@ -191,6 +443,12 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 			assert.NotSame(tt.in, got)
 			assert.Equal(tt.want.Name, got.Name)
 			assert.Equal(tt.want.Description, got.Description)
+			assert.Equal(tt.want.CredentialJsonPointer, got.CredentialJsonPointer)
+			assert.Equal(tt.want.TemplatedVaultPath, got.TemplatedVaultPath)
+			assert.Equal(tt.want.CredentialMappings, got.CredentialMappings)
+			assert.Equal(tt.want.WrapTtlSeconds, got.WrapTtlSeconds)
+			assert.Equal(tt.want.Namespace, got.Namespace)
+			assert.Equal(tt.want.ContentType, got.ContentType)
 			assert.Equal(got.CreateTime, got.UpdateTime)
 			assert.NoError(db.TestVerifyOplog(t, rw, got.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
 		})
@@ -201,7 +459,7 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		require.NoError(err)
 		require.NotNil(repo)
 		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
@@ -229,12 +487,119 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 		assert.Nil(got2)
 	})
 
+	t.Run("store-in-different-scope", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(err)
+		require.NotNil(repo)
+		iamRepo := iam.TestRepo(t, conn, wrapper)
+		_, prjA := iam.TestScopes(t, iamRepo)
+		_, prjB := iam.TestScopes(t, iamRepo)
+		csA := TestCredentialStores(t, conn, wrapper, prjA.GetPublicId(), 1)[0]
+
+		in := &CredentialLibrary{
+			CredentialLibrary: &store.CredentialLibrary{
+				StoreId:    csA.GetPublicId(),
+				HttpMethod: "GET",
+				VaultPath:  "/some/path",
+			},
+		}
+		got, err := repo.CreateCredentialLibrary(ctx, prjB.GetPublicId(), in)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+
+		libs, err := repo.ListCredentialLibraries(ctx, csA.GetPublicId())
+		require.NoError(err)
+		assert.Empty(libs, "no library should be created")
+
+		in.StoreId = "csvlt_1234567890"
+		got, err = repo.CreateCredentialLibrary(ctx, prjA.GetPublicId(), in)
+		assert.Truef(errors.Match(errors.T(errors.RecordNotFound), err), "want err: %q got: %q", errors.RecordNotFound, err)
+		assert.Nil(got)
+	})
+
+	t.Run("pki-body-validation", func(t *testing.T) {
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		newLib := func(body string) *CredentialLibrary {
+			return &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "POST",
+					VaultPath:       "/pki/issue/boundary",
+					HttpRequestBody: []byte(body),
+				},
+			}
+		}
+
+		t.Run("well-formed", func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), newLib(`{"common_name":"boundary.example.com"}`), WithPkiBodyValidation(true))
+			require.NoError(err)
+			require.NotNil(got)
+			assert.Equal(`{"common_name":"boundary.example.com"}`, string(got.HttpRequestBody))
+		})
+		t.Run("malformed", func(t *testing.T) {
+			assert := assert.New(t)
+			got, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), newLib(`{"ttl":"1h"}`), WithPkiBodyValidation(true))
+			assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+			assert.Contains(err.Error(), "common_name")
+			assert.Nil(got)
+		})
+		t.Run("malformed-without-validation", func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), newLib(`{"ttl":"1h"}`))
+			require.NoError(err)
+			assert.NotNil(got)
+		})
+	})
+
+	t.Run("invalid-duplicate-names-preflight-check", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(err)
+		require.NotNil(repo)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		in := &CredentialLibrary{
+			CredentialLibrary: &store.CredentialLibrary{
+				StoreId:    cs.GetPublicId(),
+				HttpMethod: "GET",
+				VaultPath:  "/some/path",
+				Name:       "test-name-repo",
+			},
+		}
+
+		got, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), in, WithPreflightNameCheck(true))
+		require.NoError(err)
+		require.NotNil(got)
+
+		got2, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), in, WithPreflightNameCheck(true))
+		assert.Truef(errors.Match(errors.T(errors.NotUnique), err), "want err: %q got: %q", errors.NotUnique, err)
+		assert.Nil(got2)
+		// The preflight error is not a wrapped database error.
+		assert.Nil(stderrors.Unwrap(err))
+		assert.Contains(err.Error(), "a credential library named test-name-repo already exists")
+	})
+
 	t.Run("valid-duplicate-names-diff-stores", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		require.NoError(err)
 		require.NotNil(repo)
 
@@ -274,24 +639,230 @@ func TestRepository_CreateCredentialLibrary(t *testing.T) {
 	})
 }
 
-func TestRepository_UpdateCredentialLibrary(t *testing.T) {
+func TestRepository_ValidateCredentialLibrary(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
 	rw := db.New(conn)
 	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
 
-	changeHttpRequestBody := func(b []byte) func(*CredentialLibrary) *CredentialLibrary {
-		return func(l *CredentialLibrary) *CredentialLibrary {
-			l.HttpRequestBody = b
-			return l
-		}
-	}
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+	_, otherPrj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	otherCs := TestCredentialStores(t, conn, wrapper, otherPrj.GetPublicId(), 1)[0]
+
+	existing, err := repo.CreateCredentialLibrary(context.Background(), prj.GetPublicId(), &CredentialLibrary{
+		CredentialLibrary: &store.CredentialLibrary{
+			StoreId:    cs.GetPublicId(),
+			HttpMethod: "GET",
+			VaultPath:  "/some/path",
+			Name:       "existing-name",
+		},
+	})
+	require.NoError(t, err)
 
-	changeHttpMethod := func(m Method) func(*CredentialLibrary) *CredentialLibrary {
-		return func(l *CredentialLibrary) *CredentialLibrary {
-			l.HttpMethod = string(m)
-			return l
-		}
+	tests := []struct {
+		name    string
+		in      *CredentialLibrary
+		opts    []Option
+		wantErr errors.Code
+	}{
+		{
+			name:    "nil-CredentialLibrary",
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name:    "nil-embedded-CredentialLibrary",
+			in:      &CredentialLibrary{},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "invalid-no-store-id",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "invalid-public-id-set",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:  cs.GetPublicId(),
+					PublicId: "abcd_OOOOOOOOOO",
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "invalid-no-vault-path",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId: cs.GetPublicId(),
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "valid-no-options",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.GetPublicId(),
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+				},
+			},
+		},
+		{
+			name: "valid-POST-http-body",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "POST",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte(`{"common_name":"boundary.com"}`),
+				},
+			},
+		},
+		{
+			name: "invalid-GET-http-body",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "GET",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte(`{"common_name":"boundary.com"}`),
+				},
+			},
+			wantErr: errors.CheckConstraint,
+		},
+		{
+			name: "invalid-http-method",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.GetPublicId(),
+					HttpMethod: "DELETE",
+					VaultPath:  "/some/path",
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "invalid-credential-json-pointer",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:               cs.GetPublicId(),
+					HttpMethod:            "GET",
+					VaultPath:             "/some/path",
+					CredentialJsonPointer: "data/foo~2",
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "invalid-templated-vault-path-unknown-variable",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:            cs.GetPublicId(),
+					HttpMethod:         "GET",
+					VaultPath:          "/database/creds/{{.Role}}",
+					TemplatedVaultPath: true,
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "invalid-pki-body",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         cs.GetPublicId(),
+					HttpMethod:      "POST",
+					VaultPath:       "/pki/issue/boundary",
+					HttpRequestBody: []byte(`{"ttl":"1h"}`),
+				},
+			},
+			opts:    []Option{WithPkiBodyValidation(true)},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "invalid-store-not-found",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    CredentialStorePrefix + "_1234567890",
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+				},
+			},
+			wantErr: errors.RecordNotFound,
+		},
+		{
+			name: "invalid-store-in-other-scope",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    otherCs.GetPublicId(),
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+				},
+			},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "invalid-duplicate-name",
+			in: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    cs.GetPublicId(),
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+					Name:       existing.GetName(),
+				},
+			},
+			wantErr: errors.NotUnique,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := context.Background()
+			before, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId(), WithLimit(-1))
+			require.NoError(err)
+
+			err = repo.ValidateCredentialLibrary(ctx, prj.GetPublicId(), tt.in, tt.opts...)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+			} else {
+				assert.NoError(err)
+			}
+
+			after, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId(), WithLimit(-1))
+			require.NoError(err)
+			assert.Len(after, len(before), "validation should not create a credential library")
+		})
+	}
+}
+
+func TestRepository_UpdateCredentialLibrary(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+
+	changeHttpRequestBody := func(b []byte) func(*CredentialLibrary) *CredentialLibrary {
+		return func(l *CredentialLibrary) *CredentialLibrary {
+			l.HttpRequestBody = b
+			return l
+		}
+	}
+
+	changeHttpMethod := func(m Method) func(*CredentialLibrary) *CredentialLibrary {
+		return func(l *CredentialLibrary) *CredentialLibrary {
+			l.HttpMethod = string(m)
+			return l
+		}
 	}
 
 	makeHttpMethodEmptyString := func() func(*CredentialLibrary) *CredentialLibrary {
@@ -322,6 +893,20 @@ func TestRepository_UpdateCredentialLibrary(t *testing.T) {
 		}
 	}
 
+	changeNamespace := func(n string) func(*CredentialLibrary) *CredentialLibrary {
+		return func(l *CredentialLibrary) *CredentialLibrary {
+			l.Namespace = n
+			return l
+		}
+	}
+
+	changeContentType := func(ct string) func(*CredentialLibrary) *CredentialLibrary {
+		return func(l *CredentialLibrary) *CredentialLibrary {
+			l.ContentType = ct
+			return l
+		}
+	}
+
 	makeNil := func() func(*CredentialLibrary) *CredentialLibrary {
 		return func(l *CredentialLibrary) *CredentialLibrary {
 			return nil
@@ -493,6 +1078,50 @@ func TestRepository_UpdateCredentialLibrary(t *testing.T) {
 			},
 			wantCount: 1,
 		},
+		{
+			name: "whitespace-namespace",
+			orig: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+					Namespace:  "library-ns",
+				},
+			},
+			chgFn:   changeNamespace(" "),
+			masks:   []string{namespaceField},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "change-content-type",
+			orig: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod: "POST",
+					VaultPath:  "/some/path",
+				},
+			},
+			chgFn: changeContentType(ContentTypeForm),
+			masks: []string{contentTypeField},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod:  "POST",
+					VaultPath:   "/some/path",
+					ContentType: ContentTypeForm,
+				},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "unsupported-content-type",
+			orig: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod: "POST",
+					VaultPath:  "/some/path",
+				},
+			},
+			chgFn:   changeContentType("text/plain"),
+			masks:   []string{contentTypeField},
+			wantErr: errors.InvalidParameter,
+		},
 		{
 			name: "change-name-and-description",
 			orig: &CredentialLibrary{
@@ -739,59 +1368,144 @@ func TestRepository_UpdateCredentialLibrary(t *testing.T) {
 			wantErr: errors.CheckConstraint,
 		},
 		{
-			name: "change-method-to-POST-add-request-body",
+			name: "change-method-POST-to-PUT-keep-request-body",
 			orig: &CredentialLibrary{
 				CredentialLibrary: &store.CredentialLibrary{
-					HttpMethod: "GET",
-					VaultPath:  "/some/path",
+					HttpMethod:      "POST",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("request body"),
 				},
 			},
-			chgFn: combine(changeHttpRequestBody([]byte("new request body")), changeHttpMethod(MethodPost)),
-			masks: []string{httpRequestBodyField, httpMethodField},
+			chgFn: changeHttpMethod(MethodPut),
+			masks: []string{httpMethodField},
 			want: &CredentialLibrary{
 				CredentialLibrary: &store.CredentialLibrary{
-					HttpMethod:      "POST",
+					HttpMethod:      "PUT",
 					VaultPath:       "/some/path",
-					HttpRequestBody: []byte("new request body"),
+					HttpRequestBody: []byte("request body"),
 				},
 			},
 			wantCount: 1,
 		},
-	}
-
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			assert, require := assert.New(t), require.New(t)
-			ctx := context.Background()
-			kms := kms.TestKms(t, conn, wrapper)
-			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
-			assert.NoError(err)
-			require.NotNil(repo)
-
-			_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
-			cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
-
-			tt.orig.StoreId = cs.GetPublicId()
-			orig, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), tt.orig)
-			assert.NoError(err)
-			require.NotNil(orig)
-
-			if tt.chgFn != nil {
-				orig = tt.chgFn(orig)
-			}
-			got, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), orig, 1, tt.masks)
-			if tt.wantErr != 0 {
-				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
-				assert.Equal(tt.wantCount, gotCount, "row count")
-				assert.Nil(got)
-				return
-			}
-			assert.NoError(err)
-			assert.Empty(tt.orig.PublicId)
-			require.NotNil(got)
-			assertPublicId(t, CredentialLibraryPrefix, got.GetPublicId())
+		{
+			name: "change-method-PUT-to-PATCH-keep-request-body",
+			orig: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod:      "PUT",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("request body"),
+				},
+			},
+			chgFn: changeHttpMethod(MethodPatch),
+			masks: []string{httpMethodField},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod:      "PATCH",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("request body"),
+				},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "change-method-PATCH-to-GET-leave-request-body",
+			orig: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod:      "PATCH",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("request body"),
+				},
+			},
+			chgFn:   changeHttpMethod(MethodGet),
+			masks:   []string{httpMethodField},
+			wantErr: errors.CheckConstraint,
+		},
+		{
+			name: "delete-PUT-method-leave-request-body",
+			orig: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod:      "PUT",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("request body"),
+				},
+			},
+			chgFn:   makeHttpMethodEmptyString(),
+			masks:   []string{httpMethodField},
+			wantErr: errors.CheckConstraint,
+		},
+		{
+			name: "change-method-to-PATCH-add-request-body",
+			orig: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+				},
+			},
+			chgFn: combine(changeHttpRequestBody([]byte("new request body")), changeHttpMethod(MethodPatch)),
+			masks: []string{httpRequestBodyField, httpMethodField},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod:      "PATCH",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("new request body"),
+				},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "change-method-to-POST-add-request-body",
+			orig: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod: "GET",
+					VaultPath:  "/some/path",
+				},
+			},
+			chgFn: combine(changeHttpRequestBody([]byte("new request body")), changeHttpMethod(MethodPost)),
+			masks: []string{httpRequestBodyField, httpMethodField},
+			want: &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					HttpMethod:      "POST",
+					VaultPath:       "/some/path",
+					HttpRequestBody: []byte("new request body"),
+				},
+			},
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := context.Background()
+			kms := kms.TestKms(t, conn, wrapper)
+			sche := scheduler.TestScheduler(t, conn, wrapper)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
+			assert.NoError(err)
+			require.NotNil(repo)
+
+			_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+			cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+
+			tt.orig.StoreId = cs.GetPublicId()
+			orig, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), tt.orig)
+			assert.NoError(err)
+			require.NotNil(orig)
+
+			if tt.chgFn != nil {
+				orig = tt.chgFn(orig)
+			}
+			got, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), orig, 1, tt.masks)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Equal(tt.wantCount, gotCount, "row count")
+				assert.Nil(got)
+				return
+			}
+			assert.NoError(err)
+			assert.Empty(tt.orig.PublicId)
+			require.NotNil(got)
+			assertPublicId(t, CredentialLibraryPrefix, got.GetPublicId())
 			assert.Equal(tt.wantCount, gotCount, "row count")
 			assert.NotSame(tt.orig, got)
 			assert.Equal(tt.orig.StoreId, got.StoreId)
@@ -814,12 +1528,184 @@ func TestRepository_UpdateCredentialLibrary(t *testing.T) {
 		})
 	}
 
+	t.Run("credential-json-pointer", func(t *testing.T) {
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		lib := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+
+		assert, require := assert.New(t), require.New(t)
+		lib.CredentialJsonPointer = "/data/foo/bar"
+		got, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), lib, 1, []string{credentialJsonPointerField})
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(1, gotCount)
+		assert.Equal("/data/foo/bar", got.CredentialJsonPointer)
+
+		got.CredentialJsonPointer = "data/foo"
+		got2, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got, got.Version, []string{credentialJsonPointerField})
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Equal(db.NoRowsAffected, gotCount)
+		assert.Nil(got2)
+
+		got.CredentialJsonPointer = ""
+		got3, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got, got.Version, []string{credentialJsonPointerField})
+		require.NoError(err)
+		require.NotNil(got3)
+		assert.Equal(1, gotCount)
+		underlyingDB, err := conn.SqlDB(ctx)
+		require.NoError(err)
+		dbassert.New(t, underlyingDB).IsNull(got3, "credential_json_pointer")
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		lib := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+
+		assert, require := assert.New(t), require.New(t)
+		lib.Namespace = "library-ns"
+		got, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), lib, 1, []string{namespaceField})
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(1, gotCount)
+		assert.Equal("library-ns", got.Namespace)
+
+		got.Namespace = ""
+		got2, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got, got.Version, []string{namespaceField})
+		require.NoError(err)
+		require.NotNil(got2)
+		assert.Equal(1, gotCount)
+		assert.Empty(got2.Namespace)
+		underlyingDB, err := conn.SqlDB(ctx)
+		require.NoError(err)
+		dbassert.New(t, underlyingDB).IsNull(got2, "namespace")
+	})
+
+	t.Run("content-type", func(t *testing.T) {
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		lib := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+
+		assert, require := assert.New(t), require.New(t)
+		lib.ContentType = ContentTypeForm
+		got, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), lib, 1, []string{contentTypeField})
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(1, gotCount)
+		assert.Equal(ContentTypeForm, got.ContentType)
+
+		got.ContentType = ""
+		got2, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got, got.Version, []string{contentTypeField})
+		require.NoError(err)
+		require.NotNil(got2)
+		assert.Equal(1, gotCount)
+		assert.Empty(got2.ContentType)
+		underlyingDB, err := conn.SqlDB(ctx)
+		require.NoError(err)
+		dbassert.New(t, underlyingDB).IsNull(got2, "content_type")
+	})
+
+	t.Run("with-changes", func(t *testing.T) {
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		lib := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+
+		assert, require := assert.New(t), require.New(t)
+		lib.Name = "changed-name"
+		lib.Description = "changed-description"
+		got, changes, gotCount, err := repo.UpdateCredentialLibraryWithChanges(ctx, prj.GetPublicId(), lib, 1, []string{nameField, descriptionField})
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(1, gotCount)
+		assert.ElementsMatch([]string{nameField, descriptionField}, changes)
+
+		// setting the same values again is a no-op
+		got2, changes, gotCount, err := repo.UpdateCredentialLibraryWithChanges(ctx, prj.GetPublicId(), got, got.Version, []string{nameField, descriptionField, vaultPathField})
+		require.NoError(err)
+		require.NotNil(got2)
+		assert.Equal(1, gotCount)
+		assert.Empty(changes)
+	})
+
+	t.Run("templated-vault-path", func(t *testing.T) {
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		lib := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+
+		assert, require := assert.New(t), require.New(t)
+		lib.VaultPath = "/database/creds/{{.Username}}"
+		got, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), lib, 1, []string{vaultPathField})
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(1, gotCount)
+		assert.False(got.TemplatedVaultPath)
+
+		// enabling the templated vault path validates the stored vault path
+		got.TemplatedVaultPath = true
+		got2, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got, got.Version, []string{templatedVaultPathField})
+		require.NoError(err)
+		require.NotNil(got2)
+		assert.Equal(1, gotCount)
+		assert.True(got2.TemplatedVaultPath)
+
+		// updating the vault path validates it against the stored flag
+		got2.VaultPath = "/database/creds/{{.Role}}"
+		got3, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got2, got2.Version, []string{vaultPathField})
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Equal(db.NoRowsAffected, gotCount)
+		assert.Nil(got3)
+
+		// disabling the templated vault path skips validation
+		got2.TemplatedVaultPath = false
+		got4, gotCount, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got2, got2.Version, []string{vaultPathField, templatedVaultPathField})
+		require.NoError(err)
+		require.NotNil(got4)
+		assert.Equal(1, gotCount)
+		assert.False(got4.TemplatedVaultPath)
+		assert.Equal("/database/creds/{{.Role}}", got4.VaultPath)
+	})
+
 	t.Run("invalid-duplicate-names", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		assert.NoError(err)
 		require.NotNil(repo)
 
@@ -853,7 +1739,7 @@ func TestRepository_UpdateCredentialLibrary(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		assert.NoError(err)
 		require.NotNil(repo)
 
@@ -901,7 +1787,7 @@ func TestRepository_UpdateCredentialLibrary(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		assert.NoError(err)
 		require.NotNil(repo)
 
@@ -927,149 +1813,1288 @@ func TestRepository_UpdateCredentialLibrary(t *testing.T) {
 		assert.Equal(1, gotCount1, "row count")
 		assert.NoError(db.TestVerifyOplog(t, rw, lA.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_UPDATE), db.WithCreateNotBefore(10*time.Second)))
 	})
+
+	t.Run("delete-http-method-with-default-http-method", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ctx := context.Background()
+		kms := kms.TestKms(t, conn, wrapper)
+		sche := scheduler.TestScheduler(t, conn, wrapper)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche, WithDefaultHttpMethod(MethodPost))
+		assert.NoError(err)
+		require.NotNil(repo)
+
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+
+		in := &CredentialLibrary{
+			CredentialLibrary: &store.CredentialLibrary{
+				StoreId:   cs.GetPublicId(),
+				VaultPath: "/some/path",
+			},
+		}
+		orig, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), in)
+		assert.NoError(err)
+		require.NotNil(orig)
+		assert.Equal(string(MethodPost), orig.HttpMethod)
+
+		orig.HttpMethod = string(MethodGet)
+		got1, gotCount1, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), orig, 1, []string{httpMethodField})
+		assert.NoError(err)
+		require.NotNil(got1)
+		assert.Equal(string(MethodGet), got1.HttpMethod)
+		assert.Equal(1, gotCount1, "row count")
+
+		// deleting the method applies the configured default
+		got1.HttpMethod = ""
+		got2, gotCount2, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got1, 2, []string{httpMethodField})
+		assert.NoError(err)
+		require.NotNil(got2)
+		assert.Equal(string(MethodPost), got2.HttpMethod)
+		assert.Equal(1, gotCount2, "row count")
+
+		// the configured default still has to respect the body/method
+		// constraint
+		getRepo, err := NewRepository(ctx, rw, rw, kms, sche)
+		assert.NoError(err)
+		require.NotNil(getRepo)
+		got2.HttpRequestBody = []byte("request body")
+		got3, gotCount3, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got2, 3, []string{httpRequestBodyField})
+		assert.NoError(err)
+		require.NotNil(got3)
+		assert.Equal(1, gotCount3, "row count")
+
+		got3.HttpMethod = ""
+		got4, gotCount4, err := getRepo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), got3, 4, []string{httpMethodField})
+		assert.Truef(errors.Match(errors.T(errors.CheckConstraint), err), "want err code: %v got err: %v", errors.CheckConstraint, err)
+		assert.Nil(got4)
+		assert.Equal(db.NoRowsAffected, gotCount4, "row count")
+	})
 }
 
-func TestRepository_LookupCredentialLibrary(t *testing.T) {
+func TestRepository_CreateCredentialLibraryFromTemplate(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
 	rw := db.New(conn)
 	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
 
 	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
-	l := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+	tmpl, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), &CredentialLibrary{
+		CredentialLibrary: &store.CredentialLibrary{
+			StoreId:         cs.GetPublicId(),
+			Name:            "pki-template",
+			VaultPath:       "/pki/issue/{{role}}",
+			HttpMethod:      string(MethodPost),
+			HttpRequestBody: []byte(`{"common_name":"{{common_name}}"}`),
+			IsTemplate:      true,
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, tmpl)
+	lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), &CredentialLibrary{
+		CredentialLibrary: &store.CredentialLibrary{
+			StoreId:   cs.GetPublicId(),
+			VaultPath: "/pki/issue/{{role}}",
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, lib)
 
 	badId, err := newCredentialLibraryId()
 	require.NoError(t, err)
-	require.NotNil(t, badId)
-
-	tests := []struct {
-		name    string
-		in      string
-		want    *CredentialLibrary
-		wantErr errors.Code
-	}{
-		{
-			name: "valid",
-			in:   l.GetPublicId(),
-			want: l,
-		},
-		{
-			name:    "empty-public-id",
-			in:      "",
-			wantErr: errors.InvalidParameter,
-		},
-		{
-			name: "not-found",
-			in:   badId,
-		},
-	}
 
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			assert, require := assert.New(t), require.New(t)
-			ctx := context.Background()
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		vars := map[string]string{
+			"role":        "boundary",
+			"common_name": "web.example.com",
+		}
+		got, err := repo.CreateCredentialLibraryFromTemplate(ctx, prj.GetPublicId(), tmpl.GetPublicId(), vars, WithName("web"))
+		require.NoError(err)
+		require.NotNil(got)
+		assertPublicId(t, CredentialLibraryPrefix, got.GetPublicId())
+		assert.NotEqual(tmpl.GetPublicId(), got.GetPublicId())
+		assert.Equal(cs.GetPublicId(), got.GetStoreId())
+		assert.Equal("web", got.GetName())
+		assert.Equal("/pki/issue/boundary", got.GetVaultPath())
+		assert.Equal(string(MethodPost), got.GetHttpMethod())
+		assert.Equal(`{"common_name":"web.example.com"}`, string(got.GetHttpRequestBody()))
+		assert.False(got.GetIsTemplate())
+		assert.NoError(db.TestVerifyOplog(t, rw, got.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+	})
+	t.Run("unresolved-placeholder", func(t *testing.T) {
+		assert := assert.New(t)
+		vars := map[string]string{
+			"role": "boundary",
+		}
+		got, err := repo.CreateCredentialLibraryFromTemplate(ctx, prj.GetPublicId(), tmpl.GetPublicId(), vars)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Contains(err.Error(), "common_name")
+		assert.Nil(got)
+	})
+	t.Run("json-escaped-body", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		vars := map[string]string{
+			"role":        "boundary",
+			"common_name": `web", "ttl":"87600h`,
+		}
+		got, err := repo.CreateCredentialLibraryFromTemplate(ctx, prj.GetPublicId(), tmpl.GetPublicId(), vars)
+		require.NoError(err)
+		require.NotNil(got)
+		var body map[string]string
+		require.NoError(json.Unmarshal(got.GetHttpRequestBody(), &body))
+		assert.Equal(map[string]string{"common_name": `web", "ttl":"87600h`}, body)
+	})
+	t.Run("not-a-template", func(t *testing.T) {
+		assert := assert.New(t)
+		vars := map[string]string{
+			"role": "boundary",
+		}
+		got, err := repo.CreateCredentialLibraryFromTemplate(ctx, prj.GetPublicId(), lib.GetPublicId(), vars)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+	})
+	t.Run("templates-not-listed", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		libs, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId())
+		require.NoError(err)
+		for _, l := range libs {
+			assert.NotEqual(tmpl.GetPublicId(), l.GetPublicId())
+		}
+		templates, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId(), WithTemplate(true))
+		require.NoError(err)
+		require.Len(templates, 1)
+		assert.Equal(tmpl.GetPublicId(), templates[0].GetPublicId())
+	})
+	t.Run("template-not-found", func(t *testing.T) {
+		assert := assert.New(t)
+		got, err := repo.CreateCredentialLibraryFromTemplate(ctx, prj.GetPublicId(), badId, nil)
+		assert.Truef(errors.Match(errors.T(errors.RecordNotFound), err), "want err: %q got: %q", errors.RecordNotFound, err)
+		assert.Nil(got)
+	})
+	t.Run("missing-template-id", func(t *testing.T) {
+		assert := assert.New(t)
+		got, err := repo.CreateCredentialLibraryFromTemplate(ctx, prj.GetPublicId(), "", nil)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+	})
+}
+
+func TestRepository_MoveCredentialLibrary(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		stores := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 2)
+		src, dst := stores[0], stores[1]
+		lib := TestCredentialLibraries(t, conn, wrapper, src.GetPublicId(), 1)[0]
+
+		got, err := repo.MoveCredentialLibrary(ctx, prj.GetPublicId(), lib.GetPublicId(), dst.GetPublicId())
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(lib.GetPublicId(), got.GetPublicId())
+		assert.Equal(dst.GetPublicId(), got.GetStoreId())
+		assert.NoError(db.TestVerifyOplog(t, rw, got.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_UPDATE), db.WithCreateNotBefore(10*time.Second)))
+
+		found, err := repo.LookupCredentialLibrary(ctx, lib.GetPublicId())
+		require.NoError(err)
+		require.NotNil(found)
+		assert.Equal(dst.GetPublicId(), found.GetStoreId())
+		assert.Equal(lib.GetVersion()+1, found.GetVersion())
+
+		srcLibs, err := repo.ListCredentialLibraries(ctx, src.GetPublicId())
+		require.NoError(err)
+		assert.Empty(srcLibs)
+		dstLibs, err := repo.ListCredentialLibraries(ctx, dst.GetPublicId())
+		require.NoError(err)
+		assert.Len(dstLibs, 1)
+	})
+
+	t.Run("name-collision", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		stores := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 2)
+		src, dst := stores[0], stores[1]
+		newLib := func(storeId string) *CredentialLibrary {
+			l, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), &CredentialLibrary{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:   storeId,
+					VaultPath: "/some/path",
+					Name:      "dup-name",
+				},
+			})
+			require.NoError(err)
+			require.NotNil(l)
+			return l
+		}
+		lib := newLib(src.GetPublicId())
+		newLib(dst.GetPublicId())
+
+		got, err := repo.MoveCredentialLibrary(ctx, prj.GetPublicId(), lib.GetPublicId(), dst.GetPublicId())
+		assert.Truef(errors.Match(errors.T(errors.NotUnique), err), "want err: %q got: %q", errors.NotUnique, err)
+		assert.Nil(got)
+
+		found, err := repo.LookupCredentialLibrary(ctx, lib.GetPublicId())
+		require.NoError(err)
+		require.NotNil(found)
+		assert.Equal(src.GetPublicId(), found.GetStoreId())
+	})
+
+	t.Run("different-scopes", func(t *testing.T) {
+		assert := assert.New(t)
+		iamRepo := iam.TestRepo(t, conn, wrapper)
+		_, prj1 := iam.TestScopes(t, iamRepo)
+		_, prj2 := iam.TestScopes(t, iamRepo)
+		src := TestCredentialStores(t, conn, wrapper, prj1.GetPublicId(), 1)[0]
+		dst := TestCredentialStores(t, conn, wrapper, prj2.GetPublicId(), 1)[0]
+		lib := TestCredentialLibraries(t, conn, wrapper, src.GetPublicId(), 1)[0]
+
+		got, err := repo.MoveCredentialLibrary(ctx, prj1.GetPublicId(), lib.GetPublicId(), dst.GetPublicId())
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+	})
+
+	t.Run("library-not-found", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		dst := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		badId, err := newCredentialLibraryId()
+		require.NoError(err)
+
+		got, err := repo.MoveCredentialLibrary(ctx, prj.GetPublicId(), badId, dst.GetPublicId())
+		assert.Truef(errors.Match(errors.T(errors.RecordNotFound), err), "want err: %q got: %q", errors.RecordNotFound, err)
+		assert.Nil(got)
+	})
+
+	t.Run("missing-parameters", func(t *testing.T) {
+		assert := assert.New(t)
+		got, err := repo.MoveCredentialLibrary(ctx, "", "clvlt_1234567890", "csvlt_1234567890")
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+		got, err = repo.MoveCredentialLibrary(ctx, "p_1234567890", "", "csvlt_1234567890")
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+		got, err = repo.MoveCredentialLibrary(ctx, "p_1234567890", "clvlt_1234567890", "")
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+	})
+}
+
+func TestRepository_ExportImportCredentialLibraries(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	t.Run("round-trip", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, srcPrj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		_, dstPrj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		src := TestCredentialStores(t, conn, wrapper, srcPrj.GetPublicId(), 1)[0]
+		dst := TestCredentialStores(t, conn, wrapper, dstPrj.GetPublicId(), 1)[0]
+
+		mappings, err := encodeCredentialMappings(ctx, []*CredentialMapping{
+			{
+				Name:            "db",
+				Type:            UserPasswordCredentialType,
+				UsernamePointer: "/username",
+				PasswordPointer: "/password",
+			},
+		})
+		require.NoError(err)
+
+		const pkiBody = `{"common_name":"boundary.com"}`
+		in := []*CredentialLibrary{
+			{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:     src.GetPublicId(),
+					Name:        "database",
+					Description: "database credentials",
+					VaultPath:   "/database/creds/opened",
+				},
+			},
+			{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:         src.GetPublicId(),
+					Name:            "pki",
+					HttpMethod:      string(MethodPost),
+					VaultPath:       "/pki/issue/boundary",
+					HttpRequestBody: []byte(pkiBody),
+					ContentType:     ContentTypeJson,
+					WrapTtlSeconds:  60,
+				},
+			},
+			{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:   src.GetPublicId(),
+					VaultPath: "/secret/data/unnamed",
+				},
+			},
+			{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:               src.GetPublicId(),
+					Name:                  "templated",
+					VaultPath:             "/secret/data/{{.Username}}",
+					TemplatedVaultPath:    true,
+					CredentialJsonPointer: "/data",
+					Namespace:             "team-a",
+				},
+			},
+			{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:            src.GetPublicId(),
+					Name:               "mapped",
+					VaultPath:          "/database/creds/mapped",
+					CredentialMappings: mappings,
+				},
+			},
+			{
+				CredentialLibrary: &store.CredentialLibrary{
+					StoreId:    src.GetPublicId(),
+					Name:       "template",
+					VaultPath:  "/database/creds/template",
+					IsTemplate: true,
+				},
+			},
+		}
+		for _, l := range in {
+			_, err := repo.CreateCredentialLibrary(ctx, srcPrj.GetPublicId(), l)
+			require.NoError(err)
+		}
+
+		data, err := repo.ExportCredentialLibraries(ctx, src.GetPublicId())
+		require.NoError(err)
+		require.NotEmpty(data)
+		assert.NotContains(string(data), src.GetPublicId(), "store ids must not be exported")
+
+		got, err := repo.ImportCredentialLibraries(ctx, dstPrj.GetPublicId(), dst.GetPublicId(), data)
+		require.NoError(err)
+		require.Len(got, len(in))
+
+		listed, err := repo.ListCredentialLibraries(ctx, dst.GetPublicId())
+		require.NoError(err)
+		assert.Len(listed, len(in)-1)
+		templates, err := repo.ListCredentialLibraries(ctx, dst.GetPublicId(), WithTemplate(true))
+		require.NoError(err)
+		assert.Len(templates, 1)
+
+		byPath := make(map[string]*CredentialLibrary, len(got))
+		for _, l := range got {
+			assertPublicId(t, CredentialLibraryPrefix, l.GetPublicId())
+			assert.Equal(dst.GetPublicId(), l.GetStoreId())
+			byPath[l.GetVaultPath()] = l
+		}
+		for _, want := range in {
+			l, ok := byPath[want.GetVaultPath()]
+			require.Truef(ok, "missing library with vault path %s", want.GetVaultPath())
+			assert.Equal(want.GetName(), l.GetName())
+			assert.Equal(want.GetDescription(), l.GetDescription())
+			if want.GetHttpMethod() != "" {
+				assert.Equal(want.GetHttpMethod(), l.GetHttpMethod())
+			}
+			assert.Equal(want.GetHttpRequestBody(), l.GetHttpRequestBody())
+			assert.Equal(want.GetCredentialJsonPointer(), l.GetCredentialJsonPointer())
+			assert.Equal(want.GetTemplatedVaultPath(), l.GetTemplatedVaultPath())
+			assert.Equal(want.GetCredentialMappings(), l.GetCredentialMappings())
+			assert.Equal(want.GetWrapTtlSeconds(), l.GetWrapTtlSeconds())
+			assert.Equal(want.GetNamespace(), l.GetNamespace())
+			assert.Equal(want.GetContentType(), l.GetContentType())
+			assert.Equal(want.GetIsTemplate(), l.GetIsTemplate())
+			assert.NoError(db.TestVerifyOplog(t, rw, l.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+		}
+
+		// importing the same libraries again fails because the names are
+		// not unique and none of the libraries are created
+		_, err = repo.ImportCredentialLibraries(ctx, dstPrj.GetPublicId(), dst.GetPublicId(), data)
+		assert.Truef(errors.Match(errors.T(errors.NotUnique), err), "want err: %q got: %q", errors.NotUnique, err)
+		listed, err = repo.ListCredentialLibraries(ctx, dst.GetPublicId())
+		require.NoError(err)
+		assert.Len(listed, len(in)-1)
+	})
+
+	t.Run("export-unknown-store", func(t *testing.T) {
+		_, err := repo.ExportCredentialLibraries(ctx, "csvlt_OOOOOOOOOO")
+		assert.Truef(t, errors.Match(errors.T(errors.RecordNotFound), err), "want err: %q got: %q", errors.RecordNotFound, err)
+	})
+
+	t.Run("import-invalid", func(t *testing.T) {
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+
+		tests := []struct {
+			name    string
+			data    string
+			wantErr errors.Code
+		}{
+			{
+				name:    "empty",
+				wantErr: errors.InvalidParameter,
+			},
+			{
+				name:    "not-json",
+				data:    "libraries",
+				wantErr: errors.InvalidParameter,
+			},
+			{
+				name:    "unknown-field",
+				data:    `{"version":1,"libraries":[{"vault_path":"/a","token":"secret"}]}`,
+				wantErr: errors.InvalidParameter,
+			},
+			{
+				name:    "unsupported-version",
+				data:    `{"version":2,"libraries":[]}`,
+				wantErr: errors.InvalidParameter,
+			},
+			{
+				name:    "missing-vault-path",
+				data:    `{"version":1,"libraries":[{"name":"a","vault_path":"/a"},{"name":"b"}]}`,
+				wantErr: errors.InvalidParameter,
+			},
+			{
+				name:    "invalid-method",
+				data:    `{"version":1,"libraries":[{"vault_path":"/a","http_method":"PUT"}]}`,
+				wantErr: errors.InvalidParameter,
+			},
+			{
+				name:    "body-with-get",
+				data:    `{"version":1,"libraries":[{"vault_path":"/a","http_method":"GET","http_request_body":"{}"}]}`,
+				wantErr: errors.CheckConstraint,
+			},
+			{
+				name:    "mappings-and-json-pointer",
+				data:    `{"version":1,"libraries":[{"vault_path":"/a","credential_json_pointer":"/data","credential_mappings":[{"name":"db","type":"user_password","username_pointer":"/u","password_pointer":"/p"}]}]}`,
+				wantErr: errors.InvalidParameter,
+			},
+			{
+				name:    "invalid-content-type",
+				data:    `{"version":1,"libraries":[{"vault_path":"/a","content_type":"text/plain"}]}`,
+				wantErr: errors.InvalidParameter,
+			},
+			{
+				name:    "duplicate-names",
+				data:    `{"version":1,"libraries":[{"name":"a","vault_path":"/a"},{"name":"a","vault_path":"/b"}]}`,
+				wantErr: errors.NotUnique,
+			},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				assert, require := assert.New(t), require.New(t)
+				got, err := repo.ImportCredentialLibraries(ctx, prj.GetPublicId(), cs.GetPublicId(), []byte(tt.data))
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
+				listed, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId())
+				require.NoError(err)
+				assert.Empty(listed)
+			})
+		}
+	})
+}
+
+func TestRepository_PrefixCredentialLibraryNames(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	newLib := func(t *testing.T, scopeId, storeId, name string) *CredentialLibrary {
+		t.Helper()
+		l, err := repo.CreateCredentialLibrary(ctx, scopeId, &CredentialLibrary{
+			CredentialLibrary: &store.CredentialLibrary{
+				StoreId:   storeId,
+				VaultPath: "/some/path",
+				Name:      name,
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		return l
+	}
+	names := func(t *testing.T, storeId string) map[string]string {
+		t.Helper()
+		libs, err := repo.ListCredentialLibraries(ctx, storeId)
+		require.NoError(t, err)
+		got := make(map[string]string, len(libs))
+		for _, l := range libs {
+			got[l.GetPublicId()] = l.GetName()
+		}
+		return got
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		stores := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 2)
+		cs, other := stores[0], stores[1]
+
+		a := newLib(t, prj.GetPublicId(), cs.GetPublicId(), "a")
+		// renaming "a" to "prod-a" must not collide with this library
+		prodA := newLib(t, prj.GetPublicId(), cs.GetPublicId(), "prod-a")
+		unnamed := newLib(t, prj.GetPublicId(), cs.GetPublicId(), "")
+		otherLib := newLib(t, prj.GetPublicId(), other.GetPublicId(), "a")
+
+		got, err := repo.PrefixCredentialLibraryNames(ctx, prj.GetPublicId(), cs.GetPublicId(), "prod-")
+		require.NoError(err)
+		assert.Equal(2, got)
+
+		assert.Equal(map[string]string{
+			a.GetPublicId():       "prod-a",
+			prodA.GetPublicId():   "prod-prod-a",
+			unnamed.GetPublicId(): "",
+		}, names(t, cs.GetPublicId()))
+		assert.Equal(map[string]string{otherLib.GetPublicId(): "a"}, names(t, other.GetPublicId()))
+
+		assert.NoError(db.TestVerifyOplogBatch(t, rw, []string{a.GetPublicId(), prodA.GetPublicId()}, db.WithOperation(oplog.OpType_OP_TYPE_UPDATE), db.WithCreateNotBefore(10*time.Second)))
+	})
+
+	t.Run("no-named-libraries", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+		newLib(t, prj.GetPublicId(), cs.GetPublicId(), "")
+
+		got, err := repo.PrefixCredentialLibraryNames(ctx, prj.GetPublicId(), cs.GetPublicId(), "prod-")
+		require.NoError(err)
+		assert.Equal(0, got)
+	})
+
+	t.Run("failed-rename-renames-none", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+
+		short := newLib(t, prj.GetPublicId(), cs.GetPublicId(), "a")
+		// prefixing the longest allowed name makes it too long
+		long := newLib(t, prj.GetPublicId(), cs.GetPublicId(), strings.Repeat("b", 127))
+		want := names(t, cs.GetPublicId())
+
+		got, err := repo.PrefixCredentialLibraryNames(ctx, prj.GetPublicId(), cs.GetPublicId(), "prod-")
+		assert.Error(err)
+		assert.Equal(db.NoRowsAffected, got)
+		assert.Equal(want, names(t, cs.GetPublicId()))
+
+		for _, l := range []*CredentialLibrary{short, long} {
+			found, err := repo.LookupCredentialLibrary(ctx, l.GetPublicId())
+			require.NoError(err)
+			assert.Equal(l.GetVersion(), found.GetVersion())
+		}
+	})
+
+	t.Run("store-in-different-scope", func(t *testing.T) {
+		assert := assert.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		_, otherPrj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+
+		got, err := repo.PrefixCredentialLibraryNames(ctx, otherPrj.GetPublicId(), cs.GetPublicId(), "prod-")
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Equal(db.NoRowsAffected, got)
+	})
+
+	t.Run("missing-parameters", func(t *testing.T) {
+		assert := assert.New(t)
+		for _, args := range [][3]string{
+			{"", "csvlt_1234567890", "prod-"},
+			{"p_1234567890", "", "prod-"},
+			{"p_1234567890", "csvlt_1234567890", ""},
+		} {
+			got, err := repo.PrefixCredentialLibraryNames(ctx, args[0], args[1], args[2])
+			assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+			assert.Equal(db.NoRowsAffected, got)
+		}
+	})
+}
+
+func TestRepository_ListCredentialLibraries_MaxLimit(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+
+	const maxLimit = 3
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche, WithMaxLimit(maxLimit))
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+	TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), maxLimit*2)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantCnt int
+	}{
+		{
+			name:    "default-limit",
+			wantCnt: maxLimit,
+		},
+		{
+			name:    "unlimited",
+			opts:    []Option{WithLimit(-1)},
+			wantCnt: maxLimit,
+		},
+		{
+			name:    "above-max",
+			opts:    []Option{WithLimit(maxLimit + 1)},
+			wantCnt: maxLimit,
+		},
+		{
+			name:    "below-max",
+			opts:    []Option{WithLimit(maxLimit - 1)},
+			wantCnt: maxLimit - 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId(), tt.opts...)
+			require.NoError(err)
+			assert.Len(got, tt.wantCnt)
+		})
+	}
+}
+
+func Test_validFieldMask(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		masks   []string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "updatable-fields",
+			masks: []string{"Name", "Description", "VaultPath", "HttpMethod", "HttpRequestBody", "CredentialMappings"},
+		},
+		{
+			name:  "case-insensitive",
+			masks: []string{"name", "DESCRIPTION", "vaultpath"},
+		},
+		{
+			name:    "read-only-public-id",
+			masks:   []string{"Name", "PublicId"},
+			wantErr: true,
+		},
+		{
+			name:    "read-only-fields",
+			masks:   []string{"PublicId", "CreateTime", "UpdateTime", "StoreId"},
+			wantErr: true,
+		},
+		{
+			name:    "read-only-version",
+			masks:   []string{"version"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown-field",
+			masks:   []string{"Bilbo"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			err := validFieldMask(context.Background(), tt.masks)
+			if tt.wantErr {
+				assert.Truef(errors.Match(errors.T(errors.InvalidFieldMask), err), "want err: %q got: %q", errors.InvalidFieldMask, err)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestRepository_LookupCredentialLibrary(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+	l := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+
+	badId, err := newCredentialLibraryId()
+	require.NoError(t, err)
+	require.NotNil(t, badId)
+
+	tests := []struct {
+		name    string
+		in      string
+		want    *CredentialLibrary
+		wantErr errors.Code
+	}{
+		{
+			name: "valid",
+			in:   l.GetPublicId(),
+			want: l,
+		},
+		{
+			name:    "empty-public-id",
+			in:      "",
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "not-found",
+			in:   badId,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := context.Background()
+			kms := kms.TestKms(t, conn, wrapper)
+			sche := scheduler.TestScheduler(t, conn, wrapper)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
+			assert.NoError(err)
+			require.NotNil(repo)
+
+			got, err := repo.LookupCredentialLibrary(ctx, tt.in)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+
+			switch {
+			case tt.want == nil:
+				assert.Nil(got)
+			case tt.want != nil:
+				assert.NotNil(got)
+				assert.Equal(got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_DeleteCredentialLibrary(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+	l := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+
+	badId, err := newCredentialLibraryId()
+	require.NoError(t, err)
+	require.NotNil(t, badId)
+
+	tests := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr errors.Code
+	}{
+		{
+			name: "found",
+			in:   l.GetPublicId(),
+			want: 1,
+		},
+		{
+			name: "not-found",
+			in:   badId,
+		},
+		{
+			name:    "empty-public-id",
+			in:      "",
+			wantErr: errors.InvalidParameter,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := context.Background()
+			kms := kms.TestKms(t, conn, wrapper)
+			sche := scheduler.TestScheduler(t, conn, wrapper)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
+			assert.NoError(err)
+			require.NotNil(repo)
+
+			got, err := repo.DeleteCredentialLibrary(ctx, prj.GetPublicId(), tt.in)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, got, "row count")
+		})
+	}
+}
+
+func TestRepository_CredentialLibrary_SkipOplog(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	newLib := func() *CredentialLibrary {
+		return &CredentialLibrary{
+			CredentialLibrary: &store.CredentialLibrary{
+				StoreId:    cs.GetPublicId(),
+				HttpMethod: "GET",
+				VaultPath:  "/some/path",
+			},
+		}
+	}
+
+	t.Run("default-writes-oplog", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ctx := context.Background()
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(err)
+		got, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), newLib())
+		require.NoError(err)
+		assert.NoError(db.TestVerifyOplog(t, rw, got.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+
+		n, err := repo.DeleteCredentialLibrary(ctx, prj.GetPublicId(), got.GetPublicId())
+		require.NoError(err)
+		assert.Equal(1, n)
+		assert.NoError(db.TestVerifyOplog(t, rw, got.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_DELETE), db.WithCreateNotBefore(10*time.Second)))
+	})
+
+	t.Run("skip-oplog", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ctx := context.Background()
+		repo, err := NewRepository(ctx, rw, rw, kms, sche, WithAllowSkipOplog(true))
+		require.NoError(err)
+		got, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), newLib(), WithSkipOplog(true))
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Error(db.TestVerifyOplog(t, rw, got.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_CREATE)))
+
+		found, err := repo.LookupCredentialLibrary(ctx, got.GetPublicId())
+		require.NoError(err)
+		assert.NotNil(found)
+
+		n, err := repo.DeleteCredentialLibrary(ctx, prj.GetPublicId(), got.GetPublicId(), WithSkipOplog(true))
+		require.NoError(err)
+		assert.Equal(1, n)
+		assert.Error(db.TestVerifyOplog(t, rw, got.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_DELETE)))
+	})
+
+	t.Run("skip-oplog-not-allowed", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ctx := context.Background()
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
+		require.NoError(err)
+		got, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), newLib(), WithSkipOplog(true))
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+
+		lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), newLib())
+		require.NoError(err)
+		n, err := repo.DeleteCredentialLibrary(ctx, prj.GetPublicId(), lib.GetPublicId(), WithSkipOplog(true))
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Equal(db.NoRowsAffected, n)
+	})
+}
+
+func TestRepository_ListCredentialLibraries(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	css := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 2)
+	csA, csB := css[0], css[1]
+
+	libs := TestCredentialLibraries(t, conn, wrapper, csA.GetPublicId(), 3)
+
+	tests := []struct {
+		name    string
+		in      string
+		opts    []Option
+		want    []*CredentialLibrary
+		wantErr errors.Code
+	}{
+		{
+			name:    "with-no-credential-store-id",
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name: "CredentialStore-with-no-libraries",
+			in:   csB.GetPublicId(),
+			want: []*CredentialLibrary{},
+		},
+		{
+			name: "CredentialStore-with-libraries",
+			in:   csA.GetPublicId(),
+			want: libs,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			assert.NoError(err)
 			require.NotNil(repo)
-
-			got, err := repo.LookupCredentialLibrary(ctx, tt.in)
+			got, err := repo.ListCredentialLibraries(ctx, tt.in, tt.opts...)
 			if tt.wantErr != 0 {
 				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
 				assert.Nil(got)
 				return
 			}
 			require.NoError(err)
-
-			switch {
-			case tt.want == nil:
-				assert.Nil(got)
-			case tt.want != nil:
-				assert.NotNil(got)
-				assert.Equal(got, tt.want)
+			opts := []cmp.Option{
+				cmpopts.SortSlices(func(x, y *CredentialLibrary) bool { return x.PublicId < y.PublicId }),
+				protocmp.Transform(),
 			}
+			assert.Empty(cmp.Diff(tt.want, got, opts...))
 		})
 	}
 }
 
-func TestRepository_DeleteCredentialLibrary(t *testing.T) {
+func TestRepository_ListCredentialLibraries_TimeRanges(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
 	rw := db.New(conn)
 	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
 
 	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
-	l := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
-
-	badId, err := newCredentialLibraryId()
+	// each library is created in its own transaction so the libraries
+	// have different create times
+	first := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+	second := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+	third := TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 1)[0]
+
+	second.Name = "updated"
+	updated, n, err := repo.UpdateCredentialLibrary(ctx, prj.GetPublicId(), second, second.GetVersion(), []string{nameField})
 	require.NoError(t, err)
-	require.NotNil(t, badId)
+	require.Equal(t, 1, n)
+
+	createTime := func(l *CredentialLibrary) time.Time { return l.GetCreateTime().AsTime() }
+	updateTime := func(l *CredentialLibrary) time.Time { return l.GetUpdateTime().AsTime() }
 
 	tests := []struct {
 		name    string
-		in      string
-		want    int
+		opts    []Option
+		want    []*CredentialLibrary
+		wantCnt int
 		wantErr errors.Code
 	}{
 		{
-			name: "found",
-			in:   l.GetPublicId(),
-			want: 1,
+			name: "created-after",
+			opts: []Option{WithCreatedAfter(createTime(first))},
+			want: []*CredentialLibrary{second, third},
 		},
 		{
-			name: "not-found",
-			in:   badId,
+			name: "created-before",
+			opts: []Option{WithCreatedBefore(createTime(third))},
+			want: []*CredentialLibrary{first, second},
 		},
 		{
-			name:    "empty-public-id",
-			in:      "",
+			name: "created-window",
+			opts: []Option{WithCreatedAfter(createTime(first)), WithCreatedBefore(createTime(third))},
+			want: []*CredentialLibrary{second},
+		},
+		{
+			name:    "created-after-with-limit",
+			opts:    []Option{WithCreatedAfter(createTime(first)), WithLimit(1)},
+			want:    []*CredentialLibrary{second, third},
+			wantCnt: 1,
+		},
+		{
+			name: "updated-after",
+			opts: []Option{WithUpdatedAfter(updateTime(third))},
+			want: []*CredentialLibrary{second},
+		},
+		{
+			name: "updated-before",
+			opts: []Option{WithUpdatedBefore(updateTime(updated))},
+			want: []*CredentialLibrary{first, third},
+		},
+		{
+			name: "created-and-updated",
+			opts: []Option{WithCreatedBefore(createTime(third)), WithUpdatedBefore(updateTime(updated))},
+			want: []*CredentialLibrary{first},
+		},
+		{
+			name: "empty-window",
+			opts: []Option{WithCreatedAfter(createTime(third))},
+		},
+		{
+			name:    "created-after-not-before-created-before",
+			opts:    []Option{WithCreatedAfter(createTime(third)), WithCreatedBefore(createTime(first))},
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name:    "updated-after-equals-updated-before",
+			opts:    []Option{WithUpdatedAfter(updateTime(first)), WithUpdatedBefore(updateTime(first))},
 			wantErr: errors.InvalidParameter,
 		},
 	}
-
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			assert, require := assert.New(t), require.New(t)
-			ctx := context.Background()
-			kms := kms.TestKms(t, conn, wrapper)
-			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
-			assert.NoError(err)
-			require.NotNil(repo)
+			got, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId(), tt.opts...)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			var gotIds, wantIds []string
+			for _, l := range got {
+				gotIds = append(gotIds, l.GetPublicId())
+			}
+			for _, l := range tt.want {
+				wantIds = append(wantIds, l.GetPublicId())
+			}
+			if tt.wantCnt > 0 {
+				// the limit applies to the libraries within the range
+				assert.Len(gotIds, tt.wantCnt)
+				assert.Subset(wantIds, gotIds)
+				return
+			}
+			assert.ElementsMatch(wantIds, gotIds)
+		})
+	}
+}
 
-			got, err := repo.DeleteCredentialLibrary(ctx, prj.GetPublicId(), tt.in)
+func TestRepository_ListCredentialLibraries_Filter(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+	newLib := func(name string, method Method) *CredentialLibrary {
+		l, err := NewCredentialLibrary(ctx, cs.GetPublicId(), "/some/path", WithName(name), WithMethod(method))
+		require.NoError(t, err)
+		l, err = repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), l)
+		require.NoError(t, err)
+		return l
+	}
+	x := newLib("x", MethodGet)
+	y := newLib("y", MethodPost)
+	z := newLib("z", MethodPost)
+
+	tests := []struct {
+		name    string
+		filter  string
+		opts    []Option
+		want    []*CredentialLibrary
+		wantErr errors.Code
+	}{
+		{
+			name: "no-filter",
+			want: []*CredentialLibrary{x, y, z},
+		},
+		{
+			name:   "name",
+			filter: `"/item/name" == "x"`,
+			want:   []*CredentialLibrary{x},
+		},
+		{
+			name:   "http-method",
+			filter: `"/item/http_method" == "POST"`,
+			want:   []*CredentialLibrary{y, z},
+		},
+		{
+			name:   "no-match",
+			filter: `"/item/name" == "none"`,
+		},
+		{
+			name:   "with-limit",
+			filter: `"/item/name" == "z"`,
+			opts:   []Option{WithLimit(1)},
+			want:   []*CredentialLibrary{z},
+		},
+		{
+			name:    "invalid-filter",
+			filter:  `not a filter`,
+			wantErr: errors.InvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId(), append(tt.opts, WithFilter(tt.filter))...)
 			if tt.wantErr != 0 {
 				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
 				return
 			}
-			assert.NoError(err)
-			assert.Equal(tt.want, got, "row count")
+			require.NoError(err)
+			var gotIds, wantIds []string
+			for _, l := range got {
+				gotIds = append(gotIds, l.GetPublicId())
+			}
+			for _, l := range tt.want {
+				wantIds = append(wantIds, l.GetPublicId())
+			}
+			assert.ElementsMatch(wantIds, gotIds)
 		})
 	}
 }
 
-func TestRepository_ListCredentialLibraries(t *testing.T) {
+// laggingReader simulates a replica which has not yet received any of the
+// rows written to the primary.
+type laggingReader struct {
+	db.Reader
+}
+
+func (laggingReader) LookupByPublicId(ctx context.Context, _ db.ResourcePublicIder, _ ...db.Option) error {
+	return errors.E(ctx, errors.WithCode(errors.RecordNotFound), errors.WithoutEvent())
+}
+
+func (laggingReader) SearchWhere(context.Context, interface{}, string, []interface{}, ...db.Option) error {
+	return nil
+}
+
+func TestRepository_StrongRead(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+	assert, require := assert.New(t), require.New(t)
+
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, laggingReader{rw}, rw, kms, sche)
+	require.NoError(err)
+	require.NotNil(repo)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+
+	lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), &CredentialLibrary{
+		CredentialLibrary: &store.CredentialLibrary{
+			StoreId:   cs.GetPublicId(),
+			VaultPath: "/some/path",
+		},
+	})
+	require.NoError(err)
+	require.NotNil(lib)
+
+	// the lagging reader does not see the new library
+	libs, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId())
+	require.NoError(err)
+	assert.Empty(libs)
+	got, err := repo.LookupCredentialLibrary(ctx, lib.GetPublicId())
+	require.NoError(err)
+	assert.Nil(got)
+
+	// a strong read does
+	libs, err = repo.ListCredentialLibraries(ctx, cs.GetPublicId(), WithStrongRead(true))
+	require.NoError(err)
+	require.Len(libs, 1)
+	assert.Equal(lib.GetPublicId(), libs[0].GetPublicId())
+	got, err = repo.LookupCredentialLibrary(ctx, lib.GetPublicId(), WithStrongRead(true))
+	require.NoError(err)
+	require.NotNil(got)
+	assert.Equal(lib.GetPublicId(), got.GetPublicId())
+
+	stores, err := repo.ListCredentialStores(ctx, []string{prj.GetPublicId()}, WithStrongRead(true))
+	require.NoError(err)
+	require.Len(stores, 1)
+	assert.Equal(cs.GetPublicId(), stores[0].GetPublicId())
+	gotStore, err := repo.LookupCredentialStore(ctx, cs.GetPublicId(), WithStrongRead(true))
+	require.NoError(err)
+	require.NotNil(gotStore)
+}
+
+func TestRepository_ListCredentialLibraryIds(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
 	rw := db.New(conn)
 	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
 
 	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 	css := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 2)
 	csA, csB := css[0], css[1]
 
 	libs := TestCredentialLibraries(t, conn, wrapper, csA.GetPublicId(), 3)
+	var ids []string
+	for _, l := range libs {
+		ids = append(ids, l.GetPublicId())
+	}
+	sort.Strings(ids)
 
 	tests := []struct {
 		name    string
 		in      string
 		opts    []Option
-		want    []*CredentialLibrary
+		want    []string
 		wantErr errors.Code
 	}{
 		{
@@ -1079,37 +3104,99 @@ func TestRepository_ListCredentialLibraries(t *testing.T) {
 		{
 			name: "CredentialStore-with-no-libraries",
 			in:   csB.GetPublicId(),
-			want: []*CredentialLibrary{},
 		},
 		{
 			name: "CredentialStore-with-libraries",
 			in:   csA.GetPublicId(),
-			want: libs,
+			want: ids,
+		},
+		{
+			name: "with-limit",
+			in:   csA.GetPublicId(),
+			opts: []Option{WithLimit(2)},
+			want: ids[:2],
+		},
+		{
+			name: "unlimited",
+			in:   csA.GetPublicId(),
+			opts: []Option{WithLimit(-1)},
+			want: ids,
 		},
 	}
 
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			assert, require := assert.New(t), require.New(t)
-			ctx := context.Background()
-			kms := kms.TestKms(t, conn, wrapper)
-			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			assert := assert.New(t)
+			got, err := repo.ListCredentialLibraryIds(context.Background(), tt.in, tt.opts...)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
+				return
+			}
 			assert.NoError(err)
-			require.NotNil(repo)
-			got, err := repo.ListCredentialLibraries(ctx, tt.in, tt.opts...)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestRepository_ListCredentialLibraries_StoreType(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	const numLibs = 5
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	cs := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 1)[0]
+	TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), numLibs)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantCnt int
+		wantErr errors.Code
+	}{
+		{
+			name:    "no-store-type",
+			wantCnt: numLibs,
+		},
+		{
+			name:    "vault-store-type",
+			opts:    []Option{WithStoreType("vault")},
+			wantCnt: numLibs,
+		},
+		{
+			name:    "vault-store-type-with-limit",
+			opts:    []Option{WithStoreType("vault"), WithLimit(2)},
+			wantCnt: 2,
+		},
+		{
+			name:    "unknown-store-type",
+			opts:    []Option{WithStoreType("plugin")},
+			wantErr: errors.InvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.ListCredentialLibraries(context.Background(), cs.GetPublicId(), tt.opts...)
 			if tt.wantErr != 0 {
 				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
 				assert.Nil(got)
 				return
 			}
 			require.NoError(err)
-			opts := []cmp.Option{
-				cmpopts.SortSlices(func(x, y *CredentialLibrary) bool { return x.PublicId < y.PublicId }),
-				protocmp.Transform(),
+			assert.Len(got, tt.wantCnt)
+			for _, l := range got {
+				assert.Equal(cs.GetPublicId(), l.GetStoreId())
 			}
-			assert.Empty(cmp.Diff(tt.want, got, opts...))
 		})
 	}
 }
@@ -1176,7 +3263,7 @@ func TestRepository_ListCredentialLibraries_Limits(t *testing.T) {
 			assert, require := assert.New(t), require.New(t)
 			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche, tt.repoOpts...)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche, tt.repoOpts...)
 			assert.NoError(err)
 			require.NotNil(repo)
 			got, err := repo.ListCredentialLibraries(ctx, libs[0].StoreId, tt.listOpts...)
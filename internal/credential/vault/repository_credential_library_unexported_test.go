@@ -0,0 +1,63 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/credential/vault/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeUpdateLibraryAuditEvent(t *testing.T) {
+	require := require.New(t)
+	ctx, got := testAuditContext(t)
+
+	const (
+		origBody    = "do-not-log-the-original-body"
+		updatedBody = "do-not-log-the-updated-body"
+	)
+	orig := &CredentialLibrary{
+		CredentialLibrary: &store.CredentialLibrary{
+			PublicId:        "clvlt_1234567890",
+			StoreId:         "csvlt_1234567890",
+			Name:            "original-name",
+			Description:     "unchanged-description",
+			VaultPath:       "/pki/issue/boundary",
+			HttpMethod:      string(MethodPost),
+			HttpRequestBody: []byte(origBody),
+		},
+	}
+	updated := orig.clone()
+	updated.Name = "updated-name"
+	updated.HttpRequestBody = []byte(updatedBody)
+
+	fieldMaskPaths := []string{nameField, descriptionField, httpRequestBodyField}
+	changes := changedLibraryFields(orig, updated, fieldMaskPaths)
+	require.Equal([]string{nameField, httpRequestBodyField}, changes)
+	require.NoError(writeUpdateLibraryAuditEvent(ctx, orig, updated, fieldMaskPaths, changes))
+
+	for _, f := range testAuditSinkFormats {
+		t.Run(string(f), func(t *testing.T) {
+			assert := assert.New(t)
+			got := got(f)
+			for _, want := range []string{
+				updateLibraryAuditOperation,
+				orig.PublicId,
+				orig.StoreId,
+				"field_mask_paths",
+				descriptionField,
+				"changed_fields",
+				nameField,
+				"original-name",
+				"updated-name",
+				httpRequestBodyField,
+				redactedHttpRequestBody,
+			} {
+				assert.Contains(got, want)
+			}
+			assert.NotContains(got, origBody)
+			assert.NotContains(got, updatedBody)
+			assert.NotContains(got, "unchanged-description", "unchanged fields should not include their values")
+		})
+	}
+}
@@ -1,20 +1,38 @@
 package vault
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/boundary/internal/credential"
 	"github.com/hashicorp/boundary/internal/db"
 	dbcommon "github.com/hashicorp/boundary/internal/db/common"
 	"github.com/hashicorp/boundary/internal/db/timestamp"
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/observability/event"
 	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/types/subtypes"
 	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	vault "github.com/hashicorp/vault/api"
 )
 
+// The operations of the audit events written when a credential store is
+// created, updated, or deleted.
+const (
+	createStoreAuditOperation = "create-credential-store"
+	updateStoreAuditOperation = "update-credential-store"
+	deleteStoreAuditOperation = "delete-credential-store"
+)
+
+// redactedStoreSecret replaces the values of a credential store's secret
+// fields in audit events.
+const redactedStoreSecret = "[REDACTED]"
+
 // CreateCredentialStore inserts cs into the repository and returns a new
 // CredentialStore containing the credential store's PublicId. cs is not
 // changed. cs must not contain a PublicId. The PublicId is generated and
@@ -27,6 +45,26 @@ import (
 // be unique within cs.ScopeId. Both cs.CreateTime and cs.UpdateTime are
 // ignored.
 //
+// If cs.Namespace is not set, the default namespace for cs.ScopeId, if one
+// has been set with SetScopeDefaultNamespace, is used.
+//
+// If cs contains an AppRole, cs must not contain a Vault token. The AppRole
+// must contain a RoleId and a SecretId. CreateCredentialStore calls the
+// /auth/approle/login Vault endpoint to obtain the Vault token for cs and
+// stores the encrypted SecretId with cs.
+//
+// If cs.TokenFilePath is set, cs must not contain a Vault token or an
+// AppRole. The file must be in the token file directory of the repository,
+// see WithTokenFileDirectory, and must contain a Vault token, which is
+// validated the same as a Vault token given to cs. The token is read from
+// the file again before each request to Vault, so a token rotated by a
+// Vault agent is picked up without updating cs. The token renewal job
+// stores a rotated token as the new current token of cs.
+//
+// Non-fatal problems with the configuration of the new credential store,
+// such as TLS verification being disabled, are available from the
+// Warnings method of the returned CredentialStore.
+//
 // For more information about the required properties of the Vault token see:
 // https://www.vaultproject.io/api-docs/auth/token#period,
 // https://www.vaultproject.io/api-docs/auth/token#renewable,
@@ -40,6 +78,7 @@ import (
 // https://www.vaultproject.io/api-docs/auth/token#lookup-a-token-self.
 func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialStore, _ ...Option) (*CredentialStore, error) {
 	const op = "vault.(Repository).CreateCredentialStore"
+	defer r.observeTiming(ctx, op, time.Now())
 	if cs == nil {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "nil CredentialStore")
 	}
@@ -49,7 +88,16 @@ func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialSt
 	if cs.ScopeId == "" {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "no scope id")
 	}
-	if len(cs.inputToken) == 0 {
+	switch {
+	case cs.TokenFilePath != "" && (cs.appRole != nil || len(cs.inputToken) != 0):
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "vault token file is mutually exclusive with vault token and approle")
+	case cs.appRole != nil && len(cs.inputToken) != 0:
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "vault token and approle are mutually exclusive")
+	case cs.appRole != nil && cs.appRole.RoleId == "":
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "approle without role id")
+	case cs.appRole != nil && len(cs.appRole.SecretId) == 0:
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "approle without secret id")
+	case cs.appRole == nil && cs.TokenFilePath == "" && len(cs.inputToken) == 0:
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "no vault token")
 	}
 	if cs.VaultAddress == "" {
@@ -61,8 +109,28 @@ func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialSt
 	if cs.clientCert != nil && len(cs.clientCert.CertificateKey) == 0 {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "client certificate without private key")
 	}
+	if _, err := tlsMinVersion(ctx, cs.TlsMinVersion); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
 
 	cs = cs.clone()
+	cs.CredentialStore.AuthMethod = string(cs.derivedAuthMethod())
+
+	if cs.TokenFilePath != "" {
+		token, err := readTokenFile(ctx, r.tokenFileDir, cs.TokenFilePath)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+		cs.inputToken = token
+	}
+
+	if cs.Namespace == "" {
+		ns, err := r.LookupScopeDefaultNamespace(ctx, cs.ScopeId)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+		cs.Namespace = ns
+	}
 
 	id, err := newCredentialStoreId()
 	if err != nil {
@@ -73,11 +141,18 @@ func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialSt
 		cs.clientCert.StoreId = id
 	}
 
-	client, err := cs.client()
+	client, err := cs.client(ctx, WithTokenFileDirectory(r.tokenFileDir), WithRateLimiters(r.limiters))
 	if err != nil {
 		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to create vault client"))
 	}
-	tokenLookup, err := client.lookupToken()
+	if cs.appRole != nil {
+		cs.appRole.StoreId = id
+		if cs.inputToken, err = client.appRoleLogin(ctx, cs.appRole.RoleId, cs.appRole.SecretId); err != nil {
+			return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to log in to vault with approle"))
+		}
+		client.swapToken(cs.inputToken)
+	}
+	tokenLookup, err := client.lookupToken(ctx)
 	if err != nil {
 		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to lookup vault token"))
 	}
@@ -85,7 +160,7 @@ func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialSt
 		return nil, err
 	}
 
-	available, err := client.capabilities(requiredCapabilities.paths())
+	available, err := client.capabilities(ctx, requiredCapabilities.paths())
 	if err != nil {
 		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault capabilities"))
 	}
@@ -95,7 +170,7 @@ func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialSt
 			errors.New(ctx, errors.VaultTokenMissingCapabilities, op, fmt.Sprintf("missing capabilites: %v", missing))
 	}
 
-	renewedToken, err := client.renewToken()
+	renewedToken, err := client.renewToken(ctx)
 	if err != nil {
 		return nil, errors.Wrap(ctx, err, op, errors.WithMsg("unable to renew vault token"))
 	}
@@ -133,6 +208,11 @@ func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialSt
 			return nil, errors.Wrap(ctx, err, op)
 		}
 	}
+	if cs.appRole != nil {
+		if err := cs.appRole.encrypt(ctx, databaseWrapper); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
 
 	var newToken *Token
 	var newClientCertificate *ClientCertificate
@@ -184,6 +264,20 @@ func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialSt
 				newCredentialStore.clientCert = newClientCertificate
 
 			}
+
+			// insert approle (if exists)
+			if cs.appRole != nil {
+				newAppRole := cs.appRole.clone()
+				var appRoleOplogMsg oplog.Message
+				if err := w.Create(ctx, newAppRole, db.NewOplogMsg(&appRoleOplogMsg)); err != nil {
+					return errors.Wrap(ctx, err, op)
+				}
+				msgs = append(msgs, &appRoleOplogMsg)
+
+				newAppRole.SecretId = nil
+				newAppRole.CtSecretId = nil
+				newCredentialStore.appRole = newAppRole
+			}
 			metadata := cs.oplog(oplog.OpType_OP_TYPE_CREATE)
 			if err := w.WriteOplogEntryWith(ctx, oplogWrapper, ticket, metadata, msgs); err != nil {
 				return errors.Wrap(ctx, err, op, errors.WithMsg("unable to write oplog"))
@@ -204,6 +298,12 @@ func (r *Repository) CreateCredentialStore(ctx context.Context, cs *CredentialSt
 	// TODO (lcr 05/2021): log error once repo has logger
 	_ = r.scheduler.UpdateJobNextRunInAtLeast(ctx, tokenRenewalJobName, token.renewalIn())
 
+	// Writing the audit event is best effort and an error should not
+	// cause the create to fail.
+	if err := writeStoreAuditEvent(ctx, createStoreAuditOperation, newCredentialStore, nil); err != nil {
+		event.WriteError(ctx, op, err, event.WithInfoMsg("unable to write credential store create audit event", "credential store id", newCredentialStore.PublicId))
+	}
+
 	return newCredentialStore, nil
 }
 
@@ -242,15 +342,17 @@ func validateTokenLookup(op errors.Op, s *vault.Secret) error {
 }
 
 // LookupCredentialStore returns the CredentialStore for publicId. Returns
-// nil, nil if no CredentialStore is found for publicId.
-func (r *Repository) LookupCredentialStore(ctx context.Context, publicId string, _ ...Option) (*CredentialStore, error) {
+// nil, nil if no CredentialStore is found for publicId. WithStrongRead is
+// the only option supported.
+func (r *Repository) LookupCredentialStore(ctx context.Context, publicId string, opt ...Option) (*CredentialStore, error) {
 	const op = "vault.(Repository).LookupCredentialStore"
+	defer r.observeTiming(ctx, op, time.Now())
 	if publicId == "" {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "no public id")
 	}
 	agg := allocPublicStore()
 	agg.PublicId = publicId
-	if err := r.reader.LookupByPublicId(ctx, agg); err != nil {
+	if err := r.readerFor(getOpts(opt...)).LookupByPublicId(ctx, agg); err != nil {
 		if errors.IsNotFoundError(err) {
 			return nil, nil
 		}
@@ -259,6 +361,30 @@ func (r *Repository) LookupCredentialStore(ctx context.Context, publicId string,
 	return agg.toCredentialStore(), nil
 }
 
+// CredentialStoreTokenStatus returns the expiry of the current Vault token
+// for the credential store storeId. The expiry is computed from the lease
+// metadata persisted when the token was added or last renewed, Vault is
+// not contacted. An errors.RecordNotFound error is returned if the
+// credential store or its current token is not found.
+func (r *Repository) CredentialStoreTokenStatus(ctx context.Context, storeId string) (*TokenExpiry, error) {
+	const op = "vault.(Repository).CredentialStoreTokenStatus"
+	if storeId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no store id")
+	}
+	cs, err := r.LookupCredentialStore(ctx, storeId)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if cs == nil {
+		return nil, errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("credential store %s not found", storeId))
+	}
+	tk := cs.Token()
+	if tk == nil || tk.GetExpirationTime() == nil {
+		return nil, errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("no current token found for credential store %s", storeId))
+	}
+	return newTokenExpiry(storeId, tk, time.Now()), nil
+}
+
 type publicStore struct {
 	PublicId             string `gorm:"primary_key"`
 	ScopeId              string
@@ -279,6 +405,17 @@ type publicStore struct {
 	TokenExpirationTime  *timestamp.Timestamp
 	ClientCert           []byte
 	ClientCertKeyHmac    []byte
+	AppRoleRoleId        string
+	AppRoleSecretIdHmac  []byte
+
+	ConnectTimeoutSeconds uint32
+	RequestTimeoutSeconds uint32
+	RequestsPerSecond     uint32
+	TlsMinVersion         string
+
+	TokenFilePath    string
+	AuthMethod       string
+	TokenIsRenewable bool
 }
 
 func allocPublicStore() *publicStore {
@@ -299,6 +436,12 @@ func (ps *publicStore) toCredentialStore() *CredentialStore {
 	cs.CaCert = ps.CaCert
 	cs.TlsServerName = ps.TlsServerName
 	cs.TlsSkipVerify = ps.TlsSkipVerify
+	cs.ConnectTimeoutSeconds = ps.ConnectTimeoutSeconds
+	cs.RequestTimeoutSeconds = ps.RequestTimeoutSeconds
+	cs.RequestsPerSecond = ps.RequestsPerSecond
+	cs.TlsMinVersion = ps.TlsMinVersion
+	cs.TokenFilePath = ps.TokenFilePath
+	cs.CredentialStore.AuthMethod = ps.AuthMethod
 
 	if ps.TokenHmac != nil {
 		tk := allocToken()
@@ -307,6 +450,7 @@ func (ps *publicStore) toCredentialStore() *CredentialStore {
 		tk.ExpirationTime = ps.TokenExpirationTime
 		tk.CreateTime = ps.TokenCreateTime
 		tk.UpdateTime = ps.TokenUpdateTime
+		tk.IsRenewable = ps.TokenIsRenewable
 		cs.outputToken = tk
 	}
 
@@ -316,6 +460,14 @@ func (ps *publicStore) toCredentialStore() *CredentialStore {
 		cert.CertificateKeyHmac = ps.ClientCertKeyHmac
 		cs.clientCert = cert
 	}
+
+	if ps.AppRoleRoleId != "" {
+		appRole := allocAppRole()
+		appRole.StoreId = ps.PublicId
+		appRole.RoleId = ps.AppRoleRoleId
+		appRole.SecretIdHmac = ps.AppRoleSecretIdHmac
+		cs.appRole = appRole
+	}
 	return cs
 }
 
@@ -332,16 +484,35 @@ func (ps *publicStore) GetPublicId() string { return ps.PublicId }
 //
 // cs must contain a valid PublicId. Only Name, Description, Namespace,
 // TlsServerName, TlsSkipVerify, CaCert, VaultAddress, ClientCertificate,
-// ClientCertificateKey, and Token can be changed. If cs.Name is set to a
+// ClientCertificateKey, ConnectTimeoutSeconds, RequestTimeoutSeconds,
+// RequestsPerSecond, TlsMinVersion, and Token can be changed. If cs.Name is set to a
 // non-empty string, it must be unique within cs.ScopeId. If Token is changed,
 // the new token must have the same properties defined in CreateCredentialStore
 // and UpdateCredentialStore calls the same Vault endpoints described in
 // CreateCredentialStore.
 //
+// If Token is changed, the replaced token is revoked after the update by
+// calling the /auth/token/revoke-accessor Vault endpoint with the new
+// token. A failure to revoke the replaced token is logged and does not
+// cause the update to fail.
+//
+// AppRoleRoleId and AppRoleSecretId can also be changed. Changing them
+// logs in to Vault with the updated AppRole to obtain a new token, so
+// Token cannot be changed at the same time. Removing the AppRole requires
+// Token to be changed.
+//
+// Token, AppRoleRoleId, and AppRoleSecretId cannot be changed if the
+// credential store reads its Vault token from a file.
+//
 // An attribute of cs will be set to NULL in the database if the attribute
 // in cs is the zero value and it is included in fieldMaskPaths.
+//
+// Non-fatal problems with the configuration of the updated credential
+// store are available from the Warnings method of the returned
+// CredentialStore.
 func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialStore, version uint32, fieldMaskPaths []string, _ ...Option) (*CredentialStore, int, error) {
 	const op = "vault.(Repository).UpdateCredentialStore"
+	defer r.observeTiming(ctx, op, time.Now())
 	if cs == nil {
 		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "missing CredentialStore")
 	}
@@ -367,11 +538,20 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 		case strings.EqualFold(namespaceField, f):
 		case strings.EqualFold(tlsServerNameField, f):
 		case strings.EqualFold(tlsSkipVerifyField, f):
+		case strings.EqualFold(tlsMinVersionField, f):
+			if _, err := tlsMinVersion(ctx, cs.TlsMinVersion); err != nil {
+				return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+			}
 		case strings.EqualFold(caCertField, f):
 		case strings.EqualFold(vaultAddressField, f):
 			validateToken = true
 		case strings.EqualFold(certificateField, f):
 		case strings.EqualFold(certificateKeyField, f):
+		case strings.EqualFold(connectTimeoutField, f):
+		case strings.EqualFold(requestTimeoutField, f):
+		case strings.EqualFold(requestsPerSecField, f):
+		case strings.EqualFold(appRoleRoleIdField, f):
+		case strings.EqualFold(appRoleSecretIdField, f):
 		case strings.EqualFold(tokenField, f):
 			if len(cs.inputToken) != 0 {
 				updateToken = true
@@ -391,6 +571,11 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 			caCertField:        cs.CaCert,
 			vaultAddressField:  cs.VaultAddress,
 			tokenField:         cs.inputToken,
+
+			connectTimeoutField: cs.ConnectTimeoutSeconds,
+			requestTimeoutField: cs.RequestTimeoutSeconds,
+			requestsPerSecField: cs.RequestsPerSecond,
+			tlsMinVersionField:  cs.TlsMinVersion,
 		},
 		fieldMaskPaths,
 		[]string{
@@ -412,7 +597,32 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 	if len(certNullFields) != 0 && len(certNullFields) != 2 {
 		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "attempting to unset a required field on a client cert")
 	}
-	if len(append(dbMask, certDbMask...)) == 0 && len(append(nullFields, certNullFields...)) == 0 {
+	var appRoleRoleId string
+	var appRoleSecretId []byte
+	if cs.appRole != nil {
+		appRoleRoleId = cs.appRole.GetRoleId()
+		appRoleSecretId = cs.appRole.GetSecretId()
+	}
+	appRoleDbMask, appRoleNullFields := dbcommon.BuildUpdatePaths(
+		map[string]interface{}{
+			appRoleRoleIdField:   appRoleRoleId,
+			appRoleSecretIdField: appRoleSecretId,
+		},
+		fieldMaskPaths, nil,
+	)
+	switch {
+	case len(appRoleNullFields) != 0 && len(appRoleNullFields) != 2:
+		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "attempting to unset a required field on an approle")
+	case len(appRoleNullFields) == 2 && !updateToken:
+		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "attempting to unset the approle without setting a vault token")
+	case len(appRoleDbMask) > 0 && updateToken:
+		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "vault token and approle are mutually exclusive")
+	case len(appRoleDbMask) > 0:
+		// a new token is obtained by logging in with the updated approle
+		updateToken = true
+		validateToken = true
+	}
+	if len(dbMask)+len(certDbMask)+len(appRoleDbMask) == 0 && len(nullFields)+len(certNullFields)+len(appRoleNullFields) == 0 {
 		return nil, db.NoRowsAffected, errors.New(ctx, errors.EmptyFieldMask, op, "missing field mask")
 	}
 
@@ -459,6 +669,40 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 	if err != nil {
 		return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg("can't recreate client certificate for vault client creation"))
 	}
+	if ps.TokenFilePath != "" && (updateToken || len(appRoleNullFields) > 0) {
+		return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "vault token file is mutually exclusive with vault token and approle")
+	}
+	if ps.AppRoleRoleId != "" {
+		if len(appRoleDbMask) == 0 && updateToken && len(appRoleNullFields) == 0 {
+			return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "vault token and approle are mutually exclusive")
+		}
+		origStore.appRole = allocAppRole()
+		origStore.appRole.StoreId = ps.PublicId
+		origStore.appRole.RoleId = ps.AppRoleRoleId
+		origStore.appRole.SecretId = ps.AppRoleSecretId
+	}
+	authMethod := origStore.AuthMethod()
+	switch {
+	case len(appRoleDbMask) > 0:
+		authMethod = AppRoleAuthMethod
+	case len(appRoleNullFields) == 2:
+		authMethod = TokenAuthMethod
+	}
+	if authMethod != origStore.AuthMethod() {
+		cs.CredentialStore.AuthMethod = string(authMethod)
+		filteredDbMask = append(filteredDbMask, authMethodField)
+	}
+	var replacedToken *Token
+	var replacedAccessor string
+	if updateToken {
+		// Look up the accessor of the token being replaced while it is
+		// still valid so it can be revoked once the update succeeds.
+		if replacedToken = ps.token(); replacedToken != nil {
+			if replacedAccessor, err = lookupTokenAccessor(ctx, origStore); err != nil {
+				event.WriteError(ctx, op, err, event.WithInfoMsg("unable to lookup accessor of replaced vault token", "credential store id", cs.PublicId))
+			}
+		}
+	}
 	updatedStore := origStore.applyUpdate(cs, fieldMaskPaths)
 
 	if len(certDbMask) > 0 && updatedStore.clientCert != nil {
@@ -468,12 +712,28 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 	}
 
 	var token *Token
-	client, err := updatedStore.client()
+	client, err := updatedStore.client(ctx, WithTokenFileDirectory(r.tokenFileDir), WithRateLimiters(r.limiters))
 	if err != nil {
 		return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg("unable to get client for updated store"))
 	}
+	if len(appRoleDbMask) > 0 {
+		switch {
+		case updatedStore.appRole.RoleId == "":
+			return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "approle without role id")
+		case len(updatedStore.appRole.SecretId) == 0:
+			return nil, db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "approle without secret id")
+		}
+		if cs.inputToken, err = client.appRoleLogin(ctx, updatedStore.appRole.RoleId, updatedStore.appRole.SecretId); err != nil {
+			return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg("unable to log in to vault with approle"))
+		}
+		updatedStore.inputToken = cs.inputToken
+		client.swapToken(cs.inputToken)
+		if err := updatedStore.appRole.encrypt(ctx, databaseWrapper); err != nil {
+			return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
+		}
+	}
 	if validateToken {
-		tokenLookup, err := client.lookupToken()
+		tokenLookup, err := client.lookupToken(ctx)
 		if err != nil {
 			return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg("cannot lookup token for updated store"))
 		}
@@ -481,7 +741,7 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 			return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op)
 		}
 
-		available, err := client.capabilities(requiredCapabilities.paths())
+		available, err := client.capabilities(ctx, requiredCapabilities.paths())
 		if err != nil {
 			return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault capabilities"))
 		}
@@ -493,7 +753,7 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 		}
 	}
 	if updateToken {
-		renewedToken, err := client.renewToken()
+		renewedToken, err := client.renewToken(ctx)
 		if err != nil {
 			return nil, db.NoRowsAffected, errors.Wrap(ctx, err, op, errors.WithMsg("unable to renew vault token"))
 		}
@@ -591,6 +851,32 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 				}
 			}
 
+			switch {
+			case len(appRoleNullFields) == 2:
+				// Delete the approle
+				deleteAppRole := allocAppRole()
+				deleteAppRole.StoreId = cs.GetPublicId()
+				query, values := deleteAppRole.deleteQuery()
+				rows, err := w.Exec(ctx, query, values)
+				if err != nil {
+					return errors.Wrap(ctx, err, op, errors.WithMsg("unable to delete approle"))
+				}
+				if rows > 1 {
+					return errors.New(ctx, errors.MultipleRecords, op, "more than 1 approle would have been deleted")
+				}
+				msgs = append(msgs, deleteAppRole.oplogMessage(db.DeleteOp))
+			case len(appRoleDbMask) > 0:
+				query, values := updatedStore.appRole.insertQuery()
+				rows, err := w.Exec(ctx, query, values)
+				if err != nil {
+					return errors.Wrap(ctx, err, op, errors.WithMsg("unable to upsert approle"))
+				}
+				if rows > 1 {
+					return errors.New(ctx, errors.MultipleRecords, op, "more than 1 approle would have been upserted")
+				}
+				msgs = append(msgs, updatedStore.appRole.oplogMessage(db.UpdateOp))
+			}
+
 			if updateToken {
 				query, values := token.insertQuery()
 				rows, err := w.Exec(ctx, query, values)
@@ -634,24 +920,101 @@ func (r *Repository) UpdateCredentialStore(ctx context.Context, cs *CredentialSt
 		_ = r.scheduler.UpdateJobNextRunInAtLeast(ctx, tokenRenewalJobName, token.renewalIn())
 	}
 
+	if updateToken && rowsUpdated == 1 && replacedAccessor != "" && !bytes.Equal(replacedToken.TokenHmac, token.TokenHmac) {
+		// Best effort revoke of the replaced token, an error should not
+		// cause update to fail.
+		if err := r.revokeReplacedToken(ctx, client, replacedAccessor, replacedToken); err != nil {
+			event.WriteError(ctx, op, err, event.WithInfoMsg("unable to revoke replaced vault token", "credential store id", cs.PublicId))
+		}
+	}
+
+	if rowsUpdated == 1 {
+		changes := make([]string, 0, len(dbMask)+len(nullFields)+len(certDbMask)+len(certNullFields)+len(appRoleDbMask)+len(appRoleNullFields)+1)
+		for _, m := range [][]string{dbMask, nullFields, certDbMask, certNullFields, appRoleDbMask, appRoleNullFields} {
+			changes = append(changes, m...)
+		}
+		if updateToken && !strutil.StrListContains(changes, tokenField) {
+			// the token is replaced when the approle is updated
+			changes = append(changes, tokenField)
+		}
+		// Writing the audit event is best effort and an error should not
+		// cause the update to fail.
+		if err := writeStoreAuditEvent(ctx, updateStoreAuditOperation, cs, changes); err != nil {
+			event.WriteError(ctx, op, err, event.WithInfoMsg("unable to write credential store update audit event", "credential store id", cs.PublicId))
+		}
+	}
+
 	return returnedCredentialStore, rowsUpdated, nil
 }
 
+// lookupTokenAccessor returns the accessor of cs's current token by
+// calling the /auth/token/lookup-self Vault endpoint with that token.
+func lookupTokenAccessor(ctx context.Context, cs *CredentialStore) (string, error) {
+	const op = "vault.lookupTokenAccessor"
+	c, err := cs.client(ctx)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, op)
+	}
+	t, err := c.lookupToken(ctx)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, op)
+	}
+	accessor, err := t.TokenAccessor()
+	if err != nil {
+		return "", errors.Wrap(ctx, err, op, errors.WithMsg("unable to get vault token accessor"))
+	}
+	return accessor, nil
+}
+
+// revokeReplacedToken revokes the Vault token identified by accessor using
+// c and sets the status of tk, and of the credentials retrieved with tk, to
+// revoked since Vault cascades the revocation to them.
+func (r *Repository) revokeReplacedToken(ctx context.Context, c *client, accessor string, tk *Token) error {
+	const op = "vault.(Repository).revokeReplacedToken"
+	if err := c.revokeTokenAccessor(ctx, accessor); err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to revoke vault token"))
+	}
+
+	query, values := tk.updateStatusQuery(RevokedToken)
+	numRows, err := r.writer.Exec(ctx, query, values)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	if numRows != 1 {
+		return errors.New(ctx, errors.Unknown, op, "token revoked but failed to update repo")
+	}
+
+	_, err = r.writer.Exec(ctx, updateCredentialStatusByTokenQuery, []interface{}{RevokedCredential, tk.TokenHmac})
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("error updating credentials to revoked after revoking token"))
+	}
+	return nil
+}
+
 // ListCredentialStores returns a slice of CredentialStores for the
-// scopeIds. WithLimit is the only option supported.
+// scopeIds. WithLimit, WithStoreType, and WithStrongRead are the only
+// options supported. If WithStoreType is set to a registered credential
+// store type other than vault, an empty slice is returned. An unknown store
+// type returns an errors.InvalidParameter error.
 func (r *Repository) ListCredentialStores(ctx context.Context, scopeIds []string, opt ...Option) ([]*CredentialStore, error) {
 	const op = "vault.(Repository).ListCredentialStores"
+	defer r.observeTiming(ctx, op, time.Now())
 	if len(scopeIds) == 0 {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "no scopeIds")
 	}
 	opts := getOpts(opt...)
-	limit := r.defaultLimit
-	if opts.withLimit != 0 {
-		// non-zero signals an override of the default limit for the repo.
-		limit = opts.withLimit
+	if opts.withStoreType != "" {
+		switch credential.SubtypeFromType(opts.withStoreType) {
+		case Subtype:
+		case subtypes.UnknownSubtype:
+			return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unknown store type: %s", opts.withStoreType))
+		default:
+			return nil, nil
+		}
 	}
+	limit := r.listLimit(opts)
 	var credentialStores []*publicStore
-	err := r.reader.SearchWhere(ctx, &credentialStores, "scope_id in (?)", []interface{}{scopeIds}, db.WithLimit(limit))
+	err := r.readerFor(opts).SearchWhere(ctx, &credentialStores, "scope_id in (?)", []interface{}{scopeIds}, db.WithLimit(limit))
 	if err != nil {
 		return nil, errors.Wrap(ctx, err, op)
 	}
@@ -666,6 +1029,7 @@ func (r *Repository) ListCredentialStores(ctx context.Context, scopeIds []string
 // the number of records deleted. All options are ignored.
 func (r *Repository) DeleteCredentialStore(ctx context.Context, publicId string, _ ...Option) (int, error) {
 	const op = "vault.(Repository).DeleteCredentialStore"
+	defer r.observeTiming(ctx, op, time.Now())
 	if publicId == "" {
 		return db.NoRowsAffected, errors.New(ctx, errors.InvalidParameter, op, "no public id")
 	}
@@ -723,9 +1087,84 @@ func (r *Repository) DeleteCredentialStore(ctx context.Context, publicId string,
 	}
 
 	if rows > 0 {
+		// The store no longer issues credentials so its rate limiter is
+		// no longer needed.
+		r.limiters.remove(cs.PublicId)
+
 		// Schedule token revocation and credential store cleanup jobs to run immediately
 		_ = r.scheduler.UpdateJobNextRunInAtLeast(ctx, tokenRevocationJobName, 0)
 		_ = r.scheduler.UpdateJobNextRunInAtLeast(ctx, credentialStoreCleanupJobName, 0)
+
+		// Writing the audit event is best effort and an error should not
+		// cause the delete to fail.
+		if err := writeStoreAuditEvent(ctx, deleteStoreAuditOperation, cs, nil); err != nil {
+			event.WriteError(ctx, op, err, event.WithInfoMsg("unable to write credential store delete audit event", "credential store id", cs.PublicId))
+		}
 	}
 	return rows, nil
 }
+
+// writeStoreAuditEvent writes an audit event recording the operation on
+// the credential store cs. For an update, changes are the names of the
+// changed fields and the event includes the updated value of each of
+// them. The values of the Token, CertificateKey, and AppRoleSecretId
+// fields are redacted.
+func writeStoreAuditEvent(ctx context.Context, operation string, cs *CredentialStore, changes []string) error {
+	const op = "vault.writeStoreAuditEvent"
+	fields := map[string]interface{}{
+		"store_id": cs.GetPublicId(),
+		"scope_id": cs.GetScopeId(),
+		"type":     Subtype.String(),
+	}
+	if len(changes) > 0 {
+		changed := make(map[string]interface{}, len(changes))
+		for _, f := range changes {
+			changed[f] = storeAuditValue(cs, f)
+		}
+		fields["changed_fields"] = changed
+	}
+	if err := writeAuditEvent(ctx, operation, fields); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	return nil
+}
+
+// storeAuditValue returns the value of field in cs to include in an audit
+// event.
+func storeAuditValue(cs *CredentialStore, field string) interface{} {
+	switch field {
+	case tokenField, certificateKeyField, appRoleSecretIdField:
+		return redactedStoreSecret
+	case nameField:
+		return cs.Name
+	case descriptionField:
+		return cs.Description
+	case vaultAddressField:
+		return cs.VaultAddress
+	case namespaceField:
+		return cs.Namespace
+	case caCertField:
+		return string(cs.CaCert)
+	case tlsServerNameField:
+		return cs.TlsServerName
+	case tlsSkipVerifyField:
+		return cs.TlsSkipVerify
+	case tlsMinVersionField:
+		return cs.TlsMinVersion
+	case connectTimeoutField:
+		return float64(cs.ConnectTimeoutSeconds)
+	case requestTimeoutField:
+		return float64(cs.RequestTimeoutSeconds)
+	case requestsPerSecField:
+		return float64(cs.RequestsPerSecond)
+	case certificateField:
+		if cs.ClientCertificate() != nil {
+			return string(cs.ClientCertificate().GetCertificate())
+		}
+	case appRoleRoleIdField:
+		if cs.AppRole() != nil {
+			return cs.AppRole().GetRoleId()
+		}
+	}
+	return nil
+}
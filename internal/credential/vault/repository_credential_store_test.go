@@ -3,13 +3,18 @@ package vault
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/boundary/internal/credential"
 	"github.com/hashicorp/boundary/internal/credential/vault/store"
 	"github.com/hashicorp/boundary/internal/db"
 	dbassert "github.com/hashicorp/boundary/internal/db/assert"
@@ -17,8 +22,10 @@ import (
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/observability/event"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/hashicorp/go-hclog"
 	wrapping "github.com/hashicorp/go-kms-wrapping"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,7 +43,7 @@ func TestRepository_CreateCredentialStoreResource(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		require.NoError(err)
 		require.NotNil(repo)
 		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
@@ -44,7 +51,7 @@ func TestRepository_CreateCredentialStoreResource(t *testing.T) {
 		v := NewTestVaultServer(t)
 		_, token := v.CreateToken(t)
 
-		in, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token), WithName("gary"), WithDescription("46"))
+		in, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(token), WithName("gary"), WithDescription("46"))
 		assert.NoError(err)
 		require.NotNil(in)
 		assert.NotEmpty(in.Name)
@@ -69,7 +76,7 @@ func TestRepository_CreateCredentialStoreResource(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		require.NoError(err)
 		require.NotNil(repo)
 		org, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
@@ -77,7 +84,7 @@ func TestRepository_CreateCredentialStoreResource(t *testing.T) {
 		v := NewTestVaultServer(t)
 
 		_, token1 := v.CreateToken(t)
-		in1, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token1), WithName("gary"), WithDescription("46"))
+		in1, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(token1), WithName("gary"), WithDescription("46"))
 		assert.NoError(err)
 		require.NotNil(in1)
 		assert.NotEmpty(in1.Name)
@@ -91,7 +98,7 @@ func TestRepository_CreateCredentialStoreResource(t *testing.T) {
 		assert.Equal(got1.CreateTime, got1.UpdateTime)
 
 		_, token2 := v.CreateToken(t)
-		in2, err := NewCredentialStore(org.GetPublicId(), v.Addr, []byte(token2), WithName("gary"), WithDescription("46"))
+		in2, err := NewCredentialStore(ctx, org.GetPublicId(), v.Addr, []byte(token2), WithName("gary"), WithDescription("46"))
 		assert.NoError(err)
 		require.NotNil(in2)
 		assert.NotEmpty(in2.Name)
@@ -169,7 +176,7 @@ func TestRepository_CreateCredentialStoreNonResource(t *testing.T) {
 			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			require.NoError(err)
 			require.NotNil(repo)
 			_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
@@ -188,7 +195,7 @@ func TestRepository_CreateCredentialStoreNonResource(t *testing.T) {
 				opts = append(opts, WithClientCert(clientCert))
 			}
 
-			credStoreIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token), opts...)
+			credStoreIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(token), opts...)
 			assert.NoError(err)
 			require.NotNil(credStoreIn)
 			got, err := repo.CreateCredentialStore(ctx, credStoreIn)
@@ -279,7 +286,7 @@ func TestRepository_LookupCredentialStore(t *testing.T) {
 			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			assert.NoError(err)
 			require.NotNil(repo)
 
@@ -309,6 +316,80 @@ func TestRepository_LookupCredentialStore(t *testing.T) {
 	}
 }
 
+func TestRepository_CredentialStoreTokenStatus(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	stores := TestCredentialStores(t, conn, wrapper, prj.PublicId, 2)
+	nearExpiry, renewed := stores[0], stores[1]
+
+	setExpiration := func(t *testing.T, cs *CredentialStore, expiration time.Duration) {
+		t.Helper()
+		rows, err := rw.Exec(context.Background(), testUpdateTokenStatusExpirationQuery,
+			[]interface{}{CurrentToken, int(expiration.Seconds()), cs.Token().GetTokenHmac()})
+		require.NoError(t, err)
+		require.Equal(t, 1, rows)
+	}
+	setExpiration(t, nearExpiry, 30*time.Second)
+	setExpiration(t, renewed, 24*time.Hour)
+
+	badId, err := newCredentialStoreId()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		id            string
+		wantExpiresIn time.Duration
+		wantErr       errors.Code
+	}{
+		{
+			name:          "near-expiry",
+			id:            nearExpiry.GetPublicId(),
+			wantExpiresIn: 30 * time.Second,
+		},
+		{
+			name:          "freshly-renewed",
+			id:            renewed.GetPublicId(),
+			wantExpiresIn: 24 * time.Hour,
+		},
+		{
+			name:    "empty-store-id",
+			wantErr: errors.InvalidParameter,
+		},
+		{
+			name:    "not-found",
+			id:      badId,
+			wantErr: errors.RecordNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.CredentialStoreTokenStatus(context.Background(), tt.id)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			require.NotNil(got)
+			assert.Equal(tt.id, got.StoreId)
+			assert.True(got.Renewable)
+			assert.True(got.LastRenewalTime.Before(got.ExpirationTime))
+			assert.InDelta(tt.wantExpiresIn, got.ExpiresIn, float64(5*time.Second))
+		})
+	}
+}
+
 func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -329,6 +410,21 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 		}
 	}
 
+	changeTimeouts := func(connect, request uint32) func(*CredentialStore) *CredentialStore {
+		return func(cs *CredentialStore) *CredentialStore {
+			cs.ConnectTimeoutSeconds = connect
+			cs.RequestTimeoutSeconds = request
+			return cs
+		}
+	}
+
+	changeTlsMinVersion := func(v string) func(*CredentialStore) *CredentialStore {
+		return func(cs *CredentialStore) *CredentialStore {
+			cs.TlsMinVersion = v
+			return cs
+		}
+	}
+
 	changeTlsSkipVerify := func(t bool) func(*CredentialStore) *CredentialStore {
 		return func(cs *CredentialStore) *CredentialStore {
 			cs.TlsSkipVerify = t
@@ -644,6 +740,75 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 			},
 			wantCount: 1,
 		},
+		{
+			name: "change-timeouts",
+			orig: &CredentialStore{
+				CredentialStore: &store.CredentialStore{
+					ConnectTimeoutSeconds: 5,
+				},
+			},
+			chgFn: changeTimeouts(10, 60),
+			masks: []string{connectTimeoutField, requestTimeoutField},
+			want: &CredentialStore{
+				CredentialStore: &store.CredentialStore{
+					ConnectTimeoutSeconds: 10,
+					RequestTimeoutSeconds: 60,
+				},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "delete-timeouts",
+			orig: &CredentialStore{
+				CredentialStore: &store.CredentialStore{
+					ConnectTimeoutSeconds: 5,
+					RequestTimeoutSeconds: 30,
+				},
+			},
+			chgFn: changeTimeouts(0, 0),
+			masks: []string{connectTimeoutField, requestTimeoutField},
+			want: &CredentialStore{
+				CredentialStore: &store.CredentialStore{},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "change-tls-min-version",
+			orig: &CredentialStore{
+				CredentialStore: &store.CredentialStore{},
+			},
+			chgFn: changeTlsMinVersion(TlsVersion13),
+			masks: []string{tlsMinVersionField},
+			want: &CredentialStore{
+				CredentialStore: &store.CredentialStore{
+					TlsMinVersion: TlsVersion13,
+				},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "delete-tls-min-version",
+			orig: &CredentialStore{
+				CredentialStore: &store.CredentialStore{
+					TlsMinVersion: TlsVersion13,
+				},
+			},
+			chgFn: changeTlsMinVersion(""),
+			masks: []string{tlsMinVersionField},
+			want: &CredentialStore{
+				CredentialStore: &store.CredentialStore{},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "unsupported-tls-min-version",
+			orig: &CredentialStore{
+				CredentialStore: &store.CredentialStore{},
+			},
+			chgFn:   changeTlsMinVersion("tls10"),
+			masks:   []string{tlsMinVersionField},
+			wantErr: errors.InvalidParameter,
+		},
 		{
 			name: "tls-skip-verify-false2true",
 			orig: &CredentialStore{
@@ -717,7 +882,7 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			assert.NoError(err)
 			require.NotNil(repo)
 
@@ -784,6 +949,23 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 
 			assert.Equal(tt.want.TlsSkipVerify, got.TlsSkipVerify)
 
+			if tt.want.TlsMinVersion == "" {
+				dbassert.IsNull(got, "TlsMinVersion")
+			} else {
+				assert.Equal(tt.want.TlsMinVersion, got.TlsMinVersion)
+			}
+
+			if tt.want.ConnectTimeoutSeconds == 0 {
+				dbassert.IsNull(got, "ConnectTimeoutSeconds")
+			} else {
+				assert.Equal(tt.want.ConnectTimeoutSeconds, got.ConnectTimeoutSeconds)
+			}
+			if tt.want.RequestTimeoutSeconds == 0 {
+				dbassert.IsNull(got, "RequestTimeoutSeconds")
+			} else {
+				assert.Equal(tt.want.RequestTimeoutSeconds, got.RequestTimeoutSeconds)
+			}
+
 			assert.Equal(tt.want.VaultAddress, got.VaultAddress)
 
 			if tt.wantCount > 0 {
@@ -797,7 +979,7 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		assert.NoError(err)
 		require.NotNil(repo)
 
@@ -848,7 +1030,7 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		assert.NoError(err)
 		require.NotNil(repo)
 
@@ -897,7 +1079,7 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		assert.NoError(err)
 		require.NotNil(repo)
 
@@ -927,7 +1109,7 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		assert.NoError(err)
 		require.NotNil(repo)
 
@@ -976,7 +1158,7 @@ func TestRepository_UpdateCredentialStore_Attributes(t *testing.T) {
 		ctx := context.Background()
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(ctx, rw, rw, kms, sche)
 		assert.NoError(err)
 		require.NotNil(repo)
 
@@ -1013,10 +1195,18 @@ func TestRepository_UpdateCredentialStore_VaultToken(t *testing.T) {
 		wantErr            errors.Code
 	}{
 		{
+			// the new token cannot revoke the old token which does not
+			// cause the update to fail
 			name:               "valid",
 			wantOldTokenStatus: MaintainingToken,
 			wantCount:          1,
 		},
+		{
+			name:               "valid-revoke-old-token",
+			newTokenOpts:       []TestOption{WithPolicies([]string{"default", "boundary-controller", "revoke-accessor"})},
+			wantOldTokenStatus: RevokedToken,
+			wantCount:          1,
+		},
 		{
 			name:         "token-missing-capabilities",
 			newTokenOpts: []TestOption{WithPolicies([]string{"default"})},
@@ -1045,16 +1235,17 @@ func TestRepository_UpdateCredentialStore_VaultToken(t *testing.T) {
 			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			require.NoError(err)
 			require.NotNil(repo)
 			_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
 
 			v := NewTestVaultServer(t)
+			v.addPolicy(t, "revoke-accessor", pathCapabilities{"auth/token/revoke-accessor": updateCapability})
 			_, origToken := v.CreateToken(t)
 
 			// create
-			origIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(origToken))
+			origIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(origToken))
 			assert.NoError(err)
 			require.NotNil(origIn)
 
@@ -1065,7 +1256,7 @@ func TestRepository_UpdateCredentialStore_VaultToken(t *testing.T) {
 			// update
 			_, updateToken := v.CreateToken(t, tt.newTokenOpts...)
 
-			updateIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(updateToken))
+			updateIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(updateToken))
 			assert.NoError(err)
 			require.NotNil(updateIn)
 			updateIn.PublicId = orig.GetPublicId()
@@ -1084,6 +1275,11 @@ func TestRepository_UpdateCredentialStore_VaultToken(t *testing.T) {
 			require.NoError(rw.SearchWhere(ctx, &tokens, "store_id = ?", []interface{}{orig.GetPublicId()}))
 			assert.Len(tokens, 2)
 			assert.Equal(string(tt.wantOldTokenStatus), tokens[0].Status)
+			if tt.wantOldTokenStatus == RevokedToken {
+				v.VerifyTokenInvalid(t, origToken)
+			} else {
+				v.LookupToken(t, origToken)
+			}
 
 			lookup, err := repo.LookupCredentialStore(ctx, orig.GetPublicId())
 			assert.NoError(err)
@@ -1177,7 +1373,7 @@ func TestRepository_UpdateCredentialStore_ClientCert(t *testing.T) {
 			ctx := context.Background()
 			kms := kms.TestKms(t, conn, wrapper)
 			sche := scheduler.TestScheduler(t, conn, wrapper)
-			repo, err := NewRepository(rw, rw, kms, sche)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
 			require.NoError(err)
 			require.NotNil(repo)
 			_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
@@ -1200,7 +1396,7 @@ func TestRepository_UpdateCredentialStore_ClientCert(t *testing.T) {
 			opts = append(opts, WithClientCert(origClientCert))
 
 			// create
-			origIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(origToken), opts...)
+			origIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(origToken), opts...)
 			assert.NoError(err)
 			require.NotNil(origIn)
 
@@ -1211,7 +1407,7 @@ func TestRepository_UpdateCredentialStore_ClientCert(t *testing.T) {
 			// update
 			updateClientCert := tt.updateFn(t, v)
 
-			updateIn, err := NewCredentialStore(prj.GetPublicId(), v.Addr, []byte("ignore"), WithClientCert(updateClientCert))
+			updateIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte("ignore"), WithClientCert(updateClientCert))
 			assert.NoError(err)
 			require.NotNil(updateIn)
 			updateIn.PublicId = orig.GetPublicId()
@@ -1232,6 +1428,366 @@ func TestRepository_UpdateCredentialStore_ClientCert(t *testing.T) {
 	}
 }
 
+func TestRepository_CreateCredentialStore_AppRole(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	v := NewTestVaultServer(t)
+	roleId, secretId := v.EnableAppRole(t)
+	_, token := v.CreateToken(t)
+
+	tests := []struct {
+		name     string
+		token    string
+		roleId   string
+		secretId string
+		wantErr  errors.Code
+	}{
+		{
+			name:     "valid",
+			roleId:   roleId,
+			secretId: secretId,
+		},
+		{
+			name:     "approle-and-token",
+			token:    token,
+			roleId:   roleId,
+			secretId: secretId,
+			wantErr:  errors.InvalidParameter,
+		},
+		{
+			name:     "invalid-secret-id",
+			roleId:   roleId,
+			secretId: "not-a-secret-id",
+			wantErr:  errors.Unknown,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := context.Background()
+
+			appRole, err := NewAppRole(ctx, tt.roleId, SecretIdSecret(tt.secretId))
+			require.NoError(err)
+			in, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(tt.token), WithAppRole(appRole))
+			require.NoError(err)
+
+			got, err := repo.CreateCredentialStore(ctx, in)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			require.NotNil(got)
+			assert.Equal(AppRoleAuthMethod, got.AuthMethod())
+			require.NotNil(got.AppRole())
+			assert.Equal(roleId, got.AppRole().GetRoleId())
+			assert.Empty(got.AppRole().GetSecretId())
+			assert.NotEmpty(got.AppRole().GetSecretIdHmac())
+			require.NotNil(got.Token())
+
+			ps, err := repo.lookupPrivateStore(ctx, got.GetPublicId())
+			require.NoError(err)
+			assert.Equal(roleId, ps.AppRoleRoleId)
+			assert.Equal(SecretIdSecret(secretId), ps.AppRoleSecretId)
+			assert.Equal(string(AppRoleAuthMethod), ps.AuthMethod)
+			assert.NotEmpty(ps.Token)
+			assert.NotNil(v.LookupToken(t, string(ps.Token)))
+		})
+	}
+}
+
+func TestRepository_CreateCredentialStore_TokenFile(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	tokenDir := t.TempDir()
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche, WithTokenFileDirectory(tokenDir))
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	noDirRepo, err := NewRepository(context.Background(), rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, noDirRepo)
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	v := NewTestVaultServer(t)
+	_, token := v.CreateToken(t)
+	tokenFile := filepath.Join(tokenDir, "token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte(token+"\n"), 0o600))
+
+	outsideFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(outsideFile, []byte(token+"\n"), 0o600))
+	linkFile := filepath.Join(tokenDir, "link")
+	require.NoError(t, os.Symlink(outsideFile, linkFile))
+
+	tests := []struct {
+		name      string
+		repo      *Repository
+		token     string
+		tokenFile string
+		wantErr   errors.Code
+	}{
+		{
+			name:      "valid",
+			repo:      repo,
+			tokenFile: tokenFile,
+		},
+		{
+			name:      "token-and-token-file",
+			repo:      repo,
+			token:     token,
+			tokenFile: tokenFile,
+			wantErr:   errors.InvalidParameter,
+		},
+		{
+			name:      "missing-token-file",
+			repo:      repo,
+			tokenFile: filepath.Join(tokenDir, "missing"),
+			wantErr:   errors.InvalidParameter,
+		},
+		{
+			name:      "token-file-outside-directory",
+			repo:      repo,
+			tokenFile: outsideFile,
+			wantErr:   errors.InvalidParameter,
+		},
+		{
+			name:      "token-file-relative-path",
+			repo:      repo,
+			tokenFile: "token",
+			wantErr:   errors.InvalidParameter,
+		},
+		{
+			name:      "token-file-link-outside-directory",
+			repo:      repo,
+			tokenFile: linkFile,
+			wantErr:   errors.InvalidParameter,
+		},
+		{
+			name:      "no-token-file-directory",
+			repo:      noDirRepo,
+			tokenFile: tokenFile,
+			wantErr:   errors.InvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := context.Background()
+
+			in, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(tt.token), WithTokenFile(tt.tokenFile))
+			require.NoError(err)
+
+			got, err := tt.repo.CreateCredentialStore(ctx, in)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			require.NotNil(got)
+			assert.Equal(TokenFileAuthMethod, got.AuthMethod())
+			assert.Equal(tokenFile, got.GetTokenFilePath())
+
+			ps, err := tt.repo.lookupPrivateStore(ctx, got.GetPublicId())
+			require.NoError(err)
+			assert.Equal(tokenFile, ps.TokenFilePath)
+			assert.Equal(TokenSecret(token), ps.Token)
+		})
+	}
+}
+
+func TestRepository_UpdateCredentialStore_AppRole(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+
+	appRoleMask := []string{appRoleRoleIdField, appRoleSecretIdField}
+
+	tests := []struct {
+		name        string
+		origAppRole bool
+		newAppRole  bool
+		newToken    bool
+		fieldMask   []string
+		wantAppRole bool
+		wantErr     errors.Code
+	}{
+		{
+			name:        "token-to-approle",
+			newAppRole:  true,
+			fieldMask:   appRoleMask,
+			wantAppRole: true,
+		},
+		{
+			name:        "approle-to-approle",
+			origAppRole: true,
+			newAppRole:  true,
+			fieldMask:   appRoleMask,
+			wantAppRole: true,
+		},
+		{
+			name:        "approle-to-token",
+			origAppRole: true,
+			newToken:    true,
+			fieldMask:   append([]string{tokenField}, appRoleMask...),
+		},
+		{
+			name:        "approle-unset-without-token",
+			origAppRole: true,
+			fieldMask:   appRoleMask,
+			wantErr:     errors.InvalidParameter,
+		},
+		{
+			name:        "approle-unset-role-id-only",
+			origAppRole: true,
+			newToken:    true,
+			fieldMask:   []string{tokenField, appRoleRoleIdField},
+			wantErr:     errors.InvalidParameter,
+		},
+		{
+			name:        "approle-update-token",
+			origAppRole: true,
+			newToken:    true,
+			fieldMask:   []string{tokenField},
+			wantErr:     errors.InvalidParameter,
+		},
+		{
+			name:       "approle-and-token",
+			newAppRole: true,
+			newToken:   true,
+			fieldMask:  append([]string{tokenField}, appRoleMask...),
+			wantErr:    errors.InvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := context.Background()
+			kms := kms.TestKms(t, conn, wrapper)
+			sche := scheduler.TestScheduler(t, conn, wrapper)
+			repo, err := NewRepository(ctx, rw, rw, kms, sche)
+			require.NoError(err)
+			require.NotNil(repo)
+			_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+			v := NewTestVaultServer(t)
+
+			// create
+			var origToken []byte
+			var origOpts []Option
+			if tt.origAppRole {
+				roleId, secretId := v.EnableAppRole(t)
+				appRole, err := NewAppRole(ctx, roleId, SecretIdSecret(secretId))
+				require.NoError(err)
+				origOpts = append(origOpts, WithAppRole(appRole))
+			} else {
+				_, token := v.CreateToken(t)
+				origToken = []byte(token)
+			}
+			origIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, origToken, origOpts...)
+			require.NoError(err)
+			orig, err := repo.CreateCredentialStore(ctx, origIn)
+			require.NoError(err)
+			require.NotNil(orig)
+
+			// update
+			var newToken []byte
+			var newOpts []Option
+			var newRoleId, newSecretId string
+			if tt.newAppRole {
+				newRoleId, newSecretId = v.EnableAppRole(t, WithTestRoleName("boundary-updated"))
+				appRole, err := NewAppRole(ctx, newRoleId, SecretIdSecret(newSecretId))
+				require.NoError(err)
+				newOpts = append(newOpts, WithAppRole(appRole))
+			}
+			if tt.newToken {
+				_, token := v.CreateToken(t)
+				newToken = []byte(token)
+			}
+			updateIn, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, newToken, newOpts...)
+			require.NoError(err)
+			updateIn.PublicId = orig.GetPublicId()
+
+			got, gotCount, err := repo.UpdateCredentialStore(ctx, updateIn, orig.GetVersion(), tt.fieldMask)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Zero(gotCount)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(1, gotCount)
+			require.NotNil(got)
+
+			ps, err := repo.lookupPrivateStore(ctx, orig.GetPublicId())
+			require.NoError(err)
+			assert.NotEqual(orig.Token().GetTokenHmac(), got.Token().GetTokenHmac())
+			if tt.wantAppRole {
+				assert.Equal(AppRoleAuthMethod, got.AuthMethod())
+				assert.Equal(string(AppRoleAuthMethod), ps.AuthMethod)
+				assert.Equal(newRoleId, ps.AppRoleRoleId)
+				assert.Equal(SecretIdSecret(newSecretId), ps.AppRoleSecretId)
+				return
+			}
+			assert.Equal(TokenAuthMethod, got.AuthMethod())
+			assert.Equal(string(TokenAuthMethod), ps.AuthMethod)
+			assert.Nil(got.AppRole())
+			assert.Empty(ps.AppRoleRoleId)
+			assert.Equal(TokenSecret(newToken), ps.Token)
+		})
+	}
+}
+
+func TestRepository_CredentialStore_Warnings(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	v := NewTestVaultServer(t, WithTestVaultTLS(TestServerTLS))
+	_, token := v.CreateToken(t)
+
+	in, err := NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(token), WithTlsSkipVerify(true))
+	require.NoError(err)
+	got, err := repo.CreateCredentialStore(ctx, in)
+	require.NoError(err)
+	require.NotNil(got)
+	assert.Contains(got.Warnings(), "TLS verification disabled")
+
+	got.CaCert = v.CaCert
+	got.TlsSkipVerify = false
+	updated, gotCount, err := repo.UpdateCredentialStore(ctx, got, got.GetVersion(), []string{caCertField, tlsSkipVerifyField})
+	require.NoError(err)
+	assert.Equal(1, gotCount)
+	require.NotNil(updated)
+	assert.Empty(updated.Warnings())
+}
+
 func TestRepository_ListCredentialStores_Multiple_Scopes(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
@@ -1241,7 +1797,7 @@ func TestRepository_ListCredentialStores_Multiple_Scopes(t *testing.T) {
 
 	assert, require := assert.New(t), require.New(t)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kms, sche)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
 	assert.NoError(err)
 	require.NotNil(repo)
 
@@ -1260,6 +1816,102 @@ func TestRepository_ListCredentialStores_Multiple_Scopes(t *testing.T) {
 	assert.Equal(total, len(got))
 }
 
+func TestRepository_ListCredentialStores_StoreType(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	const numStores = 5
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), numStores)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantCnt int
+		wantErr errors.Code
+	}{
+		{
+			name:    "no-store-type",
+			wantCnt: numStores,
+		},
+		{
+			name:    "vault-store-type",
+			opts:    []Option{WithStoreType("vault")},
+			wantCnt: numStores,
+		},
+		{
+			name:    "vault-store-type-with-limit",
+			opts:    []Option{WithStoreType("vault"), WithLimit(2)},
+			wantCnt: 2,
+		},
+		{
+			name:    "unknown-store-type",
+			opts:    []Option{WithStoreType("plugin")},
+			wantErr: errors.InvalidParameter,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := repo.ListCredentialStores(context.Background(), []string{prj.GetPublicId()}, tt.opts...)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				assert.Nil(got)
+				return
+			}
+			require.NoError(err)
+			assert.Len(got, tt.wantCnt)
+			for _, cs := range got {
+				assert.Equal(Subtype, credential.SubtypeFromId(cs.GetPublicId()))
+			}
+		})
+	}
+}
+
+func TestRepository_ListCredentialStores_TimingObservation(t *testing.T) {
+	event.TestEnableEventing(t, true)
+	assert, require := assert.New(t), require.New(t)
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(context.Background(), rw, rw, kms, sche, WithTimingObservations(true))
+	require.NoError(err)
+	require.NotNil(repo)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 2)
+
+	c := event.TestEventerConfig(t, "TestRepository_ListCredentialStores_TimingObservation", event.TestWithObservationSink(t))
+	testLock := &sync.Mutex{}
+	testLogger := hclog.New(&hclog.LoggerOptions{
+		Mutex: testLock,
+		Name:  "test",
+	})
+	e, err := event.NewEventer(testLogger, testLock, "TestRepository_ListCredentialStores_TimingObservation", c.EventerConfig)
+	require.NoError(err)
+	ctx, err := event.NewEventerContext(context.Background(), e)
+	require.NoError(err)
+
+	got, err := repo.ListCredentialStores(ctx, []string{prj.GetPublicId()})
+	require.NoError(err)
+	assert.Len(got, 2)
+
+	b, err := ioutil.ReadFile(c.ObservationEvents.Name())
+	require.NoError(err)
+	assert.Contains(string(b), "vault.(Repository).ListCredentialStores")
+	assert.Contains(string(b), `"elapsed"`)
+}
+
 func TestRepository_DeleteCredentialStore(t *testing.T) {
 	type tokenCount struct {
 		current, maintaining int
@@ -1276,7 +1928,7 @@ func TestRepository_DeleteCredentialStore(t *testing.T) {
 		kms := kms.TestKms(t, conn, wrapper)
 		sche := scheduler.TestScheduler(t, conn, wrapper)
 		rw := db.New(conn)
-		repo, err := NewRepository(rw, rw, kms, sche)
+		repo, err := NewRepository(context.Background(), rw, rw, kms, sche)
 		require.NoError(t, err)
 		require.NotNil(t, repo)
 
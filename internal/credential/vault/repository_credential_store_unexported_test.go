@@ -0,0 +1,73 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/credential/vault/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeStoreAuditEvent(t *testing.T) {
+	require := require.New(t)
+	ctx, got := testAuditContext(t)
+
+	const (
+		token     = "do-not-log-the-token"
+		clientKey = "do-not-log-the-client-certificate-key"
+		secretId  = "do-not-log-the-approle-secret-id"
+	)
+	cs := &CredentialStore{
+		CredentialStore: &store.CredentialStore{
+			PublicId:     "csvlt_1234567890",
+			ScopeId:      "p_1234567890",
+			Name:         "updated-name",
+			Description:  "unchanged-description",
+			VaultAddress: "https://vault.example.com",
+		},
+		inputToken: TokenSecret(token),
+		clientCert: &ClientCertificate{
+			ClientCertificate: &store.ClientCertificate{
+				Certificate:    []byte("client-certificate"),
+				CertificateKey: []byte(clientKey),
+			},
+		},
+		appRole: &AppRole{
+			AppRole: &store.AppRole{
+				RoleId:   "role-id",
+				SecretId: []byte(secretId),
+			},
+		},
+	}
+
+	changes := []string{nameField, tokenField, certificateField, certificateKeyField, appRoleSecretIdField}
+	require.NoError(writeStoreAuditEvent(ctx, updateStoreAuditOperation, cs, changes))
+
+	for _, f := range testAuditSinkFormats {
+		t.Run(string(f), func(t *testing.T) {
+			assert := assert.New(t)
+			got := got(f)
+			for _, want := range []string{
+				updateStoreAuditOperation,
+				cs.PublicId,
+				cs.ScopeId,
+				Subtype.String(),
+				"changed_fields",
+				nameField,
+				"updated-name",
+				tokenField,
+				certificateField,
+				"client-certificate",
+				certificateKeyField,
+				appRoleSecretIdField,
+				redactedStoreSecret,
+			} {
+				assert.Contains(got, want)
+			}
+			assert.NotContains(got, token)
+			assert.NotContains(got, clientKey)
+			assert.NotContains(got, secretId)
+			assert.NotContains(got, "unchanged-description", "unchanged fields should not be included")
+		})
+	}
+}
@@ -2,19 +2,40 @@ package vault
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/hashicorp/boundary/internal/credential"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/observability/event"
 	vault "github.com/hashicorp/vault/api"
 )
 
+// issueCredentialAuditOperation is the operation of the audit event written
+// when a credential is issued.
+const issueCredentialAuditOperation = "issue-credential"
+
 var _ credential.Issuer = (*Repository)(nil)
 
 // Issue issues and returns dynamic credentials from Vault for all of the
-// requests and assigns them to sessionId.
+// requests and assigns them to sessionId. An error with the
+// errors.VaultTokenExpired code is returned if a request to Vault fails
+// because the credential store's Vault token is expired. An error with the
+// errors.VaultRateLimited code is returned if a request exceeds the
+// repository's issue rate limit for a credential store. The request can be
+// retried later.
+//
+// If a library has credential mappings, a credential is returned for each
+// mapping instead of a single credential containing all of the data in the
+// Vault response. The mapped credentials share the id of the credential
+// issued by the library.
+//
+// If a library has a wrap TTL, Vault is asked to return a response-wrapping
+// token in place of the response, and the wrapped response is unwrapped
+// before the credential is issued.
 func (r *Repository) Issue(ctx context.Context, sessionId string, requests []credential.Request) ([]credential.Dynamic, error) {
 	const op = "vault.(Repository).Issue"
 	if sessionId == "" {
@@ -35,6 +56,7 @@ func (r *Repository) Issue(ctx context.Context, sessionId string, requests []cre
 
 	var creds []credential.Dynamic
 	var minLease time.Duration
+	var pathData *vaultPathData
 	for _, lib := range libs {
 		// Get the credential ID early. No need to get a secret from Vault
 		// if there is no way to save it in the database.
@@ -43,26 +65,68 @@ func (r *Repository) Issue(ctx context.Context, sessionId string, requests []cre
 			return nil, errors.Wrap(ctx, err, op)
 		}
 
-		client, err := lib.client()
+		client, err := lib.client(ctx, WithTokenFileDirectory(r.tokenFileDir), WithRateLimiters(r.limiters))
 		if err != nil {
 			return nil, errors.Wrap(ctx, err, op)
 		}
 
+		vaultPath := lib.VaultPath
+		if lib.TemplatedVaultPath {
+			if pathData == nil {
+				if pathData, err = r.lookupVaultPathData(ctx, sessionId); err != nil {
+					return nil, errors.Wrap(ctx, err, op)
+				}
+			}
+			if vaultPath, err = renderVaultPath(ctx, lib.VaultPath, *pathData); err != nil {
+				return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("library: %s", lib.PublicId)))
+			}
+		}
+
 		var secret *vault.Secret
 		switch Method(lib.HttpMethod) {
 		case MethodGet:
-			secret, err = client.get(lib.VaultPath)
-		case MethodPost:
-			secret, err = client.post(lib.VaultPath, lib.HttpRequestBody)
+			secret, err = client.get(ctx, vaultPath)
+		case MethodPost, MethodPut:
+			secret, err = client.post(ctx, vaultPath, lib.HttpRequestBody, lib.ContentType)
+		case MethodPatch:
+			secret, err = client.patch(ctx, vaultPath, lib.HttpRequestBody)
 		default:
 			return nil, errors.New(ctx, errors.Internal, op, fmt.Sprintf("unknown http method: library: %s", lib.PublicId))
 		}
 
 		if err != nil {
-			// TODO(mgaffney) 05/2021: detect if the error is because of an
-			// expired or invalid token
+			// err has the errors.VaultTokenExpired code if the request
+			// failed because the store's token is no longer valid
 			return nil, errors.Wrap(ctx, err, op)
 		}
+		if secret, err = client.unwrap(ctx, secret); err != nil {
+			return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("unable to unwrap response: library: %s", lib.PublicId)))
+		}
+
+		var secretData credential.SecretData = secret.Data
+		if lib.CredentialJsonPointer != "" {
+			secretData, err = resolveJsonPointer(ctx, lib.CredentialJsonPointer, secret.Data)
+			if err != nil {
+				return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.VaultCredentialRequest),
+					errors.WithMsg(fmt.Sprintf("unable to select credential: library: %s", lib.PublicId)))
+			}
+		}
+
+		ac := &actualCredential{
+			id:         credId,
+			sessionId:  sessionId,
+			lib:        lib,
+			secretData: secretData,
+			purpose:    lib.Purpose,
+		}
+		issued := []credential.Dynamic{ac}
+		if len(lib.CredentialMappings) > 0 {
+			issued, err = mapCredentials(ctx, ac, lib.CredentialMappings, secret.Data)
+			if err != nil {
+				return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.VaultCredentialRequest),
+					errors.WithMsg(fmt.Sprintf("unable to map credentials: library: %s", lib.PublicId)))
+			}
+		}
 
 		leaseDuration := time.Duration(secret.LeaseDuration) * time.Second
 		if minLease > leaseDuration {
@@ -102,13 +166,13 @@ func (r *Repository) Issue(ctx context.Context, sessionId string, requests []cre
 			return nil, errors.Wrap(ctx, err, op)
 		}
 
-		creds = append(creds, &actualCredential{
-			id:         cred.PublicId,
-			sessionId:  cred.SessionId,
-			lib:        lib,
-			secretData: secret.Data,
-			purpose:    lib.Purpose,
-		})
+		// Writing the audit event is best effort and an error should not
+		// cause Issue to fail.
+		if err := writeIssueAuditEvent(ctx, sessionId, lib); err != nil {
+			event.WriteError(ctx, op, err, event.WithInfoMsg("unable to write credential issue audit event", "library_id", lib.GetPublicId()))
+		}
+
+		creds = append(creds, issued...)
 	}
 
 	// Best effort update next run time of credential renewal job, but an error should not
@@ -119,6 +183,127 @@ func (r *Repository) Issue(ctx context.Context, sessionId string, requests []cre
 	return creds, nil
 }
 
+// PreviewCredentialFields issues the configured request of the credential
+// library libraryId to Vault and returns the sorted names of the top-level
+// fields of the response data. The values of the fields are never
+// returned. If Vault returns a lease with the response, the lease is
+// revoked before returning.
+//
+// Since a live request is made to Vault, WithLiveRequest(true) must be
+// provided or an error with the errors.InvalidParameter code is returned.
+// Libraries with a templated vault path cannot be previewed because the
+// path can only be rendered for a session.
+func (r *Repository) PreviewCredentialFields(ctx context.Context, libraryId string, opt ...Option) ([]string, error) {
+	const op = "vault.(Repository).PreviewCredentialFields"
+	if libraryId == "" {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "no library id")
+	}
+	opts := getOpts(opt...)
+	if !opts.withLiveRequest {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "previewing credential fields requires a live request to vault")
+	}
+
+	libs, err := r.getPrivateLibraries(ctx, []credential.Request{{SourceId: libraryId, Purpose: credential.ApplicationPurpose}})
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if len(libs) == 0 {
+		return nil, errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("credential library %s not found", libraryId))
+	}
+	lib := libs[0]
+	if lib.TemplatedVaultPath {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("templated vault path cannot be previewed: library: %s", libraryId))
+	}
+
+	client, err := lib.client(ctx, WithTokenFileDirectory(r.tokenFileDir), WithRateLimiters(r.limiters))
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+
+	var secret *vault.Secret
+	switch Method(lib.HttpMethod) {
+	case MethodGet:
+		secret, err = client.get(ctx, lib.VaultPath)
+	case MethodPost, MethodPut:
+		secret, err = client.post(ctx, lib.VaultPath, lib.HttpRequestBody, lib.ContentType)
+	case MethodPatch:
+		secret, err = client.patch(ctx, lib.VaultPath, lib.HttpRequestBody)
+	default:
+		return nil, errors.New(ctx, errors.Internal, op, fmt.Sprintf("unknown http method: library: %s", libraryId))
+	}
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if secret, err = client.unwrap(ctx, secret); err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("unable to unwrap response: library: %s", libraryId)))
+	}
+	if secret == nil {
+		return nil, errors.New(ctx, errors.VaultCredentialRequest, op, fmt.Sprintf("empty response: library: %s", libraryId))
+	}
+
+	// The credential is not issued to a session, so a lease returned
+	// with it is revoked immediately rather than left to expire.
+	if secret.LeaseID != "" {
+		if err := client.revokeLease(ctx, secret.LeaseID); err != nil {
+			event.WriteError(ctx, op, err, event.WithInfoMsg("unable to revoke lease of previewed credential", "library_id", libraryId))
+		}
+	}
+
+	fields := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// lookupVaultPathData returns the values of sessionId used to render a
+// templated vault path.
+func (r *Repository) lookupVaultPathData(ctx context.Context, sessionId string) (*vaultPathData, error) {
+	const op = "vault.(Repository).lookupVaultPathData"
+	rows, err := r.reader.Query(ctx, sessionVaultPathDataQuery, []interface{}{sql.Named("session_id", sessionId)})
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("failed for %s", sessionId)))
+	}
+	defer rows.Close()
+	var data *vaultPathData
+	for rows.Next() {
+		var username, target sql.NullString
+		if err := rows.Scan(&username, &target); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+		data = &vaultPathData{
+			Username: username.String,
+			Target:   target.String,
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	if data == nil {
+		return nil, errors.New(ctx, errors.RecordNotFound, op, fmt.Sprintf("session %s", sessionId))
+	}
+	return data, nil
+}
+
+// writeIssueAuditEvent writes an audit event recording the library, store
+// and Vault request used to issue a credential for sessionId. The library's
+// HttpRequestBody is never included in the event, only whether one was sent.
+func writeIssueAuditEvent(ctx context.Context, sessionId string, lib *privateLibrary) error {
+	const op = "vault.writeIssueAuditEvent"
+	if err := writeAuditEvent(ctx, issueCredentialAuditOperation, map[string]interface{}{
+		"session_id":             sessionId,
+		"library_id":             lib.GetPublicId(),
+		"store_id":               lib.StoreId,
+		"vault_path":             lib.VaultPath,
+		"http_method":            lib.HttpMethod,
+		"http_request_body_sent": len(lib.HttpRequestBody) > 0,
+	}); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	return nil
+}
+
 var _ credential.Revoker = (*Repository)(nil)
 
 // Revoke revokes all dynamic credentials issued from Vault for sessionId.
@@ -37,7 +37,7 @@ func TestRepository_IssueCredentials(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
 
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := vault.NewRepository(rw, rw, kms, sche)
+	repo, err := vault.NewRepository(ctx, rw, rw, kms, sche)
 	require.NoError(err)
 	require.NotNil(repo)
 
@@ -49,7 +49,7 @@ func TestRepository_IssueCredentials(t *testing.T) {
 	require.NoError(err)
 	opts = append(opts, vault.WithClientCert(clientCert))
 
-	credStoreIn, err := vault.NewCredentialStore(prj.GetPublicId(), v.Addr, []byte(token), opts...)
+	credStoreIn, err := vault.NewCredentialStore(ctx, prj.GetPublicId(), v.Addr, []byte(token), opts...)
 	assert.NoError(err)
 	require.NotNil(credStoreIn)
 	origStore, err := repo.CreateCredentialStore(ctx, credStoreIn)
@@ -66,7 +66,7 @@ func TestRepository_IssueCredentials(t *testing.T) {
 	libs := make(map[libT]string)
 	{
 		libPath := path.Join("database", "creds", "opened")
-		libIn, err := vault.NewCredentialLibrary(origStore.GetPublicId(), libPath)
+		libIn, err := vault.NewCredentialLibrary(ctx, origStore.GetPublicId(), libPath)
 		assert.NoError(err)
 		require.NotNil(libIn)
 		lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), libIn)
@@ -76,7 +76,7 @@ func TestRepository_IssueCredentials(t *testing.T) {
 	}
 	{
 		libPath := path.Join("pki", "issue", "boundary")
-		libIn, err := vault.NewCredentialLibrary(origStore.GetPublicId(), libPath, vault.WithMethod(vault.MethodPost), vault.WithRequestBody([]byte(`{"common_name":"boundary.com"}`)))
+		libIn, err := vault.NewCredentialLibrary(ctx, origStore.GetPublicId(), libPath, vault.WithMethod(vault.MethodPost), vault.WithRequestBody([]byte(`{"common_name":"boundary.com"}`)))
 		assert.NoError(err)
 		require.NotNil(libIn)
 		lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), libIn)
@@ -87,7 +87,7 @@ func TestRepository_IssueCredentials(t *testing.T) {
 	{
 
 		libPath := path.Join("pki", "issue", "boundary")
-		libIn, err := vault.NewCredentialLibrary(origStore.GetPublicId(), libPath, vault.WithMethod(vault.MethodPost))
+		libIn, err := vault.NewCredentialLibrary(ctx, origStore.GetPublicId(), libPath, vault.WithMethod(vault.MethodPost))
 		assert.NoError(err)
 		require.NotNil(libIn)
 		lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), libIn)
@@ -254,7 +254,7 @@ func TestRepository_Revoke(t *testing.T) {
 	}
 
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := vault.NewRepository(rw, rw, kms, sche)
+	repo, err := vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	require.NoError(err)
 	require.NotNil(repo)
 
@@ -372,7 +372,7 @@ func Test_TerminateSession(t *testing.T) {
 	}
 
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := vault.NewRepository(rw, rw, kms, sche)
+	repo, err := vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	require.NoError(err)
 	require.NotNil(repo)
 
@@ -0,0 +1,177 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeIssueAuditEvent(t *testing.T) {
+	ctx, got := testAuditContext(t)
+
+	const secretBody = "do-not-log-this-body"
+	lib := &privateLibrary{
+		PublicId:        "clvlt_1234567890",
+		StoreId:         "csvlt_1234567890",
+		VaultPath:       "/pki/issue/boundary",
+		HttpMethod:      string(MethodPost),
+		HttpRequestBody: []byte(`{"common_name":"` + secretBody + `"}`),
+	}
+	require.NoError(t, writeIssueAuditEvent(ctx, "s_1234567890", lib))
+
+	for _, f := range testAuditSinkFormats {
+		t.Run(string(f), func(t *testing.T) {
+			assert := assert.New(t)
+			got := got(f)
+			for _, want := range []string{
+				issueCredentialAuditOperation,
+				"s_1234567890",
+				lib.PublicId,
+				lib.StoreId,
+				lib.VaultPath,
+				lib.HttpMethod,
+				"http_request_body_sent",
+			} {
+				assert.Contains(got, want)
+			}
+			assert.NotContains(got, secretBody)
+			assert.NotContains(got, "REDACTED")
+		})
+	}
+}
+
+func TestRepository_PreviewCredentialFields(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+
+	// a fake Vault server which returns a known set of fields and records
+	// the requests it receives
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		req := r.Method + " " + r.URL.Path
+		if r.Method != http.MethodGet && r.URL.Path != "/v1/sys/leases/revoke" {
+			req += " " + r.Header.Get("Content-Type")
+		}
+		requests = append(requests, req)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/sys/leases/revoke" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		fmt.Fprint(w, `{"lease_id":"database/creds/opened/lease","lease_duration":3600,"data":{"username":"u","password":"p","ttl":60}}`)
+	}))
+	t.Cleanup(srv.Close)
+	gotRequests := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		r := requests
+		requests = nil
+		return r
+	}
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	cs := TestCredentialStore(t, conn, wrapper, prj.GetPublicId(), srv.URL, "token", "accessor")
+	newLibrary := func(t *testing.T, opt ...Option) *CredentialLibrary {
+		t.Helper()
+		in, err := NewCredentialLibrary(ctx, cs.GetPublicId(), "database/creds/opened", opt...)
+		require.NoError(t, err)
+		lib, err := repo.CreateCredentialLibrary(ctx, prj.GetPublicId(), in)
+		require.NoError(t, err)
+		return lib
+	}
+	getLib := newLibrary(t)
+	postLib := newLibrary(t, WithName("post"), WithMethod(MethodPost), WithRequestBody([]byte(`{"common_name":"boundary"}`)))
+	formLib := newLibrary(t, WithName("form"), WithMethod(MethodPut), WithRequestBody([]byte(`common_name=boundary`)), WithContentType(ContentTypeForm))
+	patchLib := newLibrary(t, WithName("patch"), WithMethod(MethodPatch), WithRequestBody([]byte(`{"ttl":60}`)))
+	templatedLib := newLibrary(t, WithName("templated"), WithTemplatedVaultPath(true))
+	gotRequests()
+
+	t.Run("requires-live-request", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.PreviewCredentialFields(ctx, getLib.GetPublicId())
+		require.Error(err)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err code: %q got err: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+
+		got, err = repo.PreviewCredentialFields(ctx, getLib.GetPublicId(), WithLiveRequest(false))
+		require.Error(err)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err code: %q got err: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+		assert.Empty(gotRequests())
+	})
+	t.Run("no-library-id", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.PreviewCredentialFields(ctx, "", WithLiveRequest(true))
+		require.Error(err)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err code: %q got err: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+	})
+	t.Run("unknown-library", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.PreviewCredentialFields(ctx, CredentialLibraryPrefix+"_1234567890", WithLiveRequest(true))
+		require.Error(err)
+		assert.Truef(errors.Match(errors.T(errors.RecordNotFound), err), "want err code: %q got err: %q", errors.RecordNotFound, err)
+		assert.Nil(got)
+	})
+	t.Run("templated-vault-path", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.PreviewCredentialFields(ctx, templatedLib.GetPublicId(), WithLiveRequest(true))
+		require.Error(err)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err code: %q got err: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+		assert.Empty(gotRequests())
+	})
+	t.Run("get", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.PreviewCredentialFields(ctx, getLib.GetPublicId(), WithLiveRequest(true))
+		require.NoError(err)
+		assert.Equal([]string{"password", "ttl", "username"}, got)
+		assert.Equal([]string{"GET /v1/database/creds/opened", "PUT /v1/sys/leases/revoke"}, gotRequests())
+	})
+	t.Run("post", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.PreviewCredentialFields(ctx, postLib.GetPublicId(), WithLiveRequest(true))
+		require.NoError(err)
+		assert.Equal([]string{"password", "ttl", "username"}, got)
+		// Vault handles POST and PUT requests identically and the client
+		// sends both as PUT
+		assert.Equal([]string{"PUT /v1/database/creds/opened application/json", "PUT /v1/sys/leases/revoke"}, gotRequests())
+	})
+	t.Run("form-content-type", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.PreviewCredentialFields(ctx, formLib.GetPublicId(), WithLiveRequest(true))
+		require.NoError(err)
+		assert.Equal([]string{"password", "ttl", "username"}, got)
+		assert.Equal([]string{"PUT /v1/database/creds/opened application/x-www-form-urlencoded", "PUT /v1/sys/leases/revoke"}, gotRequests())
+	})
+	t.Run("patch", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.PreviewCredentialFields(ctx, patchLib.GetPublicId(), WithLiveRequest(true))
+		require.NoError(err)
+		assert.Equal([]string{"password", "ttl", "username"}, got)
+		assert.Equal([]string{"PATCH /v1/database/creds/opened application/merge-patch+json", "PUT /v1/sys/leases/revoke"}, gotRequests())
+	})
+}
@@ -0,0 +1,101 @@
+package vault
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/boundary/internal/db"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+)
+
+// SetScopeDefaultNamespace sets the default Vault namespace for credential
+// stores created in scopeId. The default is only applied when a credential
+// store is created without a namespace; existing credential stores are not
+// changed. An empty namespace removes the default for scopeId.
+func (r *Repository) SetScopeDefaultNamespace(ctx context.Context, scopeId, namespace string, _ ...Option) error {
+	const op = "vault.(Repository).SetScopeDefaultNamespace"
+	if scopeId == "" {
+		return errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	}
+
+	oplogWrapper, err := r.kms.GetWrapper(ctx, scopeId, kms.KeyPurposeOplog)
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg("unable to get oplog wrapper"))
+	}
+
+	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
+		func(reader db.Reader, w db.Writer) error {
+			current := allocScopeDefault()
+			err := reader.LookupWhere(ctx, current, "scope_id = ?", scopeId)
+			switch {
+			case errors.IsNotFoundError(err):
+				current = nil
+			case err != nil:
+				return errors.Wrap(ctx, err, op)
+			}
+
+			switch {
+			case namespace == "" && current == nil:
+				return nil
+			case namespace == "":
+				rowsDeleted, err := w.Delete(ctx, current, db.WithOplog(oplogWrapper, current.oplog(oplog.OpType_OP_TYPE_DELETE)))
+				if err != nil {
+					return errors.Wrap(ctx, err, op)
+				}
+				if rowsDeleted > 1 {
+					return errors.New(ctx, errors.MultipleRecords, op, "more than 1 scope default would have been deleted")
+				}
+			case current == nil:
+				d := allocScopeDefault()
+				d.ScopeId = scopeId
+				d.Namespace = namespace
+				if err := w.Create(ctx, d, db.WithOplog(oplogWrapper, d.oplog(oplog.OpType_OP_TYPE_CREATE))); err != nil {
+					return errors.Wrap(ctx, err, op)
+				}
+			default:
+				d := current.clone()
+				d.Namespace = namespace
+				rowsUpdated, err := w.Update(ctx, d, []string{"Namespace"}, nil, db.WithOplog(oplogWrapper, d.oplog(oplog.OpType_OP_TYPE_UPDATE)))
+				if err != nil {
+					return errors.Wrap(ctx, err, op)
+				}
+				if rowsUpdated > 1 {
+					return errors.New(ctx, errors.MultipleRecords, op, "more than 1 scope default would have been updated")
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("failed for %s", scopeId)))
+	}
+	return nil
+}
+
+// LookupScopeDefaultNamespace returns the default Vault namespace for
+// credential stores created in scopeId. An empty string is returned if
+// scopeId does not have a default namespace.
+func (r *Repository) LookupScopeDefaultNamespace(ctx context.Context, scopeId string, _ ...Option) (string, error) {
+	const op = "vault.(Repository).LookupScopeDefaultNamespace"
+	if scopeId == "" {
+		return "", errors.New(ctx, errors.InvalidParameter, op, "no scope id")
+	}
+	rows, err := r.reader.Query(ctx, scopeDefaultNamespaceQuery, []interface{}{sql.Named("scope_id", scopeId)})
+	if err != nil {
+		return "", errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("failed for %s", scopeId)))
+	}
+	defer rows.Close()
+	var namespace string
+	for rows.Next() {
+		if err := rows.Scan(&namespace); err != nil {
+			return "", errors.Wrap(ctx, err, op)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", errors.Wrap(ctx, err, op)
+	}
+	return namespace, nil
+}
@@ -0,0 +1,140 @@
+package vault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/internal/db"
+	dbassert "github.com/hashicorp/boundary/internal/db/assert"
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
+	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ScopeDefaultNamespace(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	ctx := context.Background()
+
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	v := NewTestVaultServer(t)
+
+	newStore := func(t *testing.T, scopeId string, opt ...Option) *CredentialStore {
+		t.Helper()
+		_, token := v.CreateToken(t)
+		in, err := NewCredentialStore(ctx, scopeId, v.Addr, []byte(token), opt...)
+		require.NoError(t, err)
+		got, err := repo.CreateCredentialStore(ctx, in)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		return got
+	}
+
+	t.Run("invalid-scope-id", func(t *testing.T) {
+		assert := assert.New(t)
+		err := repo.SetScopeDefaultNamespace(ctx, "", "ns")
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		got, err := repo.LookupScopeDefaultNamespace(ctx, "")
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Empty(got)
+	})
+
+	t.Run("no-default", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		got, err := repo.LookupScopeDefaultNamespace(ctx, prj.GetPublicId())
+		require.NoError(err)
+		assert.Empty(got)
+
+		cs := newStore(t, prj.GetPublicId())
+		assert.Empty(cs.Namespace)
+	})
+
+	t.Run("inherit-when-unset", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), "scope-ns"))
+		got, err := repo.LookupScopeDefaultNamespace(ctx, prj.GetPublicId())
+		require.NoError(err)
+		assert.Equal("scope-ns", got)
+
+		cs := newStore(t, prj.GetPublicId())
+		assert.Equal("scope-ns", cs.Namespace)
+
+		// changing the default does not change existing stores
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), "new-scope-ns"))
+		got2, err := repo.LookupCredentialStore(ctx, cs.GetPublicId())
+		require.NoError(err)
+		assert.Equal("scope-ns", got2.Namespace)
+
+		cs2 := newStore(t, prj.GetPublicId())
+		assert.Equal("new-scope-ns", cs2.Namespace)
+	})
+
+	t.Run("override-when-set", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), "scope-ns"))
+
+		cs := newStore(t, prj.GetPublicId(), WithNamespace("store-ns"))
+		assert.Equal("store-ns", cs.Namespace)
+	})
+
+	t.Run("clear-store-namespace", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), "scope-ns"))
+
+		cs := newStore(t, prj.GetPublicId())
+		require.Equal("scope-ns", cs.Namespace)
+
+		cs.Namespace = ""
+		got, gotCount, err := repo.UpdateCredentialStore(ctx, cs, cs.Version, []string{"Namespace"})
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal(1, gotCount)
+		assert.Empty(got.Namespace)
+
+		underlyingDB, err := conn.SqlDB(ctx)
+		require.NoError(err)
+		dbassert.New(t, underlyingDB).IsNull(got, "namespace")
+	})
+
+	t.Run("remove-default", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), "scope-ns"))
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), ""))
+
+		got, err := repo.LookupScopeDefaultNamespace(ctx, prj.GetPublicId())
+		require.NoError(err)
+		assert.Empty(got)
+
+		cs := newStore(t, prj.GetPublicId())
+		assert.Empty(cs.Namespace)
+	})
+	t.Run("oplog", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), "scope-ns"))
+		assert.NoError(db.TestVerifyOplog(t, rw, prj.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
+
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), "new-scope-ns"))
+		assert.NoError(db.TestVerifyOplog(t, rw, prj.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_UPDATE), db.WithCreateNotBefore(10*time.Second)))
+
+		require.NoError(repo.SetScopeDefaultNamespace(ctx, prj.GetPublicId(), ""))
+		assert.NoError(db.TestVerifyOplog(t, rw, prj.GetPublicId(), db.WithOperation(oplog.OpType_OP_TYPE_DELETE), db.WithCreateNotBefore(10*time.Second)))
+	})
+}
@@ -1,12 +1,16 @@
 package vault
 
 import (
+	"context"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/jackc/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -18,6 +22,7 @@ func TestRepository_New(t *testing.T) {
 	wrapper := db.TestWrapper(t)
 	kmsCache := kms.TestKms(t, conn, wrapper)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
+	limiters := NewRateLimiters(2.5, 5)
 
 	type args struct {
 		r         db.Reader
@@ -42,11 +47,15 @@ func TestRepository_New(t *testing.T) {
 				scheduler: sche,
 			},
 			want: &Repository{
-				reader:       rw,
-				writer:       rw,
-				kms:          kmsCache,
-				scheduler:    sche,
-				defaultLimit: db.DefaultLimit,
+				reader:            rw,
+				writer:            rw,
+				kms:               kmsCache,
+				scheduler:         sche,
+				defaultLimit:      db.DefaultLimit,
+				defaultHttpMethod: DefaultHttpMethod,
+				maxUpdateAttempts: defaultMaxUpdateAttempts,
+				updateBackoff:     db.ExpBackoff{},
+				limiters:          NewRateLimiters(0, 0),
 			},
 		},
 		{
@@ -59,13 +68,158 @@ func TestRepository_New(t *testing.T) {
 				opts:      []Option{WithLimit(5)},
 			},
 			want: &Repository{
-				reader:       rw,
-				writer:       rw,
-				kms:          kmsCache,
-				scheduler:    sche,
-				defaultLimit: 5,
+				reader:            rw,
+				writer:            rw,
+				kms:               kmsCache,
+				scheduler:         sche,
+				defaultLimit:      5,
+				defaultHttpMethod: DefaultHttpMethod,
+				maxUpdateAttempts: defaultMaxUpdateAttempts,
+				updateBackoff:     db.ExpBackoff{},
+				limiters:          NewRateLimiters(0, 0),
 			},
 		},
+		{
+			name: "valid-with-max-limit",
+			args: args{
+				r:         rw,
+				w:         rw,
+				kms:       kmsCache,
+				scheduler: sche,
+				opts:      []Option{WithMaxLimit(100)},
+			},
+			want: &Repository{
+				reader:            rw,
+				writer:            rw,
+				kms:               kmsCache,
+				scheduler:         sche,
+				defaultLimit:      db.DefaultLimit,
+				maxLimit:          100,
+				defaultHttpMethod: DefaultHttpMethod,
+				maxUpdateAttempts: defaultMaxUpdateAttempts,
+				updateBackoff:     db.ExpBackoff{},
+				limiters:          NewRateLimiters(0, 0),
+			},
+		},
+		{
+			name: "valid-with-default-http-method",
+			args: args{
+				r:         rw,
+				w:         rw,
+				kms:       kmsCache,
+				scheduler: sche,
+				opts:      []Option{WithDefaultHttpMethod(MethodPost)},
+			},
+			want: &Repository{
+				reader:            rw,
+				writer:            rw,
+				kms:               kmsCache,
+				scheduler:         sche,
+				defaultLimit:      db.DefaultLimit,
+				defaultHttpMethod: MethodPost,
+				maxUpdateAttempts: defaultMaxUpdateAttempts,
+				updateBackoff:     db.ExpBackoff{},
+				limiters:          NewRateLimiters(0, 0),
+			},
+		},
+		{
+			name: "valid-with-max-update-attempts",
+			args: args{
+				r:         rw,
+				w:         rw,
+				kms:       kmsCache,
+				scheduler: sche,
+				opts:      []Option{WithMaxUpdateAttempts(5)},
+			},
+			want: &Repository{
+				reader:            rw,
+				writer:            rw,
+				kms:               kmsCache,
+				scheduler:         sche,
+				defaultLimit:      db.DefaultLimit,
+				defaultHttpMethod: DefaultHttpMethod,
+				maxUpdateAttempts: 5,
+				updateBackoff:     db.ExpBackoff{},
+				limiters:          NewRateLimiters(0, 0),
+			},
+		},
+		{
+			name: "valid-with-rate-limiters",
+			args: args{
+				r:         rw,
+				w:         rw,
+				kms:       kmsCache,
+				scheduler: sche,
+				opts:      []Option{WithRateLimiters(limiters)},
+			},
+			want: &Repository{
+				reader:            rw,
+				writer:            rw,
+				kms:               kmsCache,
+				scheduler:         sche,
+				defaultLimit:      db.DefaultLimit,
+				defaultHttpMethod: DefaultHttpMethod,
+				maxUpdateAttempts: defaultMaxUpdateAttempts,
+				updateBackoff:     db.ExpBackoff{},
+				limiters:          limiters,
+			},
+		},
+		{
+			name: "valid-with-token-file-directory",
+			args: args{
+				r:         rw,
+				w:         rw,
+				kms:       kmsCache,
+				scheduler: sche,
+				opts:      []Option{WithTokenFileDirectory("/var/run/boundary")},
+			},
+			want: &Repository{
+				reader:            rw,
+				writer:            rw,
+				kms:               kmsCache,
+				scheduler:         sche,
+				defaultLimit:      db.DefaultLimit,
+				defaultHttpMethod: DefaultHttpMethod,
+				maxUpdateAttempts: defaultMaxUpdateAttempts,
+				updateBackoff:     db.ExpBackoff{},
+				limiters:          NewRateLimiters(0, 0),
+				tokenFileDir:      "/var/run/boundary",
+			},
+		},
+		{
+			name: "valid-with-timing-observations",
+			args: args{
+				r:         rw,
+				w:         rw,
+				kms:       kmsCache,
+				scheduler: sche,
+				opts:      []Option{WithTimingObservations(true)},
+			},
+			want: &Repository{
+				reader:            rw,
+				writer:            rw,
+				kms:               kmsCache,
+				scheduler:         sche,
+				defaultLimit:      db.DefaultLimit,
+				defaultHttpMethod: DefaultHttpMethod,
+				maxUpdateAttempts: defaultMaxUpdateAttempts,
+				updateBackoff:     db.ExpBackoff{},
+				limiters:          NewRateLimiters(0, 0),
+				observeTimings:    true,
+			},
+		},
+		{
+			name: "invalid-default-http-method",
+			args: args{
+				r:         rw,
+				w:         rw,
+				kms:       kmsCache,
+				scheduler: sche,
+				opts:      []Option{WithDefaultHttpMethod(Method("DELETE"))},
+			},
+			want:      nil,
+			wantIsErr: errors.InvalidParameter,
+		},
 		{
 			name: "nil-reader",
 			args: args{
@@ -126,7 +280,7 @@ func TestRepository_New(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			assert, require := assert.New(t), require.New(t)
-			got, err := NewRepository(tt.args.r, tt.args.w, tt.args.kms, tt.args.scheduler, tt.args.opts...)
+			got, err := NewRepository(context.Background(), tt.args.r, tt.args.w, tt.args.kms, tt.args.scheduler, tt.args.opts...)
 			if tt.wantIsErr != 0 {
 				assert.Truef(errors.Match(errors.T(tt.wantIsErr), err), "want err: %q got: %q", tt.wantIsErr, err)
 				assert.Nil(got)
@@ -138,3 +292,120 @@ func TestRepository_New(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_retryOnConflict(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	const op = "vault.TestRepository_retryOnConflict"
+	// a serialization failure as returned by postgres
+	conflict := func() error {
+		return errors.Wrap(ctx, &pgconn.PgError{Code: "40001"}, op, errors.WithoutEvent())
+	}
+	notUnique := func() error {
+		return errors.New(ctx, errors.NotUnique, op, "name already exists", errors.WithoutEvent())
+	}
+
+	tests := []struct {
+		name         string
+		results      []func() error
+		wantAttempts int
+		wantErr      errors.Code
+	}{
+		{
+			name:         "success",
+			results:      []func() error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "transient-conflict",
+			results:      []func() error{conflict, nil},
+			wantAttempts: 2,
+		},
+		{
+			name:         "persistent-conflict",
+			results:      []func() error{conflict, conflict, conflict, nil},
+			wantAttempts: 3,
+			wantErr:      errors.TransactionConflict,
+		},
+		{
+			name:         "not-unique-not-retried",
+			results:      []func() error{notUnique, nil},
+			wantAttempts: 1,
+			wantErr:      errors.NotUnique,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			r := &Repository{
+				maxUpdateAttempts: defaultMaxUpdateAttempts,
+				updateBackoff:     db.ConstBackoff{DurationMs: 1},
+			}
+			var attempts int
+			err := r.retryOnConflict(ctx, func() error {
+				res := tt.results[attempts]
+				attempts++
+				if res == nil {
+					return nil
+				}
+				return res()
+			})
+			assert.Equal(tt.wantAttempts, attempts)
+			if tt.wantErr != 0 {
+				assert.Truef(errors.Match(errors.T(tt.wantErr), err), "want err: %q got: %q", tt.wantErr, err)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestRepository_Concurrent(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	rw := db.New(conn)
+	wrapper := db.TestWrapper(t)
+	kmsCache := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+
+	_, prj := iam.TestScopes(t, iam.TestRepo(t, conn, wrapper))
+	stores := TestCredentialStores(t, conn, wrapper, prj.GetPublicId(), 2)
+	var libs []*CredentialLibrary
+	for _, cs := range stores {
+		libs = append(libs, TestCredentialLibraries(t, conn, wrapper, cs.GetPublicId(), 3)...)
+	}
+
+	repo, err := NewRepository(context.Background(), rw, rw, kmsCache, sche)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	const workers = 20
+	ctx := context.Background()
+	errs := make(chan error, workers*4)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cs := stores[i%len(stores)]
+			if _, err := repo.LookupCredentialStore(ctx, cs.GetPublicId()); err != nil {
+				errs <- err
+			}
+			if _, err := repo.ListCredentialStores(ctx, []string{prj.GetPublicId()}); err != nil {
+				errs <- err
+			}
+			if _, err := repo.LookupCredentialLibrary(ctx, libs[i%len(libs)].GetPublicId()); err != nil {
+				errs <- err
+			}
+			if _, err := repo.ListCredentialLibraries(ctx, cs.GetPublicId()); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
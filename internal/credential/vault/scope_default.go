@@ -0,0 +1,50 @@
+package vault
+
+import (
+	"github.com/hashicorp/boundary/internal/credential/vault/store"
+	"github.com/hashicorp/boundary/internal/oplog"
+	"google.golang.org/protobuf/proto"
+)
+
+// A ScopeDefault contains the default values applied to Vault credential
+// stores created in a scope.
+type ScopeDefault struct {
+	*store.ScopeDefault
+	tableName string `gorm:"-"`
+}
+
+func allocScopeDefault() *ScopeDefault {
+	return &ScopeDefault{
+		ScopeDefault: &store.ScopeDefault{},
+	}
+}
+
+func (d *ScopeDefault) clone() *ScopeDefault {
+	cp := proto.Clone(d.ScopeDefault)
+	return &ScopeDefault{
+		ScopeDefault: cp.(*store.ScopeDefault),
+	}
+}
+
+// TableName returns the table name.
+func (d *ScopeDefault) TableName() string {
+	if d.tableName != "" {
+		return d.tableName
+	}
+	return "credential_vault_scope_default"
+}
+
+// SetTableName sets the table name.
+func (d *ScopeDefault) SetTableName(n string) {
+	d.tableName = n
+}
+
+func (d *ScopeDefault) oplog(op oplog.OpType) oplog.Metadata {
+	metadata := oplog.Metadata{
+		"resource-public-id": []string{d.ScopeId},
+		"resource-type":      []string{"credential-vault-scope-default"},
+		"op-type":            []string{op.String()},
+		"scope-id":           []string{d.ScopeId},
+	}
+	return metadata
+}
@@ -47,3 +47,25 @@ func (s KeySecret) GoString() string {
 func (s KeySecret) MarshalJSON() ([]byte, error) {
 	return json.Marshal([]byte(redactedKeySecret))
 }
+
+// SecretIdSecret equals a Vault AppRole SecretID. This type provides a
+// wrapper so the secret isn't inadvertently leaked into a log or error.
+type SecretIdSecret []byte
+
+// redactedSecretIdSecret is the redacted string or json for a Vault AppRole SecretID.
+const redactedSecretIdSecret = "[REDACTED: Vault secret_id_secret]"
+
+// String will redact the SecretIdSecret.
+func (s SecretIdSecret) String() string {
+	return redactedSecretIdSecret
+}
+
+// GoString will redact the SecretIdSecret.
+func (s SecretIdSecret) GoString() string {
+	return redactedSecretIdSecret
+}
+
+// MarshalJSON will redact the SecretIdSecret.
+func (s SecretIdSecret) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]byte(redactedSecretIdSecret))
+}
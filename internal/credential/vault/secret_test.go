@@ -130,3 +130,44 @@ func TestKeySecret_MarshalJSON(t *testing.T) {
 		assert.Equal(testB, sec.B)
 	})
 }
+
+func TestSecretIdSecret_String(t *testing.T) {
+	t.Parallel()
+	t.Run("redacted", func(t *testing.T) {
+		assert := assert.New(t)
+		const want = redactedSecretIdSecret
+		s := SecretIdSecret("special secret")
+		assert.Equalf(want, s.String(), "SecretIdSecret.String() = %v, want %v", s.String(), want)
+
+		// Verify stringer is called
+		got := fmt.Sprintf("%s", s)
+		assert.Equalf(want, got, "SecretIdSecret.String() = %v, want %v", got, want)
+	})
+}
+
+func TestSecretIdSecret_GoString(t *testing.T) {
+	t.Parallel()
+	t.Run("redacted", func(t *testing.T) {
+		assert := assert.New(t)
+		const want = redactedSecretIdSecret
+		s := SecretIdSecret("magic secret")
+		assert.Equalf(want, s.GoString(), "SecretIdSecret.GoString() = %v, want %v", s.GoString(), want)
+
+		// Verify gostringer is called
+		got := fmt.Sprintf("%#v", s)
+		assert.Equalf(want, got, "SecretIdSecret.GoString() = %v, want %v", got, want)
+	})
+}
+
+func TestSecretIdSecret_MarshalJSON(t *testing.T) {
+	t.Parallel()
+	t.Run("redacted", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		want, err := json.Marshal([]byte(redactedSecretIdSecret))
+		require.NoError(err)
+		s := SecretIdSecret("normal secret")
+		got, err := s.MarshalJSON()
+		require.NoError(err)
+		assert.Equalf(want, got, "SecretIdSecret.MarshalJSON() = %s, want %s", got, want)
+	})
+}
@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// templatePlaceholder matches a placeholder token such as {{common_name}}
+// in the VaultPath or HttpRequestBody of a credential library used as a
+// template. Tokens starting with a dot, such as {{.Username}}, are session
+// variables of a templated vault path and are not matched.
+var templatePlaceholder = regexp.MustCompile(`{{\s*([A-Za-z0-9_\-][A-Za-z0-9_.\-]*)\s*}}`)
+
+// expandTemplate replaces each placeholder token in s with its value in
+// vars. If escape is not nil, each value is passed through escape before it
+// replaces the placeholder. An errors.InvalidParameter error listing the
+// names of all unresolved placeholders is returned if vars does not contain
+// a value for every placeholder in s.
+func expandTemplate(ctx context.Context, s string, vars map[string]string, escape func(string) string) (string, error) {
+	const op = "vault.expandTemplate"
+	missing := make(map[string]bool)
+	out := templatePlaceholder.ReplaceAllStringFunc(s, func(token string) string {
+		name := templatePlaceholder.FindStringSubmatch(token)[1]
+		v, ok := vars[name]
+		if !ok {
+			missing[name] = true
+			return token
+		}
+		if escape != nil {
+			v = escape(v)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for n := range missing {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unresolved template placeholders: %s", strings.Join(names, ", ")))
+	}
+	return out, nil
+}
+
+// requestBodyEscaper returns the function used to escape the values of
+// placeholders in a request body with content type ct. Values in a JSON
+// body are escaped as the contents of a JSON string, so a placeholder in a
+// JSON body must be inside a string literal. Values in a form body are
+// query escaped.
+func requestBodyEscaper(ct string) func(string) string {
+	if ct == ContentTypeForm {
+		return url.QueryEscape
+	}
+	return escapeJsonString
+}
+
+// escapeJsonString returns s encoded as the contents of a JSON string,
+// without the surrounding quotes.
+func escapeJsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
@@ -0,0 +1,93 @@
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_expandTemplate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		in      string
+		vars    map[string]string
+		escape  func(string) string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "no-placeholders",
+			in:   "/pki/issue/boundary",
+			want: "/pki/issue/boundary",
+		},
+		{
+			name: "common-name",
+			in:   `{"common_name":"{{common_name}}"}`,
+			vars: map[string]string{"common_name": "boundary.example.com"},
+			want: `{"common_name":"boundary.example.com"}`,
+		},
+		{
+			name: "spaces-and-repeats",
+			in:   "/pki/{{ role }}/{{role}}",
+			vars: map[string]string{"role": "web"},
+			want: "/pki/web/web",
+		},
+		{
+			name: "unused-vars",
+			in:   "/pki/{{role}}",
+			vars: map[string]string{"role": "web", "ttl": "1h"},
+			want: "/pki/web",
+		},
+		{
+			name: "session-variables-ignored",
+			in:   "/database/creds/{{role}}-{{.Username}}",
+			vars: map[string]string{"role": "web"},
+			want: "/database/creds/web-{{.Username}}",
+		},
+		{
+			name:   "json-escaped",
+			in:     `{"common_name":"{{common_name}}"}`,
+			vars:   map[string]string{"common_name": `web", "ttl":"87600h`},
+			escape: requestBodyEscaper(ContentTypeJson),
+			want:   `{"common_name":"web\", \"ttl\":\"87600h"}`,
+		},
+		{
+			name:   "default-content-type-json-escaped",
+			in:     `{"common_name":"{{common_name}}"}`,
+			vars:   map[string]string{"common_name": "a\nb"},
+			escape: requestBodyEscaper(""),
+			want:   `{"common_name":"a\nb"}`,
+		},
+		{
+			name:   "form-escaped",
+			in:     "common_name={{common_name}}",
+			vars:   map[string]string{"common_name": "web&ttl=87600h"},
+			escape: requestBodyEscaper(ContentTypeForm),
+			want:   "common_name=web%26ttl%3D87600h",
+		},
+		{
+			name:    "unresolved",
+			in:      `/pki/{{role}}/{{mount}} {"common_name":"{{common_name}}"}`,
+			vars:    map[string]string{"role": "web"},
+			wantErr: "unresolved template placeholders: common_name, mount",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got, err := expandTemplate(context.Background(), tt.in, tt.vars, tt.escape)
+			if tt.wantErr != "" {
+				assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+				assert.Contains(err.Error(), tt.wantErr)
+				assert.Empty(got)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
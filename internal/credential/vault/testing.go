@@ -44,7 +44,7 @@ func TestCredentialStore(t *testing.T, conn *db.DB, wrapper wrapping.Wrapper, sc
 	assert.NoError(t, err)
 	require.NotNil(t, databaseWrapper)
 
-	cs, err := NewCredentialStore(scopeId, vaultAddr, []byte(vaultToken), opts...)
+	cs, err := NewCredentialStore(ctx, scopeId, vaultAddr, []byte(vaultToken), opts...)
 	assert.NoError(t, err)
 	require.NotNil(t, cs)
 	id, err := newCredentialStoreId()
@@ -120,7 +120,7 @@ func TestCredentialLibraries(t *testing.T, conn *db.DB, _ wrapping.Wrapper, stor
 	var libs []*CredentialLibrary
 
 	for i := 0; i < count; i++ {
-		lib, err := NewCredentialLibrary(storeId, fmt.Sprintf("vault/path%d", i), WithMethod(MethodGet))
+		lib, err := NewCredentialLibrary(context.Background(), storeId, fmt.Sprintf("vault/path%d", i), WithMethod(MethodGet))
 		assert.NoError(err)
 		require.NotNil(lib)
 		id, err := newCredentialLibraryId()
@@ -152,7 +152,7 @@ func TestCredentials(t *testing.T, conn *db.DB, wrapper wrapping.Wrapper, librar
 	ctx := context.Background()
 	kms := kms.TestKms(t, conn, wrapper)
 	sche := scheduler.TestScheduler(t, conn, wrapper)
-	repo, err := NewRepository(rw, rw, kms, sche)
+	repo, err := NewRepository(ctx, rw, rw, kms, sche)
 	assert.NoError(err)
 	require.NotNil(repo)
 
@@ -629,10 +629,10 @@ func (v *TestVaultServer) clientUsingToken(t *testing.T, token string) *client {
 		ClientKey:  v.ClientKey,
 	}
 
-	client, err := newClient(conf)
+	client, err := newClient(context.Background(), conf)
 	require.NoError(err)
 	require.NotNil(client)
-	require.NoError(client.ping())
+	require.NoError(client.ping(context.Background()))
 	return client
 }
 
@@ -719,6 +719,50 @@ func (v *TestVaultServer) addPolicy(t *testing.T, name string, pc pathCapabiliti
 	require.NoError(vc.Sys().PutPolicy(name, policy))
 }
 
+// EnableAppRole enables the Vault AppRole auth method and creates a role
+// on it. It returns the RoleID and a SecretID for the role. Tokens issued
+// by logging in with the role are periodic and have the same policies as
+// tokens created with v.CreateToken.
+//
+// The default role name is boundary. WithTestRoleName, WithPolicies, and
+// WithTokenPeriod are the only test options supported.
+func (v *TestVaultServer) EnableAppRole(t *testing.T, opt ...TestOption) (roleId, secretId string) {
+	t.Helper()
+	require := require.New(t)
+	opts := getTestOpts(t, opt...)
+	vc := v.client(t).cl
+
+	auths, err := vc.Sys().ListAuth()
+	require.NoError(err)
+	if _, ok := auths["approle/"]; !ok {
+		require.NoError(vc.Sys().EnableAuthWithOptions("approle", &vault.EnableAuthOptions{Type: "approle"}))
+	}
+
+	rolePath := path.Join("auth/approle/role", opts.roleName)
+	roleOptions := map[string]interface{}{
+		"token_period":   opts.tokenPeriod.String(),
+		"token_policies": opts.policies,
+	}
+	_, err = vc.Logical().Write(rolePath, roleOptions)
+	require.NoError(err)
+
+	s, err := vc.Logical().Read(path.Join(rolePath, "role-id"))
+	require.NoError(err)
+	require.NotNil(s)
+	roleId, ok := s.Data["role_id"].(string)
+	require.True(ok)
+	require.NotEmpty(roleId)
+
+	s, err = vc.Logical().Write(path.Join(rolePath, "secret-id"), nil)
+	require.NoError(err)
+	require.NotNil(s)
+	secretId, ok = s.Data["secret_id"].(string)
+	require.True(ok)
+	require.NotEmpty(secretId)
+
+	return roleId, secretId
+}
+
 // MountPKI mounts the Vault PKI secret engine and initializes it by
 // generating a root certificate authority and creating a default role on
 // the mount. The root CA is returned.
@@ -1,6 +1,7 @@
 package vault
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -235,10 +236,10 @@ func TestNewVaultServer(t *testing.T) {
 			Token: TokenSecret(v.RootToken),
 		}
 
-		client, err := newClient(conf)
+		client, err := newClient(context.Background(), conf)
 		require.NoError(err)
 		require.NotNil(client)
-		require.NoError(client.ping())
+		require.NoError(client.ping(context.Background()))
 	})
 	t.Run("TestServerTLS", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
@@ -255,10 +256,10 @@ func TestNewVaultServer(t *testing.T) {
 			CaCert: v.CaCert,
 		}
 
-		client, err := newClient(conf)
+		client, err := newClient(context.Background(), conf)
 		require.NoError(err)
 		require.NotNil(client)
-		require.NoError(client.ping())
+		require.NoError(client.ping(context.Background()))
 	})
 	t.Run("TestClientTLS", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
@@ -279,10 +280,10 @@ func TestNewVaultServer(t *testing.T) {
 			ClientKey:  v.ClientKey,
 		}
 
-		client, err := newClient(conf)
+		client, err := newClient(context.Background(), conf)
 		require.NoError(err)
 		require.NotNil(client)
-		require.NoError(client.ping())
+		require.NoError(client.ping(context.Background()))
 	})
 	t.Run("TestClientTLS-with-client-key", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
@@ -311,10 +312,10 @@ func TestNewVaultServer(t *testing.T) {
 			ClientKey:  v.ClientKey,
 		}
 
-		client, err := newClient(conf)
+		client, err := newClient(context.Background(), conf)
 		require.NoError(err)
 		require.NotNil(client)
-		require.NoError(client.ping())
+		require.NoError(client.ping(context.Background()))
 	})
 }
 
@@ -467,14 +468,14 @@ func TestTestVaultServer_LookupLease(t *testing.T) {
 		Token:      TokenSecret(v.RootToken),
 	}
 
-	client, err := newClient(conf)
+	client, err := newClient(context.Background(), conf)
 	require.NoError(err)
 	require.NotNil(client)
-	assert.NoError(client.ping())
+	assert.NoError(client.ping(context.Background()))
 
 	// Create secret
 	credPath := path.Join("database", "creds", "opened")
-	cred, err := client.get(credPath)
+	cred, err := client.get(context.Background(), credPath)
 	require.NoError(err)
 
 	// Sleep to move ttl
@@ -502,7 +503,7 @@ func TestTestVaultServer_VerifyTokenInvalid(t *testing.T) {
 
 	_, token := v.CreateToken(t)
 	client := v.clientUsingToken(t, token)
-	err := client.revokeToken()
+	err := client.revokeToken(context.Background())
 	require.NoError(err)
 	v.VerifyTokenInvalid(t, token)
 
@@ -1,10 +1,15 @@
 package vault
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -12,6 +17,7 @@ import (
 	"github.com/hashicorp/go-rootcerts"
 	vault "github.com/hashicorp/vault/api"
 	"github.com/mitchellh/mapstructure"
+	"golang.org/x/time/rate"
 )
 
 type clientConfig struct {
@@ -23,6 +29,65 @@ type clientConfig struct {
 	TlsServerName string
 	TlsSkipVerify bool
 	Namespace     string
+
+	// TlsMinVersion is the minimum TLS version used to connect to Vault.
+	// If empty, TLS 1.2 is the minimum version.
+	TlsMinVersion string
+
+	// ConnectTimeout is the maximum duration to wait for a connection to
+	// Vault to be established. If zero, the Vault client default is used.
+	ConnectTimeout time.Duration
+
+	// RequestTimeout is the maximum duration to wait for a request to
+	// Vault to complete. If zero, the Vault client default is used.
+	RequestTimeout time.Duration
+
+	// WrapTtl is the TTL of the response-wrapping tokens Vault returns in
+	// place of the responses to the client's requests. Wrapped responses
+	// are unwrapped with unwrap. If zero, responses are not wrapped.
+	WrapTtl time.Duration
+
+	// StoreId is the public id of the credential store the client is
+	// created for. Requests are rate limited per StoreId.
+	StoreId string
+
+	// RequestsPerSecond is the maximum rate of requests to Vault for
+	// StoreId. If zero, the default rate of Limiters is used.
+	RequestsPerSecond uint32
+
+	// Limiters holds the rate limiter for StoreId. If nil, requests are
+	// not rate limited.
+	Limiters *RateLimiters
+
+	// TokenFile is the path of a file containing the Vault token, such as
+	// the token sink of a Vault agent. If set, the token is read from the
+	// file before each request to Vault and Token is ignored.
+	TokenFile string
+
+	// TokenFileDir is the directory TokenFile must be in. The path of
+	// TokenFile is checked before each read, so the file cannot be replaced
+	// by a link to a file outside of the directory.
+	TokenFileDir string
+}
+
+// TLS versions supported as the minimum TLS version of a credential store.
+const (
+	TlsVersion12 = "tls12"
+	TlsVersion13 = "tls13"
+)
+
+// tlsMinVersion returns the crypto/tls version constant for v. TLS 1.2 is
+// returned if v is empty. An errors.InvalidParameter error is returned if
+// v is not a supported version.
+func tlsMinVersion(ctx context.Context, v string) (uint16, error) {
+	const op = "vault.tlsMinVersion"
+	switch v {
+	case "", TlsVersion12:
+		return tls.VersionTLS12, nil
+	case TlsVersion13:
+		return tls.VersionTLS13, nil
+	}
+	return 0, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unsupported tls min version: %q", v))
 }
 
 func (c *clientConfig) isValid() bool {
@@ -40,17 +105,37 @@ func (c *clientConfig) isClientTLS() bool {
 }
 
 type client struct {
-	cl    *vault.Client
-	token TokenSecret
+	cl           *vault.Client
+	token        TokenSecret
+	tokenFile    string
+	tokenFileDir string
+
+	limiter        *rate.Limiter
+	maxLimiterWait time.Duration
 }
 
-func newClient(c *clientConfig) (*client, error) {
+func newClient(ctx context.Context, c *clientConfig) (*client, error) {
 	const op = "vault.newClient"
 	if !c.isValid() {
-		return nil, errors.NewDeprecated(errors.InvalidParameter, op, "invalid configuration")
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "invalid configuration")
+	}
+	minVersion, err := tlsMinVersion(ctx, c.TlsMinVersion)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
 	}
 	vc := vault.DefaultConfig()
 	vc.Address = c.Addr
+	vc.HttpClient.Transport.(*http.Transport).TLSClientConfig.MinVersion = minVersion
+	if c.RequestTimeout > 0 {
+		vc.Timeout = c.RequestTimeout
+	}
+	if c.ConnectTimeout > 0 {
+		transport := vc.HttpClient.Transport.(*http.Transport)
+		transport.DialContext = (&net.Dialer{
+			Timeout:   c.ConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
 	if len(c.CaCert) > 0 {
 		rootConfig := &rootcerts.Config{
 			CACertificate: c.CaCert,
@@ -58,14 +143,14 @@ func newClient(c *clientConfig) (*client, error) {
 		tlsConfig := vc.HttpClient.Transport.(*http.Transport).TLSClientConfig
 		tlsConfig.InsecureSkipVerify = c.TlsSkipVerify
 		if err := rootcerts.ConfigureTLS(tlsConfig, rootConfig); err != nil {
-			return nil, errors.WrapDeprecated(err, op)
+			return nil, errors.Wrap(ctx, err, op)
 		}
 	}
 
 	if c.isClientTLS() {
 		clientCert, err := tls.X509KeyPair(c.ClientCert, c.ClientKey)
 		if err != nil {
-			return nil, errors.WrapDeprecated(err, op)
+			return nil, errors.Wrap(ctx, err, op)
 		}
 		tlsConfig := vc.HttpClient.Transport.(*http.Transport).TLSClientConfig
 		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
@@ -75,30 +160,130 @@ func newClient(c *clientConfig) (*client, error) {
 
 	vClient, err := vault.NewClient(vc)
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op)
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	token := c.Token
+	if c.TokenFile != "" {
+		if token, err = readTokenFile(ctx, c.TokenFileDir, c.TokenFile); err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
+	vClient.SetToken(string(token))
+	if c.WrapTtl > 0 {
+		wrapTtl := c.WrapTtl.String()
+		vClient.SetWrappingLookupFunc(func(_, path string) string {
+			if path == unwrapPath {
+				return ""
+			}
+			return wrapTtl
+		})
+	}
+
+	var limiter *rate.Limiter
+	if c.StoreId != "" && c.Limiters != nil {
+		limiter = c.Limiters.get(c.StoreId, c.RequestsPerSecond)
 	}
-	vClient.SetToken(string(c.Token))
 
 	return &client{
-		cl:    vClient,
-		token: c.Token,
+		cl:             vClient,
+		token:          token,
+		tokenFile:      c.TokenFile,
+		tokenFileDir:   c.TokenFileDir,
+		limiter:        limiter,
+		maxLimiterWait: maxRateLimitWait,
 	}, nil
 }
 
+// readTokenFile returns the Vault token in the file at path with leading
+// and trailing whitespace removed. The path is checked with
+// checkTokenFilePath before every read, so a file which is replaced by a
+// link to a file outside of dir is never read. An errors.InvalidParameter
+// error is returned if the path is not allowed, the file cannot be read or
+// it does not contain a token.
+func readTokenFile(ctx context.Context, dir, path string) (TokenSecret, error) {
+	const op = "vault.readTokenFile"
+	resolved, err := checkTokenFilePath(ctx, dir, path)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	b, err := ioutil.ReadFile(resolved)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.InvalidParameter), errors.WithMsg("unable to read vault token file"))
+	}
+	token := bytes.TrimSpace(b)
+	if len(token) == 0 {
+		return nil, errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("vault token file is empty: %s", path))
+	}
+	return TokenSecret(token), nil
+}
+
+// checkTokenFilePath returns path with its symbolic links resolved. An
+// errors.InvalidParameter error is returned if path is not an absolute
+// path of a file in dir or one of its subdirectories. Symbolic links in dir
+// and path are resolved before they are compared, so a link in dir cannot
+// be used to read a file outside of dir. Token files are not allowed if dir
+// is empty.
+func checkTokenFilePath(ctx context.Context, dir, path string) (string, error) {
+	const op = "vault.checkTokenFilePath"
+	switch {
+	case dir == "":
+		return "", errors.New(ctx, errors.InvalidParameter, op, "vault token files are not allowed: no token file directory is configured")
+	case !filepath.IsAbs(path):
+		return "", errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("vault token file path is not absolute: %s", path))
+	}
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, op, errors.WithCode(errors.InvalidParameter), errors.WithMsg("unable to resolve vault token file directory"))
+	}
+	resolvedPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, op, errors.WithCode(errors.InvalidParameter), errors.WithMsg("unable to resolve vault token file path"))
+	}
+	rel, err := filepath.Rel(resolvedDir, resolvedPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("vault token file is not in the token file directory: %s", path))
+	}
+	return resolvedPath, nil
+}
+
+// prepareRequest is called before each request to Vault. It blocks until
+// the rate limiter of c allows the request. If c reads its token from a
+// file, the token is read again so a token rotated by a Vault agent is
+// used for the request.
+func (c *client) prepareRequest(ctx context.Context) error {
+	const op = "vault.(client).prepareRequest"
+	if err := c.waitLimiter(ctx); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	if c.tokenFile == "" {
+		return nil
+	}
+	token, err := readTokenFile(ctx, c.tokenFileDir, c.tokenFile)
+	if err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	c.token = token
+	c.cl.SetToken(string(token))
+	return nil
+}
+
 // ping calls the /sys/health Vault endpoint and returns an error if no
 // response is returned. This endpoint is accessible with the default
 // policy in Vault 1.7.2. See
 // https://www.vaultproject.io/api-docs/system/health#read-health-information.
-func (c *client) ping() error {
+func (c *client) ping(ctx context.Context) error {
 	const op = "vault.(client).ping"
+	if err := c.prepareRequest(ctx); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
 	h, err := c.cl.Sys().Health()
 	switch {
 	case err != nil:
-		return errors.WrapDeprecated(err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+		return errors.Wrap(ctx, err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
 	case h == nil:
-		return errors.NewDeprecated(errors.Unavailable, op, fmt.Sprintf("no response: vault: %s", c.cl.Address()))
+		return errors.New(ctx, errors.Unavailable, op, fmt.Sprintf("no response: vault: %s", c.cl.Address()))
 	case !h.Initialized || h.Sealed:
-		return errors.NewDeprecated(errors.Unavailable, op, fmt.Sprintf("vault (%s): initialized: %t, sealed: %t ", c.cl.Address(), h.Initialized, h.Sealed))
+		return errors.New(ctx, errors.Unavailable, op, fmt.Sprintf("vault (%s): initialized: %t, sealed: %t ", c.cl.Address(), h.Initialized, h.Sealed))
 	}
 
 	return nil
@@ -106,25 +291,109 @@ func (c *client) ping() error {
 
 // renewToken calls the /auth/token/renew-self Vault endpoint and returns
 // the vault.Secret response. This endpoint is accessible with the default
-// policy in Vault 1.7.2. See
+// policy in Vault 1.7.2 so an errors.VaultTokenExpired error is returned
+// if Vault responds with a 403. See
 // https://www.vaultproject.io/api-docs/auth/token#renew-a-token-self.
-func (c *client) renewToken() (*vault.Secret, error) {
+func (c *client) renewToken(ctx context.Context) (*vault.Secret, error) {
 	const op = "vault.(client).renewToken"
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
 	t, err := c.cl.Auth().Token().RenewSelf(0)
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+		code := errors.Unknown
+		if isForbidden(err) {
+			code = errors.VaultTokenExpired
+		}
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(code), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
 	}
 	return t, nil
 }
 
 // revokeToken calls the /auth/token/revoke-self Vault endpoint. This
-// endpoint is accessible with the default policy in Vault 1.7.2. See
-// https://www.vaultproject.io/api-docs/auth/token#revoke-a-token-self.
-func (c *client) revokeToken() error {
+// endpoint is accessible with the default policy in Vault 1.7.2 so an
+// errors.VaultTokenExpired error is returned if Vault responds with a 403.
+// See https://www.vaultproject.io/api-docs/auth/token#revoke-a-token-self.
+func (c *client) revokeToken(ctx context.Context) error {
 	const op = "vault.(client).revokeToken"
+	if err := c.prepareRequest(ctx); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
 	// The `token` parameter is kept for backwards compatibility but is ignored, so use ""
 	if err := c.cl.Auth().Token().RevokeSelf(""); err != nil {
-		return errors.WrapDeprecated(err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+		code := errors.Unknown
+		if isForbidden(err) {
+			code = errors.VaultTokenExpired
+		}
+		return errors.Wrap(ctx, err, op, errors.WithCode(code), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+	}
+	return nil
+}
+
+// tokenExpired reports whether the Vault token used by c is expired or
+// otherwise no longer valid. It calls the /auth/token/lookup-self Vault
+// endpoint, which is accessible with the default policy in Vault 1.7.2,
+// and returns true if Vault responds with a 403. It is only called to
+// classify the error of a request which already waited on the rate
+// limiter and read the token, so it does not call prepareRequest.
+func (c *client) tokenExpired() bool {
+	_, err := c.cl.Auth().Token().LookupSelf()
+	return isForbidden(err)
+}
+
+// isForbidden reports whether err is a 403 response from Vault. Vault
+// responds with a 403 for requests made with an expired or invalid token
+// and for requests the token's policies do not permit.
+func isForbidden(err error) bool {
+	var respErr *vault.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden
+}
+
+// credentialRequestCode returns the errors.Code for err returned by Vault
+// for a credential request. If Vault responded with a 403 and the token
+// used by c is no longer valid, errors.VaultTokenExpired is returned.
+// Otherwise errors.VaultCredentialRequest is returned.
+func (c *client) credentialRequestCode(err error) errors.Code {
+	if isForbidden(err) && c.tokenExpired() {
+		return errors.VaultTokenExpired
+	}
+	return errors.VaultCredentialRequest
+}
+
+// appRoleLogin calls the /auth/approle/login Vault endpoint with roleId
+// and secretId and returns the client token of the response. This
+// endpoint does not require a Vault token. See
+// https://www.vaultproject.io/api-docs/auth/approle#login-with-approle.
+func (c *client) appRoleLogin(ctx context.Context, roleId string, secretId SecretIdSecret) (TokenSecret, error) {
+	const op = "vault.(client).appRoleLogin"
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	data := map[string]interface{}{
+		"role_id":   roleId,
+		"secret_id": string(secretId),
+	}
+	s, err := c.cl.Logical().Write("auth/approle/login", data)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+	}
+	if s == nil || s.Auth == nil || s.Auth.ClientToken == "" {
+		return nil, errors.New(ctx, errors.Unknown, op, fmt.Sprintf("no client token in response: vault: %s", c.cl.Address()))
+	}
+	return TokenSecret(s.Auth.ClientToken), nil
+}
+
+// revokeTokenAccessor calls the /auth/token/revoke-accessor Vault endpoint.
+// This endpoint is not accessible with the default policy in Vault 1.7.2.
+// See
+// https://www.vaultproject.io/api-docs/auth/token#revoke-a-token-accessor.
+func (c *client) revokeTokenAccessor(ctx context.Context, accessor string) error {
+	const op = "vault.(client).revokeTokenAccessor"
+	if err := c.prepareRequest(ctx); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	if err := c.cl.Auth().Token().RevokeAccessor(accessor); err != nil {
+		return errors.Wrap(ctx, err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
 	}
 	return nil
 }
@@ -133,11 +402,14 @@ func (c *client) revokeToken() error {
 // vault.Secret response. This endpoint is accessible with the default
 // policy in Vault 1.7.2. See
 // https://www.vaultproject.io/api-docs/system/leases#renew-lease.
-func (c *client) renewLease(leaseId string, leaseDuration time.Duration) (*vault.Secret, error) {
+func (c *client) renewLease(ctx context.Context, leaseId string, leaseDuration time.Duration) (*vault.Secret, error) {
 	const op = "vault.(client).renewLease"
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
 	t, err := c.cl.Sys().Renew(leaseId, int(leaseDuration.Round(time.Second).Seconds()))
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithCode(errors.VaultCredentialRequest), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.VaultCredentialRequest), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
 	}
 	return t, nil
 }
@@ -145,10 +417,13 @@ func (c *client) renewLease(leaseId string, leaseDuration time.Duration) (*vault
 // revokeLease calls the /sys/leases/revoke Vault endpoint. This endpoint
 // is NOT accessible with the default policy in Vault 1.7.2. See
 // https://www.vaultproject.io/api-docs/system/leases#revoke-lease.
-func (c *client) revokeLease(leaseId string) error {
+func (c *client) revokeLease(ctx context.Context, leaseId string) error {
 	const op = "vault.(client).revokeLease"
+	if err := c.prepareRequest(ctx); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
 	if err := c.cl.Sys().Revoke(leaseId); err != nil {
-		return errors.WrapDeprecated(err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+		return errors.Wrap(ctx, err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
 	}
 	return nil
 }
@@ -157,11 +432,14 @@ func (c *client) revokeLease(leaseId string) error {
 // the vault.Secret response. This endpoint is accessible with the default
 // policy in Vault 1.7.2. See
 // https://www.vaultproject.io/api-docs/auth/token#lookup-a-token-self.
-func (c *client) lookupToken() (*vault.Secret, error) {
+func (c *client) lookupToken(ctx context.Context) (*vault.Secret, error) {
 	const op = "vault.(client).lookupToken"
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
 	t, err := c.cl.Auth().Token().LookupSelf()
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(errors.Unknown), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
 	}
 	return t, nil
 }
@@ -174,38 +452,131 @@ func (c *client) swapToken(new TokenSecret) (old TokenSecret) {
 	return
 }
 
-func (c *client) get(path string) (*vault.Secret, error) {
+func (c *client) get(ctx context.Context, path string) (*vault.Secret, error) {
 	const op = "vault.(client).get"
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
 	s, err := c.cl.Logical().Read(path)
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithCode(errors.VaultCredentialRequest), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(c.credentialRequestCode(err)), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
 	}
 	return s, nil
 }
 
-func (c *client) post(path string, data []byte) (*vault.Secret, error) {
+// unwrapPath is the path of the /sys/wrapping/unwrap Vault endpoint.
+const unwrapPath = "sys/wrapping/unwrap"
+
+// unwrap returns the response wrapped by s by calling the
+// /sys/wrapping/unwrap Vault endpoint with the response-wrapping token of
+// s. s is returned if it is not a wrapped response. See
+// https://www.vaultproject.io/docs/concepts/response-wrapping.
+func (c *client) unwrap(ctx context.Context, s *vault.Secret) (*vault.Secret, error) {
+	const op = "vault.(client).unwrap"
+	if s == nil || s.WrapInfo == nil {
+		return s, nil
+	}
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+	u, err := c.cl.Logical().Unwrap(s.WrapInfo.Token)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(c.credentialRequestCode(err)), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+	}
+	if u == nil {
+		return nil, errors.New(ctx, errors.VaultCredentialRequest, op, fmt.Sprintf("vault: %s: empty unwrapped response", c.cl.Address()))
+	}
+	return u, nil
+}
+
+// post sends data to path with the HTTP POST method as the content type
+// contentType. If contentType is empty, DefaultContentType is used. Vault
+// handles POST and PUT requests identically.
+func (c *client) post(ctx context.Context, path string, data []byte, contentType string) (*vault.Secret, error) {
 	const op = "vault.(client).post"
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
 
-	if len(data) == 0 {
+	if contentType == "" {
+		contentType = DefaultContentType
+	}
+	if len(data) == 0 && contentType == ContentTypeJson {
 		// For POST and PUT methods, Vault requires a valid JSON object be
 		// sent even if the JSON object is empty
 		data = []byte(`{}`)
 	}
-	s, err := c.cl.Logical().WriteBytes(path, data)
+	s, err := c.write(ctx, "PUT", path, data, contentType)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(c.credentialRequestCode(err)), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+	}
+	return s, nil
+}
+
+// patch sends data to path with the HTTP PATCH method. Vault requires the
+// body of a PATCH request to be a JSON merge patch, see RFC 7386.
+func (c *client) patch(ctx context.Context, path string, data []byte) (*vault.Secret, error) {
+	const op = "vault.(client).patch"
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
+	}
+
+	if len(data) == 0 {
+		data = []byte(`{}`)
+	}
+	s, err := c.write(ctx, "PATCH", path, data, "application/merge-patch+json")
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithCode(errors.VaultCredentialRequest), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
+		return nil, errors.Wrap(ctx, err, op, errors.WithCode(c.credentialRequestCode(err)), errors.WithMsg(fmt.Sprintf("vault: %s", c.cl.Address())))
 	}
 	return s, nil
 }
 
+// write sends data to path with method and the Content-Type header set to
+// contentType. Responses are handled the same as the Logical().Write
+// methods of the Vault client: a 404 response without data returns a nil
+// secret and no error.
+func (c *client) write(ctx context.Context, method, path string, data []byte, contentType string) (*vault.Secret, error) {
+	r := c.cl.NewRequest(method, "/v1/"+path)
+	if r.Headers == nil {
+		r.Headers = make(http.Header)
+	}
+	r.Headers.Set("Content-Type", contentType)
+	r.BodyBytes = data
+
+	resp, err := c.cl.RawRequestWithContext(ctx, r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		secret, parseErr := vault.ParseSecret(resp.Body)
+		switch parseErr {
+		case nil:
+		case io.EOF:
+			return nil, nil
+		default:
+			return nil, err
+		}
+		if secret != nil && (len(secret.Warnings) > 0 || len(secret.Data) > 0) {
+			return secret, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vault.ParseSecret(resp.Body)
+}
+
 // capabilities calls the /sys/capabilities-self Vault endpoint and returns
 // the vault.Secret response. This endpoint is accessible with the default
 // policy in Vault 1.7.2. See
 // https://www.vaultproject.io/api-docs/system/capabilities-self.
-func (c *client) capabilities(paths []string) (pathCapabilities, error) {
+func (c *client) capabilities(ctx context.Context, paths []string) (pathCapabilities, error) {
 	const op = "vault.(client).capabilities"
 	if len(paths) == 0 {
-		return nil, errors.NewDeprecated(errors.InvalidParameter, op, "empty paths")
+		return nil, errors.New(ctx, errors.InvalidParameter, op, "empty paths")
+	}
+	if err := c.prepareRequest(ctx); err != nil {
+		return nil, errors.Wrap(ctx, err, op)
 	}
 	body := map[string]string{
 		"paths": strings.Join(paths, ","),
@@ -217,8 +588,6 @@ func (c *client) capabilities(paths []string) (pathCapabilities, error) {
 		return nil, err
 	}
 
-	ctx, cancelFunc := context.WithCancel(context.Background())
-	defer cancelFunc()
 	resp, err := c.cl.RawRequestWithContext(ctx, r)
 	if err != nil {
 		return nil, err
@@ -230,7 +599,7 @@ func (c *client) capabilities(paths []string) (pathCapabilities, error) {
 		return nil, err
 	}
 	if secret == nil || secret.Data == nil {
-		return nil, errors.NewDeprecated(errors.Unknown, op, "data from Vault is empty")
+		return nil, errors.New(ctx, errors.Unknown, op, "data from Vault is empty")
 	}
 
 	var res map[string][]string
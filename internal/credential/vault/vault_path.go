@@ -0,0 +1,70 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/boundary/internal/errors"
+)
+
+// vaultPathData contains the session values available to a templated
+// vault path.
+type vaultPathData struct {
+	// Username is the name of the user of the session or the user's public
+	// id if the user does not have a name.
+	Username string
+	// Target is the name of the target of the session.
+	Target string
+}
+
+// validateVaultPath reports whether p is a valid templated vault path. An
+// errors.InvalidParameter error is returned if p cannot be parsed or
+// references a variable other than {{.Username}} and {{.Target}}.
+func validateVaultPath(ctx context.Context, p string) error {
+	const op = "vault.validateVaultPath"
+	if _, err := renderVaultPath(ctx, p, vaultPathData{Username: "username", Target: "target"}); err != nil {
+		return errors.Wrap(ctx, err, op)
+	}
+	return nil
+}
+
+// renderVaultPath renders the templated vault path p with the values in
+// data. The values are substituted into p as is. An errors.InvalidParameter
+// error is returned if a value cannot be used as a single segment of a
+// vault path.
+func renderVaultPath(ctx context.Context, p string, data vaultPathData) (string, error) {
+	const op = "vault.renderVaultPath"
+	tmpl, err := template.New("vault_path").Option("missingkey=error").Parse(p)
+	if err != nil {
+		return "", errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("invalid templated vault path: %s", err))
+	}
+	if err := validatePathSegment(ctx, "username", data.Username); err != nil {
+		return "", errors.Wrap(ctx, err, op)
+	}
+	if err := validatePathSegment(ctx, "target", data.Target); err != nil {
+		return "", errors.Wrap(ctx, err, op)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("unable to render templated vault path: %s", err))
+	}
+	return b.String(), nil
+}
+
+// validatePathSegment reports whether s, the value of the variable name,
+// can be placed in a single segment of a vault path. An
+// errors.InvalidParameter error is returned if s contains a "/" or is one
+// of the dot segments "." and "..", which would change the path being
+// requested.
+func validatePathSegment(ctx context.Context, name, s string) error {
+	const op = "vault.validatePathSegment"
+	switch {
+	case s == ".", s == "..":
+		return errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("%s cannot be a dot segment: %q", name, s))
+	case strings.Contains(s, "/"):
+		return errors.New(ctx, errors.InvalidParameter, op, fmt.Sprintf("%s cannot contain a slash: %q", name, s))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_renderVaultPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		in      string
+		data    vaultPathData
+		want    string
+		wantErr bool
+		// validInput is set if the templated vault path is valid and only
+		// the data cannot be rendered into it
+		validInput bool
+	}{
+		{
+			name: "no-variables",
+			in:   "/database/creds/opened",
+			data: vaultPathData{Username: "alice", Target: "db"},
+			want: "/database/creds/opened",
+		},
+		{
+			name: "username-and-target",
+			in:   "/database/creds/{{.Target}}-{{.Username}}",
+			data: vaultPathData{Username: "alice", Target: "db"},
+			want: "/database/creds/db-alice",
+		},
+		{
+			name: "values-not-escaped",
+			in:   "/database/creds/{{.Target}}/{{.Username}}",
+			data: vaultPathData{Username: "alice smith@example.com", Target: "db..prod"},
+			want: "/database/creds/db..prod/alice smith@example.com",
+		},
+		{
+			name:       "slash-in-username",
+			in:         "/database/creds/{{.Target}}/{{.Username}}",
+			data:       vaultPathData{Username: "a/../b", Target: "db"},
+			wantErr:    true,
+			validInput: true,
+		},
+		{
+			name:       "dot-segment-target",
+			in:         "/database/creds/{{.Target}}/{{.Username}}",
+			data:       vaultPathData{Username: "alice", Target: ".."},
+			wantErr:    true,
+			validInput: true,
+		},
+		{
+			name:       "single-dot-username",
+			in:         "/database/creds/{{.Target}}/{{.Username}}",
+			data:       vaultPathData{Username: ".", Target: "db"},
+			wantErr:    true,
+			validInput: true,
+		},
+		{
+			name:    "unknown-variable",
+			in:      "/database/creds/{{.Role}}",
+			data:    vaultPathData{Username: "alice", Target: "db"},
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			in:      "/database/creds/{{.Username",
+			data:    vaultPathData{Username: "alice", Target: "db"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			ctx := context.Background()
+			got, err := renderVaultPath(ctx, tt.in, tt.data)
+			if tt.wantErr {
+				assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+				assert.Empty(got)
+				if tt.validInput {
+					assert.NoError(validateVaultPath(ctx, tt.in))
+				} else {
+					assert.Error(validateVaultPath(ctx, tt.in))
+				}
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, got)
+			assert.NoError(validateVaultPath(ctx, tt.in))
+		})
+	}
+}
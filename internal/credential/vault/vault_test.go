@@ -1,9 +1,18 @@
 package vault
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	stderrors "errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"path"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -18,7 +27,7 @@ func Test_newClient(t *testing.T) {
 	t.Run("nilConfig", func(t *testing.T) {
 		assert := assert.New(t)
 		var c *clientConfig
-		client, err := newClient(c)
+		client, err := newClient(context.Background(), c)
 		assert.Error(err)
 		assert.Nil(client)
 	})
@@ -36,7 +45,7 @@ func TestClient_RenewToken(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	client := v.clientUsingToken(t, token)
-	renewedToken, err := client.renewToken()
+	renewedToken, err := client.renewToken(context.Background())
 	require.NoError(t, err)
 	assert.NotNil(renewedToken)
 
@@ -69,7 +78,7 @@ func TestClient_LookupToken(t *testing.T) {
 	secretLookup := v.LookupToken(t, token)
 
 	client := v.clientUsingToken(t, token)
-	tokenLookup, err := client.lookupToken()
+	tokenLookup, err := client.lookupToken(context.Background())
 	assert.NoError(err)
 	require.NotNil(tokenLookup)
 
@@ -85,14 +94,14 @@ func TestClient_RevokeToken(t *testing.T) {
 	_, token := v.CreateToken(t)
 
 	client := v.clientUsingToken(t, token)
-	tokenLookup, err := client.lookupToken()
+	tokenLookup, err := client.lookupToken(context.Background())
 	assert.NoError(err)
 	assert.NotNil(tokenLookup)
 
-	require.NoError(client.revokeToken())
+	require.NoError(client.revokeToken(context.Background()))
 
 	// An attempt to lookup should now fail with a 403
-	tokenLookup, err = client.lookupToken()
+	tokenLookup, err = client.lookupToken(context.Background())
 	require.Error(err)
 	assert.Nil(tokenLookup)
 
@@ -110,7 +119,7 @@ func TestClient_Get(t *testing.T) {
 
 	client := v.client(t)
 
-	cred, err := client.get(path.Join("database", "creds", "opened"))
+	cred, err := client.get(context.Background(), path.Join("database", "creds", "opened"))
 	assert.NoError(err)
 	assert.NotNil(cred)
 }
@@ -124,19 +133,282 @@ func TestClient_Post(t *testing.T) {
 	t.Run("post-body", func(t *testing.T) {
 		assert := assert.New(t)
 		credData := []byte(`{"common_name":"boundary.com"}`)
-		cred, err := client.post(credPath, credData)
+		cred, err := client.post(context.Background(), credPath, credData, "")
 		assert.NoError(err)
 		assert.NotNil(cred)
 	})
 	t.Run("nil-body", func(t *testing.T) {
 		assert := assert.New(t)
-		cred, err := client.post(credPath, nil)
+		cred, err := client.post(context.Background(), credPath, nil, "")
 		assert.Error(err)
 		assert.Contains(err.Error(), "common_name field is required")
 		assert.Nil(cred)
 	})
 }
 
+func TestClient_PostContentType(t *testing.T) {
+	t.Parallel()
+
+	// a fake Vault server which echoes the method, Content-Type header,
+	// and body of each request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"method":       r.Method,
+				"content_type": r.Header.Get("Content-Type"),
+				"body":         string(body),
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := newClient(context.Background(), &clientConfig{
+		Addr:  srv.URL,
+		Token: TokenSecret("token"),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		data            []byte
+		contentType     string
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "default",
+			data:            []byte(`{"common_name":"boundary.com"}`),
+			wantContentType: ContentTypeJson,
+			wantBody:        `{"common_name":"boundary.com"}`,
+		},
+		{
+			name:            "default-empty-body",
+			wantContentType: ContentTypeJson,
+			wantBody:        `{}`,
+		},
+		{
+			name:            "json",
+			data:            []byte(`{"common_name":"boundary.com"}`),
+			contentType:     ContentTypeJson,
+			wantContentType: ContentTypeJson,
+			wantBody:        `{"common_name":"boundary.com"}`,
+		},
+		{
+			name:            "form",
+			data:            []byte(`common_name=boundary.com`),
+			contentType:     ContentTypeForm,
+			wantContentType: ContentTypeForm,
+			wantBody:        `common_name=boundary.com`,
+		},
+		{
+			name:            "form-empty-body",
+			contentType:     ContentTypeForm,
+			wantContentType: ContentTypeForm,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := c.post(context.Background(), "secret/data/foo", tt.data, tt.contentType)
+			require.NoError(err)
+			require.NotNil(got)
+			assert.Equal("PUT", got.Data["method"])
+			assert.Equal(tt.wantContentType, got.Data["content_type"])
+			assert.Equal(tt.wantBody, got.Data["body"])
+		})
+	}
+
+	t.Run("patch", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := c.patch(context.Background(), "secret/data/foo", []byte(`{"ttl":60}`))
+		require.NoError(err)
+		require.NotNil(got)
+		assert.Equal("PATCH", got.Data["method"])
+		assert.Equal("application/merge-patch+json", got.Data["content_type"])
+		assert.Equal(`{"ttl":60}`, got.Data["body"])
+	})
+}
+
+func Test_readTokenFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	t.Run("missing-file", func(t *testing.T) {
+		assert := assert.New(t)
+		got, err := readTokenFile(context.Background(), dir, filepath.Join(dir, "missing"))
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+	})
+	t.Run("empty-file", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		path := filepath.Join(dir, "empty")
+		require.NoError(ioutil.WriteFile(path, []byte(" \n"), 0o600))
+		got, err := readTokenFile(context.Background(), dir, path)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+	})
+	t.Run("valid", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		path := filepath.Join(dir, "token")
+		require.NoError(ioutil.WriteFile(path, []byte("s.token\n"), 0o600))
+		got, err := readTokenFile(context.Background(), dir, path)
+		require.NoError(err)
+		assert.Equal(TokenSecret("s.token"), got)
+	})
+	t.Run("outside-directory", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(ioutil.WriteFile(path, []byte("s.token\n"), 0o600))
+		got, err := readTokenFile(context.Background(), dir, path)
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(got)
+	})
+}
+
+func Test_checkTokenFilePath(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	dir := t.TempDir()
+	outside := t.TempDir()
+
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(ioutil.WriteFile(tokenFile, []byte("s.token\n"), 0o600))
+	require.NoError(os.Mkdir(filepath.Join(dir, "agent"), 0o700))
+	nestedFile := filepath.Join(dir, "agent", "token")
+	require.NoError(ioutil.WriteFile(nestedFile, []byte("s.token\n"), 0o600))
+	outsideFile := filepath.Join(outside, "token")
+	require.NoError(ioutil.WriteFile(outsideFile, []byte("s.token\n"), 0o600))
+	linkFile := filepath.Join(dir, "link")
+	require.NoError(os.Symlink(outsideFile, linkFile))
+
+	tests := []struct {
+		name    string
+		dir     string
+		path    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			dir:  dir,
+			path: tokenFile,
+		},
+		{
+			name: "valid-subdirectory",
+			dir:  dir,
+			path: nestedFile,
+		},
+		{
+			name:    "no-directory",
+			path:    tokenFile,
+			wantErr: true,
+		},
+		{
+			name:    "relative-path",
+			dir:     dir,
+			path:    "token",
+			wantErr: true,
+		},
+		{
+			name:    "outside-directory",
+			dir:     dir,
+			path:    outsideFile,
+			wantErr: true,
+		},
+		{
+			name:    "link-outside-directory",
+			dir:     dir,
+			path:    linkFile,
+			wantErr: true,
+		},
+		{
+			name:    "directory",
+			dir:     dir,
+			path:    dir,
+			wantErr: true,
+		},
+		{
+			name:    "missing-file",
+			dir:     dir,
+			path:    filepath.Join(dir, "missing"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got, err := checkTokenFilePath(context.Background(), tt.dir, tt.path)
+			if tt.wantErr {
+				assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+				assert.Empty(got)
+				return
+			}
+			assert.NoError(err)
+			want, err := filepath.EvalSymlinks(tt.path)
+			assert.NoError(err)
+			assert.Equal(want, got)
+		})
+	}
+}
+
+func TestClient_TokenFile(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+
+	// a fake Vault server which echoes the token of each request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"token": r.Header.Get("X-Vault-Token"),
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	require.NoError(ioutil.WriteFile(path, []byte("token-1\n"), 0o600))
+
+	c, err := newClient(context.Background(), &clientConfig{
+		Addr:         srv.URL,
+		TokenFile:    path,
+		TokenFileDir: dir,
+	})
+	require.NoError(err)
+	require.NotNil(c)
+	assert.Equal(TokenSecret("token-1"), c.token)
+
+	got, err := c.get(context.Background(), "secret/data/foo")
+	require.NoError(err)
+	assert.Equal("token-1", got.Data["token"])
+
+	// the agent rotates the token
+	require.NoError(ioutil.WriteFile(path, []byte("token-2\n"), 0o600))
+	got, err = c.get(context.Background(), "secret/data/foo")
+	require.NoError(err)
+	assert.Equal("token-2", got.Data["token"])
+
+	// the file is replaced by a link to a file outside of the token file
+	// directory, which must not be read
+	outside := filepath.Join(t.TempDir(), "secret")
+	require.NoError(ioutil.WriteFile(outside, []byte("not-a-token\n"), 0o600))
+	require.NoError(os.Remove(path))
+	require.NoError(os.Symlink(outside, path))
+	got, err = c.get(context.Background(), "secret/data/foo")
+	assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+	assert.Nil(got)
+
+	require.NoError(os.Remove(path))
+	got, err = c.get(context.Background(), "secret/data/foo")
+	assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err: %q got: %q", errors.InvalidParameter, err)
+	assert.Nil(got)
+}
+
 func TestClient_RenewLease(t *testing.T) {
 	t.Parallel()
 	assert, require := assert.New(t), require.New(t)
@@ -147,7 +419,7 @@ func TestClient_RenewLease(t *testing.T) {
 	client := v.clientUsingToken(t, token)
 
 	// Create secret
-	cred, err := client.get(path.Join("database", "creds", "opened"))
+	cred, err := client.get(context.Background(), path.Join("database", "creds", "opened"))
 	assert.NoError(err)
 	require.NotNil(cred)
 
@@ -158,7 +430,7 @@ func TestClient_RenewLease(t *testing.T) {
 	// Verify lease has not been renewed
 	assert.Empty(leaseLookup.Data["last_renewal"])
 
-	renewedLease, err := client.renewLease(cred.LeaseID, time.Hour)
+	renewedLease, err := client.renewLease(context.Background(), cred.LeaseID, time.Hour)
 	assert.NoError(err)
 	require.NotNil(renewedLease)
 	assert.Equal(cred.LeaseID, renewedLease.LeaseID)
@@ -205,7 +477,7 @@ func TestClient_capabilities(t *testing.T) {
 			_, token := v.CreateToken(t, WithPolicies(tt.polices))
 			client := v.clientUsingToken(t, token)
 
-			have, err := client.capabilities(tt.require.paths())
+			have, err := client.capabilities(context.Background(), tt.require.paths())
 			assert.NoError(err)
 			got := have.missing(tt.require)
 			assert.Equalf(tt.wantMissing, got, "pathCapabilities: want: {%s} got: {%s}", tt.wantMissing, got)
@@ -222,7 +494,7 @@ func TestClient_revokeLease(t *testing.T) {
 	_, token := v.CreateToken(t, WithPolicies([]string{"boundary-controller", "database"}))
 	client := v.clientUsingToken(t, token)
 
-	cred, err := client.get(path.Join("database", "creds", "opened"))
+	cred, err := client.get(context.Background(), path.Join("database", "creds", "opened"))
 	assert.NoError(err)
 	require.NotNil(cred)
 
@@ -230,8 +502,274 @@ func TestClient_revokeLease(t *testing.T) {
 	assert.NoError(testDatabase.ValidateCredential(t, cred))
 
 	// revoke the database credentials
-	assert.NoError(client.revokeLease(cred.LeaseID))
+	assert.NoError(client.revokeLease(context.Background(), cred.LeaseID))
 
 	// verify the database credentials no longer work
 	assert.Error(testDatabase.ValidateCredential(t, cred))
 }
+
+func TestClient_TokenExpired(t *testing.T) {
+	t.Parallel()
+
+	// newFakeVault returns a client for a fake Vault server which responds
+	// with a 403 to every request except lookup-self requests when
+	// tokenValid is true.
+	newFakeVault := func(t *testing.T, tokenValid bool) *client {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if tokenValid && r.URL.Path == "/v1/auth/token/lookup-self" {
+				fmt.Fprint(w, `{"data":{"id":"token","renewable":true,"orphan":true,"period":3600}}`)
+				return
+			}
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+		}))
+		t.Cleanup(srv.Close)
+
+		c, err := newClient(context.Background(), &clientConfig{
+			Addr:  srv.URL,
+			Token: TokenSecret("token"),
+		})
+		require.NoError(t, err)
+		return c
+	}
+
+	tests := []struct {
+		name       string
+		tokenValid bool
+		call       func(c *client) error
+		wantCode   errors.Code
+	}{
+		{
+			name: "get-expired-token",
+			call: func(c *client) error {
+				_, err := c.get(context.Background(), "secret/data/foo")
+				return err
+			},
+			wantCode: errors.VaultTokenExpired,
+		},
+		{
+			name: "post-expired-token",
+			call: func(c *client) error {
+				_, err := c.post(context.Background(), "secret/data/foo", nil, "")
+				return err
+			},
+			wantCode: errors.VaultTokenExpired,
+		},
+		{
+			name:       "get-permission-denied",
+			tokenValid: true,
+			call: func(c *client) error {
+				_, err := c.get(context.Background(), "secret/data/foo")
+				return err
+			},
+			wantCode: errors.VaultCredentialRequest,
+		},
+		{
+			name:       "post-permission-denied",
+			tokenValid: true,
+			call: func(c *client) error {
+				_, err := c.post(context.Background(), "secret/data/foo", nil, "")
+				return err
+			},
+			wantCode: errors.VaultCredentialRequest,
+		},
+		{
+			name: "renew-expired-token",
+			call: func(c *client) error {
+				_, err := c.renewToken(context.Background())
+				return err
+			},
+			wantCode: errors.VaultTokenExpired,
+		},
+		{
+			name: "revoke-expired-token",
+			call: func(c *client) error {
+				return c.revokeToken(context.Background())
+			},
+			wantCode: errors.VaultTokenExpired,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeVault(t, tt.tokenValid)
+			err := tt.call(c)
+			require.Error(t, err)
+			assert.Truef(t, errors.Match(errors.T(tt.wantCode), err), "want err code: %q got: %q", tt.wantCode, err)
+		})
+	}
+}
+
+func TestClient_RequestTimeout(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+
+	// a fake Vault server which does not respond until the test is done
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(done) })
+
+	c, err := newClient(context.Background(), &clientConfig{
+		Addr:           srv.URL,
+		Token:          TokenSecret("token"),
+		ConnectTimeout: time.Second,
+		RequestTimeout: 100 * time.Millisecond,
+	})
+	require.NoError(err)
+
+	start := time.Now()
+	_, err = c.get(context.Background(), "secret/data/foo")
+	require.Error(err)
+	assert.Less(int64(time.Since(start)), int64(5*time.Second))
+	assert.Truef(stderrors.Is(err, context.DeadlineExceeded), "want wrapped %v, got: %v", context.DeadlineExceeded, err)
+	assert.Truef(errors.Match(errors.T(errors.VaultCredentialRequest), err), "want err code: %q got: %q", errors.VaultCredentialRequest, err)
+}
+
+func TestClient_TlsMinVersion(t *testing.T) {
+	t.Parallel()
+
+	// a fake Vault server which does not support TLS 1.3
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"initialized":true,"sealed":false,"standby":false}`)
+	}))
+	srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	tests := []struct {
+		name          string
+		tlsMinVersion string
+		wantErr       bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:          "tls12",
+			tlsMinVersion: TlsVersion12,
+		},
+		{
+			name:          "tls13",
+			tlsMinVersion: TlsVersion13,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			c, err := newClient(context.Background(), &clientConfig{
+				Addr:          srv.URL,
+				Token:         TokenSecret("token"),
+				CaCert:        caCert,
+				TlsMinVersion: tt.tlsMinVersion,
+			})
+			require.NoError(err)
+			err = c.ping(context.Background())
+			if tt.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert := assert.New(t)
+		c, err := newClient(context.Background(), &clientConfig{
+			Addr:          srv.URL,
+			Token:         TokenSecret("token"),
+			TlsMinVersion: "tls10",
+		})
+		assert.Truef(errors.Match(errors.T(errors.InvalidParameter), err), "want err code: %q got: %q", errors.InvalidParameter, err)
+		assert.Nil(c)
+	})
+}
+
+func TestClient_Unwrap(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+
+	const (
+		wrapToken = "wrapping-token"
+		secretKey = "s3cret"
+	)
+	// a fake Vault server which wraps the response to a read of
+	// secret/data and unwraps it when given the wrapping token
+	var gotWrapTtl string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/secret/data":
+			gotWrapTtl = r.Header.Get("X-Vault-Wrap-TTL")
+			if gotWrapTtl == "" {
+				fmt.Fprintf(w, `{"data":{"password":%q}}`, secretKey)
+				return
+			}
+			fmt.Fprintf(w, `{"wrap_info":{"token":%q,"ttl":300,"creation_path":"secret/data"}}`, wrapToken)
+		case "/v1/sys/wrapping/unwrap":
+			assert.Empty(r.Header.Get("X-Vault-Wrap-TTL"), "the unwrap request should not be wrapped")
+			var body struct {
+				Token string `json:"token"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Token != wrapToken {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"errors":["wrapping token is not valid or does not exist"]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"lease_id":"secret/data/1234","lease_duration":3600,"data":{"password":%q}}`, secretKey)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := newClient(context.Background(), &clientConfig{
+		Addr:    srv.URL,
+		Token:   TokenSecret("token"),
+		WrapTtl: 5 * time.Minute,
+	})
+	require.NoError(err)
+
+	wrapped, err := c.get(context.Background(), "secret/data")
+	require.NoError(err)
+	assert.Equal("5m0s", gotWrapTtl)
+	require.NotNil(wrapped.WrapInfo)
+	assert.Equal(wrapToken, wrapped.WrapInfo.Token)
+	assert.Empty(wrapped.Data)
+
+	got, err := c.unwrap(context.Background(), wrapped)
+	require.NoError(err)
+	assert.Nil(got.WrapInfo)
+	assert.Equal("secret/data/1234", got.LeaseID)
+	assert.Equal(secretKey, got.Data["password"])
+
+	// a response which is not wrapped is returned unchanged
+	c, err = newClient(context.Background(), &clientConfig{
+		Addr:  srv.URL,
+		Token: TokenSecret("token"),
+	})
+	require.NoError(err)
+	s, err := c.get(context.Background(), "secret/data")
+	require.NoError(err)
+	assert.Empty(gotWrapTtl)
+	got, err = c.unwrap(context.Background(), s)
+	require.NoError(err)
+	assert.Same(s, got)
+
+	// an invalid wrapping token
+	wrapped.WrapInfo.Token = "invalid"
+	got, err = c.unwrap(context.Background(), wrapped)
+	assert.Error(err)
+	assert.Nil(got)
+}
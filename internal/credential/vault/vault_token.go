@@ -86,6 +86,8 @@ func newToken(storeId string, token TokenSecret, accessor []byte, expiration tim
 			TokenHmac: hmac,
 			Token:     tokenCopy,
 			Status:    string(CurrentToken),
+			// A token must be renewable to be added to a credential store.
+			IsRenewable: true,
 		},
 	}
 	return t, nil
@@ -147,6 +149,7 @@ func (t *Token) insertQuery() (query string, queryValues []interface{}) {
 		sql.Named("5", t.Status),
 		sql.Named("6", "now()"),
 		sql.Named("7", exp),
+		sql.Named("8", t.IsRenewable),
 	}
 	return
 }
@@ -167,6 +170,7 @@ func (t *Token) updateExpirationQuery() (query string, queryValues []interface{}
 	exp := int(t.expiration.Round(time.Second).Seconds())
 	queryValues = []interface{}{
 		exp,
+		t.IsRenewable,
 		t.TokenHmac,
 	}
 	return
@@ -194,3 +198,41 @@ func (t *Token) renewalIn() time.Duration {
 	// Token renewal should be attempted half way to expiration
 	return t.expiration / 2
 }
+
+// TokenExpiry reports the lease of the current Vault token of a credential
+// store as it was last persisted by the repository. It is computed without
+// contacting Vault.
+type TokenExpiry struct {
+	// StoreId is the public id of the credential store which owns the
+	// token.
+	StoreId string
+
+	// LastRenewalTime is the time the token was last renewed in Vault.
+	LastRenewalTime time.Time
+
+	// ExpirationTime is the time the token expires in Vault unless it is
+	// renewed.
+	ExpirationTime time.Time
+
+	// Renewable reports whether the token can still be renewed. It is
+	// false if the token has expired or if Vault reported the token as not
+	// renewable when it was last renewed.
+	Renewable bool
+
+	// ExpiresIn is the duration until ExpirationTime. It is zero if the
+	// token has expired.
+	ExpiresIn time.Duration
+}
+
+func newTokenExpiry(storeId string, tk *Token, now time.Time) *TokenExpiry {
+	te := &TokenExpiry{
+		StoreId:         storeId,
+		LastRenewalTime: tk.GetLastRenewalTime().AsTime(),
+		ExpirationTime:  tk.GetExpirationTime().AsTime(),
+	}
+	if te.ExpirationTime.After(now) {
+		te.ExpiresIn = te.ExpirationTime.Sub(now)
+		te.Renewable = tk.GetIsRenewable()
+	}
+	return te
+}
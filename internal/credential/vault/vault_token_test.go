@@ -98,10 +98,11 @@ func TestToken_New(t *testing.T) {
 			},
 			want: &Token{
 				Token: &store.Token{
-					StoreId:   cs.PublicId,
-					Token:     []byte("token"),
-					TokenHmac: hmac([]byte("token"), []byte("accessor")),
-					Status:    string(CurrentToken),
+					StoreId:     cs.PublicId,
+					Token:       []byte("token"),
+					TokenHmac:   hmac([]byte("token"), []byte("accessor")),
+					Status:      string(CurrentToken),
+					IsRenewable: true,
 				},
 				expiration: 5 * time.Minute,
 			},
@@ -149,3 +150,56 @@ func subtract(t *testing.T, startTime, endTime *timestamp.Timestamp) time.Durati
 	}
 	return a.Sub(b)
 }
+
+func TestToken_newTokenExpiry(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		expiration    time.Time
+		renewable     bool
+		wantRenewable bool
+		wantExpiresIn time.Duration
+	}{
+		{
+			name:          "near-expiry",
+			expiration:    now.Add(30 * time.Second),
+			renewable:     true,
+			wantRenewable: true,
+			wantExpiresIn: 30 * time.Second,
+		},
+		{
+			name:          "freshly-renewed",
+			expiration:    now.Add(24 * time.Hour),
+			renewable:     true,
+			wantRenewable: true,
+			wantExpiresIn: 24 * time.Hour,
+		},
+		{
+			name:          "not-renewable",
+			expiration:    now.Add(24 * time.Hour),
+			wantExpiresIn: 24 * time.Hour,
+		},
+		{
+			name:       "expired",
+			expiration: now.Add(-time.Minute),
+			renewable:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			tk := allocToken()
+			tk.LastRenewalTime = timestamp.New(now.Add(-time.Hour))
+			tk.ExpirationTime = timestamp.New(tt.expiration)
+			tk.IsRenewable = tt.renewable
+
+			got := newTokenExpiry("csvlt_1234567890", tk, now)
+			assert.Equal("csvlt_1234567890", got.StoreId)
+			assert.True(got.LastRenewalTime.Equal(now.Add(-time.Hour)))
+			assert.True(got.ExpirationTime.Equal(tt.expiration))
+			assert.Equal(tt.wantRenewable, got.Renewable)
+			assert.Equal(tt.wantExpiresIn, got.ExpiresIn)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+begin;
+
+  create table credential_vault_scope_default (
+    scope_id wt_scope_id primary key
+      constraint iam_scope_fkey
+        references iam_scope (public_id)
+        on delete cascade
+        on update cascade,
+    create_time wt_timestamp,
+    update_time wt_timestamp,
+    namespace text not null
+      constraint namespace_must_not_be_empty
+        check(length(trim(namespace)) > 0)
+  );
+  comment on table credential_vault_scope_default is
+    'credential_vault_scope_default is a table where each row contains the default values '
+    'applied to vault credential stores created in a scope.';
+
+  create trigger update_time_column before update on credential_vault_scope_default
+    for each row execute procedure update_time_column();
+
+  create trigger default_create_time_column before insert on credential_vault_scope_default
+    for each row execute procedure default_create_time();
+
+  create trigger immutable_columns before update on credential_vault_scope_default
+    for each row execute procedure immutable_columns('scope_id', 'create_time');
+
+commit;
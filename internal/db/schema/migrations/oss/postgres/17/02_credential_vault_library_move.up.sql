@@ -0,0 +1,40 @@
+begin;
+
+  -- A vault credential library can be moved to a different vault credential
+  -- store. The store_id column is no longer immutable in either the
+  -- credential_library base table or the credential_vault_library subtype
+  -- table.
+  drop trigger immutable_columns on credential_library;
+  create trigger immutable_columns before update on credential_library
+    for each row execute procedure immutable_columns('public_id');
+
+  drop trigger immutable_columns on credential_vault_library;
+  create trigger immutable_columns before update on credential_vault_library
+    for each row execute procedure immutable_columns('public_id', 'create_time');
+
+  -- The foreign key from the subtype table to the base table is checked at
+  -- the end of the transaction so the store_id in the subtype table can be
+  -- updated before the store_id in the base table.
+  alter table credential_vault_library
+    alter constraint credential_library_fkey deferrable initially deferred;
+
+  -- update_credential_library_store_id is an after update trigger function
+  -- for subtypes of credential_library. It updates the store_id of the
+  -- base table row to match the store_id of the subtype row.
+  create function update_credential_library_store_id()
+    returns trigger
+  as $$
+  begin
+    update credential_library
+       set store_id  = new.store_id
+     where public_id = new.public_id;
+    return null;
+  end;
+  $$ language plpgsql;
+
+  create trigger update_credential_library_store_id after update of store_id on credential_vault_library
+    for each row
+    when (new.store_id is distinct from old.store_id)
+    execute procedure update_credential_library_store_id();
+
+commit;
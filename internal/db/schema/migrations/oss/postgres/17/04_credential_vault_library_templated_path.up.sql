@@ -0,0 +1,38 @@
+begin;
+
+  alter table credential_vault_library
+    add column templated_vault_path boolean default false not null;
+
+  -- replaces view from 17/03_credential_vault_library_json_pointer.up.sql
+  -- adds templated_vault_path column to the end of the view
+  create or replace view credential_vault_library_private as
+     select library.public_id         as public_id,
+            library.store_id          as store_id,
+            library.name              as name,
+            library.description       as description,
+            library.create_time       as create_time,
+            library.update_time       as update_time,
+            library.version           as version,
+            library.vault_path        as vault_path,
+            library.http_method       as http_method,
+            library.http_request_body as http_request_body,
+            store.scope_id            as scope_id,
+            store.vault_address       as vault_address,
+            store.namespace           as namespace,
+            store.ca_cert             as ca_cert,
+            store.tls_server_name     as tls_server_name,
+            store.tls_skip_verify     as tls_skip_verify,
+            store.token_hmac          as token_hmac,
+            store.ct_token            as ct_token, -- encrypted
+            store.token_key_id        as token_key_id,
+            store.client_cert         as client_cert,
+            store.ct_client_key       as ct_client_key, -- encrypted
+            store.client_key_id       as client_key_id,
+            library.credential_json_pointer as credential_json_pointer,
+            library.templated_vault_path    as templated_vault_path
+       from credential_vault_library library
+       join credential_vault_store_private store
+         on library.store_id = store.public_id
+        and store.token_status = 'current';
+
+commit;
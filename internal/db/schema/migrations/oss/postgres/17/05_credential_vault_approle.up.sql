@@ -0,0 +1,117 @@
+begin;
+
+  create table credential_vault_approle (
+    store_id wt_public_id primary key
+      constraint credential_vault_store_fkey
+        references credential_vault_store (public_id)
+        on delete cascade
+        on update cascade,
+    role_id text not null
+      constraint role_id_must_not_be_empty
+        check(length(trim(role_id)) > 0),
+    secret_id bytea not null -- encrypted
+      constraint secret_id_must_not_be_empty
+        check(length(secret_id) > 0),
+    secret_id_hmac bytea not null
+      constraint secret_id_hmac_must_not_be_empty
+        check(length(secret_id_hmac) > 0),
+    key_id text not null
+      constraint kms_database_key_version_fkey
+        references kms_database_key_version (private_id)
+        on delete restrict
+        on update cascade
+  );
+  comment on table credential_vault_approle is
+    'credential_vault_approle is a table where each row contains the Vault AppRole a credential_vault_store uses to log in to Vault and obtain its token. '
+    'A credential_vault_store can have 0 or 1 AppRoles.';
+
+  create trigger immutable_columns before update on credential_vault_approle
+    for each row execute procedure immutable_columns('store_id');
+
+  -- replaces view from 10/04_vault_credential.up.sql
+  -- adds the approle columns to the end of the view
+     create or replace view credential_vault_store_private as
+     with
+     active_tokens as (
+        select token_hmac,
+               token, -- encrypted
+               store_id,
+               create_time,
+               update_time,
+               last_renewal_time,
+               expiration_time,
+               -- renewal time is the midpoint between the last renewal time and the expiration time
+               last_renewal_time + (expiration_time - last_renewal_time) / 2 as renewal_time,
+               key_id,
+               status
+          from credential_vault_token
+         where status in ('current', 'maintaining', 'revoke')
+     )
+     select store.public_id           as public_id,
+            store.scope_id            as scope_id,
+            store.name                as name,
+            store.description         as description,
+            store.create_time         as create_time,
+            store.update_time         as update_time,
+            store.delete_time         as delete_time,
+            store.version             as version,
+            store.vault_address       as vault_address,
+            store.namespace           as namespace,
+            store.ca_cert             as ca_cert,
+            store.tls_server_name     as tls_server_name,
+            store.tls_skip_verify     as tls_skip_verify,
+            store.public_id           as store_id,
+            token.token_hmac          as token_hmac,
+            token.token               as ct_token, -- encrypted
+            token.create_time         as token_create_time,
+            token.update_time         as token_update_time,
+            token.last_renewal_time   as token_last_renewal_time,
+            token.expiration_time     as token_expiration_time,
+            token.renewal_time        as token_renewal_time,
+            token.key_id              as token_key_id,
+            token.status              as token_status,
+            cert.certificate          as client_cert,
+            cert.certificate_key      as ct_client_key, -- encrypted
+            cert.certificate_key_hmac as client_cert_key_hmac,
+            cert.key_id               as client_key_id,
+            approle.role_id           as approle_role_id,
+            approle.secret_id         as ct_approle_secret_id, -- encrypted
+            approle.secret_id_hmac    as approle_secret_id_hmac,
+            approle.key_id            as approle_key_id
+       from credential_vault_store store
+  left join active_tokens token
+         on store.public_id = token.store_id
+  left join credential_vault_client_certificate cert
+         on store.public_id = cert.store_id
+  left join credential_vault_approle approle
+         on store.public_id = approle.store_id;
+
+  -- replaces view from 10/04_vault_credential.up.sql
+  -- adds the approle columns to the end of the view
+     create or replace view credential_vault_store_public as
+     select public_id,
+            scope_id,
+            name,
+            description,
+            create_time,
+            update_time,
+            version,
+            vault_address,
+            namespace,
+            ca_cert,
+            tls_server_name,
+            tls_skip_verify,
+            token_hmac,
+            token_create_time,
+            token_update_time,
+            token_last_renewal_time,
+            token_expiration_time,
+            client_cert,
+            client_cert_key_hmac,
+            approle_role_id,
+            approle_secret_id_hmac
+       from credential_vault_store_private
+      where token_status = 'current'
+        and delete_time is null;
+
+commit;
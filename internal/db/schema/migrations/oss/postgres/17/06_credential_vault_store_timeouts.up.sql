@@ -0,0 +1,185 @@
+begin;
+
+  alter table credential_vault_store
+    add column connect_timeout_seconds integer
+      constraint connect_timeout_seconds_must_be_positive
+        check(connect_timeout_seconds > 0),
+    add column request_timeout_seconds integer
+      constraint request_timeout_seconds_must_be_positive
+        check(request_timeout_seconds > 0);
+  comment on column credential_vault_store.connect_timeout_seconds is
+    'connect_timeout_seconds is the maximum number of seconds to wait for a connection to Vault. '
+    'If null, the Vault client default is used.';
+  comment on column credential_vault_store.request_timeout_seconds is
+    'request_timeout_seconds is the maximum number of seconds to wait for a request to Vault to complete. '
+    'If null, the Vault client default is used.';
+
+  -- replaces view from 17/05_credential_vault_approle.up.sql
+  -- adds the timeout columns to the end of the view
+     create or replace view credential_vault_store_private as
+     with
+     active_tokens as (
+        select token_hmac,
+               token, -- encrypted
+               store_id,
+               create_time,
+               update_time,
+               last_renewal_time,
+               expiration_time,
+               -- renewal time is the midpoint between the last renewal time and the expiration time
+               last_renewal_time + (expiration_time - last_renewal_time) / 2 as renewal_time,
+               key_id,
+               status
+          from credential_vault_token
+         where status in ('current', 'maintaining', 'revoke')
+     )
+     select store.public_id           as public_id,
+            store.scope_id            as scope_id,
+            store.name                as name,
+            store.description         as description,
+            store.create_time         as create_time,
+            store.update_time         as update_time,
+            store.delete_time         as delete_time,
+            store.version             as version,
+            store.vault_address       as vault_address,
+            store.namespace           as namespace,
+            store.ca_cert             as ca_cert,
+            store.tls_server_name     as tls_server_name,
+            store.tls_skip_verify     as tls_skip_verify,
+            store.public_id           as store_id,
+            token.token_hmac          as token_hmac,
+            token.token               as ct_token, -- encrypted
+            token.create_time         as token_create_time,
+            token.update_time         as token_update_time,
+            token.last_renewal_time   as token_last_renewal_time,
+            token.expiration_time     as token_expiration_time,
+            token.renewal_time        as token_renewal_time,
+            token.key_id              as token_key_id,
+            token.status              as token_status,
+            cert.certificate          as client_cert,
+            cert.certificate_key      as ct_client_key, -- encrypted
+            cert.certificate_key_hmac as client_cert_key_hmac,
+            cert.key_id               as client_key_id,
+            approle.role_id           as approle_role_id,
+            approle.secret_id         as ct_approle_secret_id, -- encrypted
+            approle.secret_id_hmac    as approle_secret_id_hmac,
+            approle.key_id            as approle_key_id,
+            store.connect_timeout_seconds as connect_timeout_seconds,
+            store.request_timeout_seconds as request_timeout_seconds
+       from credential_vault_store store
+  left join active_tokens token
+         on store.public_id = token.store_id
+  left join credential_vault_client_certificate cert
+         on store.public_id = cert.store_id
+  left join credential_vault_approle approle
+         on store.public_id = approle.store_id;
+
+  -- replaces view from 17/05_credential_vault_approle.up.sql
+  -- adds the timeout columns to the end of the view
+     create or replace view credential_vault_store_public as
+     select public_id,
+            scope_id,
+            name,
+            description,
+            create_time,
+            update_time,
+            version,
+            vault_address,
+            namespace,
+            ca_cert,
+            tls_server_name,
+            tls_skip_verify,
+            token_hmac,
+            token_create_time,
+            token_update_time,
+            token_last_renewal_time,
+            token_expiration_time,
+            client_cert,
+            client_cert_key_hmac,
+            approle_role_id,
+            approle_secret_id_hmac,
+            connect_timeout_seconds,
+            request_timeout_seconds
+       from credential_vault_store_private
+      where token_status = 'current'
+        and delete_time is null;
+
+  -- replaces view from 17/04_credential_vault_library_templated_path.up.sql
+  -- adds the timeout columns to the end of the view
+  create or replace view credential_vault_library_private as
+     select library.public_id         as public_id,
+            library.store_id          as store_id,
+            library.name              as name,
+            library.description       as description,
+            library.create_time       as create_time,
+            library.update_time       as update_time,
+            library.version           as version,
+            library.vault_path        as vault_path,
+            library.http_method       as http_method,
+            library.http_request_body as http_request_body,
+            store.scope_id            as scope_id,
+            store.vault_address       as vault_address,
+            store.namespace           as namespace,
+            store.ca_cert             as ca_cert,
+            store.tls_server_name     as tls_server_name,
+            store.tls_skip_verify     as tls_skip_verify,
+            store.token_hmac          as token_hmac,
+            store.ct_token            as ct_token, -- encrypted
+            store.token_key_id        as token_key_id,
+            store.client_cert         as client_cert,
+            store.ct_client_key       as ct_client_key, -- encrypted
+            store.client_key_id       as client_key_id,
+            library.credential_json_pointer as credential_json_pointer,
+            library.templated_vault_path    as templated_vault_path,
+            store.connect_timeout_seconds   as connect_timeout_seconds,
+            store.request_timeout_seconds   as request_timeout_seconds
+       from credential_vault_library library
+       join credential_vault_store_private store
+         on library.store_id = store.public_id
+        and store.token_status = 'current';
+
+  -- replaces view from 10/04_vault_credential.up.sql
+  -- adds the timeout columns to the end of the view
+     create or replace view credential_vault_credential_private as
+     select credential.public_id         as public_id,
+            credential.library_id        as library_id,
+            credential.session_id        as session_id,
+            credential.create_time       as create_time,
+            credential.update_time       as update_time,
+            credential.version           as version,
+            credential.external_id       as external_id,
+            credential.last_renewal_time as last_renewal_time,
+            credential.expiration_time   as expiration_time,
+            credential.is_renewable      as is_renewable,
+            credential.status            as status,
+            credential.last_renewal_time + (credential.expiration_time - credential.last_renewal_time) / 2 as renewal_time,
+            token.token_hmac             as token_hmac,
+            token.token                  as ct_token, -- encrypted
+            token.create_time            as token_create_time,
+            token.update_time            as token_update_time,
+            token.last_renewal_time      as token_last_renewal_time,
+            token.expiration_time        as token_expiration_time,
+            token.key_id                 as token_key_id,
+            token.status                 as token_status,
+            store.scope_id               as scope_id,
+            store.vault_address          as vault_address,
+            store.namespace              as namespace,
+            store.ca_cert                as ca_cert,
+            store.tls_server_name        as tls_server_name,
+            store.tls_skip_verify        as tls_skip_verify,
+            cert.certificate             as client_cert,
+            cert.certificate_key         as ct_client_key, -- encrypted
+            cert.certificate_key_hmac    as client_cert_key_hmac,
+            cert.key_id                  as client_key_id,
+            store.connect_timeout_seconds as connect_timeout_seconds,
+            store.request_timeout_seconds as request_timeout_seconds
+       from credential_vault_credential credential
+       join credential_vault_token token
+         on credential.token_hmac = token.token_hmac
+       join credential_vault_store store
+         on token.store_id = store.public_id
+  left join credential_vault_client_certificate cert
+         on store.public_id = cert.store_id
+      where credential.expiration_time != 'infinity'::date;
+
+commit;
@@ -0,0 +1,50 @@
+begin;
+
+  alter table credential_vault_library
+    add column credential_mappings bytea
+      constraint credential_mappings_must_not_be_empty
+        check(length(credential_mappings) > 0),
+    add constraint credential_mappings_not_allowed_with_credential_json_pointer
+      check(
+        credential_mappings is null
+        or
+        credential_json_pointer is null
+      );
+
+  -- replaces view from 17/07_credential_vault_store_rate_limit.up.sql
+  -- adds the credential_mappings column to the end of the view
+  create or replace view credential_vault_library_private as
+     select library.public_id         as public_id,
+            library.store_id          as store_id,
+            library.name              as name,
+            library.description       as description,
+            library.create_time       as create_time,
+            library.update_time       as update_time,
+            library.version           as version,
+            library.vault_path        as vault_path,
+            library.http_method       as http_method,
+            library.http_request_body as http_request_body,
+            store.scope_id            as scope_id,
+            store.vault_address       as vault_address,
+            store.namespace           as namespace,
+            store.ca_cert             as ca_cert,
+            store.tls_server_name     as tls_server_name,
+            store.tls_skip_verify     as tls_skip_verify,
+            store.token_hmac          as token_hmac,
+            store.ct_token            as ct_token, -- encrypted
+            store.token_key_id        as token_key_id,
+            store.client_cert         as client_cert,
+            store.ct_client_key       as ct_client_key, -- encrypted
+            store.client_key_id       as client_key_id,
+            library.credential_json_pointer as credential_json_pointer,
+            library.templated_vault_path    as templated_vault_path,
+            store.connect_timeout_seconds   as connect_timeout_seconds,
+            store.request_timeout_seconds   as request_timeout_seconds,
+            store.requests_per_second       as requests_per_second,
+            library.credential_mappings     as credential_mappings
+       from credential_vault_library library
+       join credential_vault_store_private store
+         on library.store_id = store.public_id
+        and store.token_status = 'current';
+
+commit;
@@ -0,0 +1,42 @@
+begin;
+
+  -- replaces the check constraint from 10/04_vault_credential.up.sql
+  -- adds the PUT and PATCH methods
+  alter table credential_vault_http_method_enm
+    drop constraint only_predefined_http_methods_allowed;
+  alter table credential_vault_http_method_enm
+    add constraint only_predefined_http_methods_allowed
+      check (
+        name in (
+          'GET',
+          'POST',
+          'PUT',
+          'PATCH'
+        )
+      );
+  comment on table credential_vault_http_method_enm is
+    'credential_vault_http_method_enm is an enumeration table for the http method used by a vault library when communicating with vault. '
+    'It contains rows for representing the HTTP GET, POST, PUT, and PATCH methods.';
+
+  insert into credential_vault_http_method_enm (name)
+  values
+    ('PUT'),
+    ('PATCH');
+
+  -- replaces the check constraint from 10/04_vault_credential.up.sql
+  -- a request body is allowed with every method except GET
+  alter table credential_vault_library
+    drop constraint http_request_body_only_allowed_with_post_method;
+  alter table credential_vault_library
+    add constraint http_request_body_only_allowed_with_body_method
+      check(
+        http_request_body is null
+        or
+        (
+          http_method in ('POST', 'PUT', 'PATCH')
+          and
+          length(http_request_body) > 0
+        )
+      );
+
+commit;
@@ -0,0 +1,78 @@
+begin;
+
+  alter table credential_vault_library
+    add column is_template boolean not null default false;
+  comment on column credential_vault_library.is_template is
+    'is_template is true if the library is a template for new credential libraries. '
+    'A template is never used to issue credentials and cannot be added to a target.';
+
+  create trigger immutable_is_template before update on credential_vault_library
+    for each row execute procedure immutable_columns('is_template');
+
+  -- target_credential_library_not_template is a before insert trigger for the
+  -- target_credential_library table that prevents a credential library
+  -- template from being added to a target.
+  create function target_credential_library_not_template()
+    returns trigger
+  as $$
+  begin
+    perform from credential_vault_library
+      where public_id = new.credential_library_id
+        and is_template;
+    if found then
+      raise exception 'credential library is a template: %', new.credential_library_id using
+        errcode = '23514',
+        schema = tg_table_schema,
+        table = tg_table_name,
+        column = 'credential_library_id';
+    end if;
+    return new;
+  end;
+  $$ language plpgsql;
+
+  create trigger target_credential_library_not_template before insert on target_credential_library
+    for each row execute procedure target_credential_library_not_template();
+
+  -- replaces view from 17/14_credential_vault_store_token_file.up.sql
+  -- excludes templates, which are never used to issue credentials
+  create or replace view credential_vault_library_private as
+     select library.public_id         as public_id,
+            library.store_id          as store_id,
+            library.name              as name,
+            library.description       as description,
+            library.create_time       as create_time,
+            library.update_time       as update_time,
+            library.version           as version,
+            library.vault_path        as vault_path,
+            library.http_method       as http_method,
+            library.http_request_body as http_request_body,
+            store.scope_id            as scope_id,
+            store.vault_address       as vault_address,
+            coalesce(library.namespace, store.namespace)
+                                      as namespace,
+            store.ca_cert             as ca_cert,
+            store.tls_server_name     as tls_server_name,
+            store.tls_skip_verify     as tls_skip_verify,
+            store.token_hmac          as token_hmac,
+            store.ct_token            as ct_token, -- encrypted
+            store.token_key_id        as token_key_id,
+            store.client_cert         as client_cert,
+            store.ct_client_key       as ct_client_key, -- encrypted
+            store.client_key_id       as client_key_id,
+            library.credential_json_pointer as credential_json_pointer,
+            library.templated_vault_path    as templated_vault_path,
+            store.connect_timeout_seconds   as connect_timeout_seconds,
+            store.request_timeout_seconds   as request_timeout_seconds,
+            store.requests_per_second       as requests_per_second,
+            library.credential_mappings     as credential_mappings,
+            store.tls_min_version           as tls_min_version,
+            library.wrap_ttl_seconds        as wrap_ttl_seconds,
+            library.content_type            as content_type,
+            store.token_file_path           as token_file_path
+       from credential_vault_library library
+       join credential_vault_store_private store
+         on library.store_id = store.public_id
+        and store.token_status = 'current'
+      where not library.is_template;
+
+commit;
@@ -0,0 +1,122 @@
+begin;
+
+  alter table credential_vault_store
+    add column auth_method text not null default 'token'
+      constraint auth_method_must_be_supported
+        check(auth_method in ('token', 'approle', 'token-file'));
+  comment on column credential_vault_store.auth_method is
+    'auth_method is the method the store uses to obtain its Vault token. '
+    'A token store is given its token, an approle store logs in to Vault with its AppRole, '
+    'and a token-file store reads its token from token_file_path.';
+
+  update credential_vault_store
+     set auth_method = 'approle'
+   where public_id in (select store_id from credential_vault_approle);
+
+  update credential_vault_store
+     set auth_method = 'token-file'
+   where token_file_path is not null;
+
+  alter table credential_vault_store
+    add constraint token_file_path_requires_token_file_auth_method
+      check((auth_method = 'token-file') = (token_file_path is not null));
+
+  -- replaces view from 17/14_credential_vault_store_token_file.up.sql
+  -- adds the auth_method column to the end of the view
+     create or replace view credential_vault_store_private as
+     with
+     active_tokens as (
+        select token_hmac,
+               token, -- encrypted
+               store_id,
+               create_time,
+               update_time,
+               last_renewal_time,
+               expiration_time,
+               -- renewal time is the midpoint between the last renewal time and the expiration time
+               last_renewal_time + (expiration_time - last_renewal_time) / 2 as renewal_time,
+               key_id,
+               status
+          from credential_vault_token
+         where status in ('current', 'maintaining', 'revoke')
+     )
+     select store.public_id           as public_id,
+            store.scope_id            as scope_id,
+            store.name                as name,
+            store.description         as description,
+            store.create_time         as create_time,
+            store.update_time         as update_time,
+            store.delete_time         as delete_time,
+            store.version             as version,
+            store.vault_address       as vault_address,
+            store.namespace           as namespace,
+            store.ca_cert             as ca_cert,
+            store.tls_server_name     as tls_server_name,
+            store.tls_skip_verify     as tls_skip_verify,
+            store.public_id           as store_id,
+            token.token_hmac          as token_hmac,
+            token.token               as ct_token, -- encrypted
+            token.create_time         as token_create_time,
+            token.update_time         as token_update_time,
+            token.last_renewal_time   as token_last_renewal_time,
+            token.expiration_time     as token_expiration_time,
+            token.renewal_time        as token_renewal_time,
+            token.key_id              as token_key_id,
+            token.status              as token_status,
+            cert.certificate          as client_cert,
+            cert.certificate_key      as ct_client_key, -- encrypted
+            cert.certificate_key_hmac as client_cert_key_hmac,
+            cert.key_id               as client_key_id,
+            approle.role_id           as approle_role_id,
+            approle.secret_id         as ct_approle_secret_id, -- encrypted
+            approle.secret_id_hmac    as approle_secret_id_hmac,
+            approle.key_id            as approle_key_id,
+            store.connect_timeout_seconds as connect_timeout_seconds,
+            store.request_timeout_seconds as request_timeout_seconds,
+            store.requests_per_second     as requests_per_second,
+            store.tls_min_version         as tls_min_version,
+            store.token_file_path         as token_file_path,
+            store.auth_method             as auth_method
+       from credential_vault_store store
+  left join active_tokens token
+         on store.public_id = token.store_id
+  left join credential_vault_client_certificate cert
+         on store.public_id = cert.store_id
+  left join credential_vault_approle approle
+         on store.public_id = approle.store_id;
+
+  -- replaces view from 17/14_credential_vault_store_token_file.up.sql
+  -- adds the auth_method column to the end of the view
+     create or replace view credential_vault_store_public as
+     select public_id,
+            scope_id,
+            name,
+            description,
+            create_time,
+            update_time,
+            version,
+            vault_address,
+            namespace,
+            ca_cert,
+            tls_server_name,
+            tls_skip_verify,
+            token_hmac,
+            token_create_time,
+            token_update_time,
+            token_last_renewal_time,
+            token_expiration_time,
+            client_cert,
+            client_cert_key_hmac,
+            approle_role_id,
+            approle_secret_id_hmac,
+            connect_timeout_seconds,
+            request_timeout_seconds,
+            requests_per_second,
+            tls_min_version,
+            token_file_path,
+            auth_method
+       from credential_vault_store_private
+      where token_status = 'current'
+        and delete_time is null;
+
+commit;
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/hashicorp/boundary/internal/db/schema"
+	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/boundary/internal/oplog/store"
 	"github.com/hashicorp/boundary/testing/dbtest"
@@ -161,6 +163,27 @@ and create_time > NOW()::timestamp - (interval '1 second' * ?)
 	return nil
 }
 
+// TestVerifyOplogBatch will verify that there is an oplog entry for each of
+// the provided resourceIds. It supports the same options as TestVerifyOplog,
+// which are applied to the lookup of every entry. An error listing each of
+// the resourceIds without a matching entry is returned if any entry is not
+// found.
+func TestVerifyOplogBatch(t *testing.T, r Reader, resourceIds []string, opt ...TestOption) error {
+	t.Helper()
+	const op = "db.TestVerifyOplogBatch"
+	var missing []string
+	for _, id := range resourceIds {
+		if err := TestVerifyOplog(t, r, id, opt...); err != nil {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New(context.Background(), errors.RecordNotFound, op,
+			fmt.Sprintf("missing oplog entries for %d of %d resources: %s", len(missing), len(resourceIds), strings.Join(missing, ", ")), errors.WithoutEvent())
+	}
+	return nil
+}
+
 // getTestOpts - iterate the inbound TestOptions and return a struct
 func getTestOpts(opt ...TestOption) testOptions {
 	opts := getDefaultTestOptions()
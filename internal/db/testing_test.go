@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/hashicorp/go-uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_Utils(t *testing.T) {
@@ -62,6 +63,52 @@ func TestVerifyOplogEntry(t *testing.T) {
 	})
 }
 
+func TestVerifyOplogBatchEntries(t *testing.T) {
+	db, _ := TestSetup(t, "postgres")
+	assert, require := assert.New(t), require.New(t)
+	rw := Db{underlying: db}
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := uuid.GenerateUUID()
+		require.NoError(err)
+		user, err := db_test.NewTestUser()
+		require.NoError(err)
+		user.Name = "batch-" + id
+		err = rw.Create(
+			context.Background(),
+			user,
+			WithOplog(
+				TestWrapper(t),
+				oplog.Metadata{
+					"op-type":            []string{oplog.OpType_OP_TYPE_CREATE.String()},
+					"resource-public-id": []string{user.GetPublicId()},
+				}),
+		)
+		require.NoError(err)
+		ids = append(ids, user.GetPublicId())
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		err := TestVerifyOplogBatch(t, &rw, ids, WithOperation(oplog.OpType_OP_TYPE_CREATE), WithCreateNotBefore(5*time.Second))
+		assert.NoError(err)
+	})
+	t.Run("wrong-operation", func(t *testing.T) {
+		err := TestVerifyOplogBatch(t, &rw, ids, WithOperation(oplog.OpType_OP_TYPE_UPDATE))
+		assert.Error(err)
+	})
+	t.Run("missing-entry", func(t *testing.T) {
+		missing, err := uuid.GenerateUUID()
+		require.NoError(err)
+		err = TestVerifyOplogBatch(t, &rw, append([]string{missing}, ids...))
+		require.Error(err)
+		assert.Contains(err.Error(), missing)
+		for _, id := range ids {
+			assert.NotContains(err.Error(), id)
+		}
+	})
+}
+
 func Test_getTestOpts(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
@@ -87,6 +87,10 @@ const (
 	// reasons related to the state of the DDL and/or inputs (such as we're
 	// already in the right state and don't want to end up writing oplogs).
 	GracefullyAborted Code = 1106
+	// TransactionConflict represents a db transaction which was rolled back
+	// because it conflicted with a concurrent transaction, such as a
+	// serialization failure or a deadlock.  The transaction can be retried.
+	TransactionConflict Code = 1107
 
 	// Migration setup errors are codes 2000-2999
 	MigrationIntegrity Code = 2000 // MigrationIntegrity represents an error with the generated migration related code
@@ -101,6 +105,8 @@ const (
 	VaultTokenNotRenewable        Code = 3012 // VaultTokenNotRenewable represents an error for a Vault token that is not renewable
 	VaultTokenMissingCapabilities Code = 3013 // VaultTokenMissingCapabilities represents an error for a Vault token that is missing capabilities
 	VaultCredentialRequest        Code = 3014 // VaultCredentialRequest represents an error returned from Vault when retrieving a credential
+	VaultTokenExpired             Code = 3015 // VaultTokenExpired represents an error for a Vault token that is expired or otherwise no longer valid
+	VaultRateLimited              Code = 3016 // VaultRateLimited represents an error for a Vault request that exceeded the rate limit of a credential store
 
 	// OIDC authentication provided errors
 	OidcProviderCallbackError Code = 4000 // OidcProviderCallbackError represents an error that is passed by the OIDC provider to the callback endpoint
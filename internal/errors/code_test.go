@@ -282,6 +282,16 @@ func TestCode_Both_String_Info(t *testing.T) {
 			c:    VaultCredentialRequest,
 			want: VaultCredentialRequest,
 		},
+		{
+			name: "VaultTokenExpired",
+			c:    VaultTokenExpired,
+			want: VaultTokenExpired,
+		},
+		{
+			name: "VaultRateLimited",
+			c:    VaultRateLimited,
+			want: VaultRateLimited,
+		},
 		{
 			name: "OidcProviderCallbackError",
 			c:    OidcProviderCallbackError,
@@ -292,6 +302,11 @@ func TestCode_Both_String_Info(t *testing.T) {
 			c:    GracefullyAborted,
 			want: GracefullyAborted,
 		},
+		{
+			name: "TransactionConflict",
+			c:    TransactionConflict,
+			want: TransactionConflict,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -31,6 +31,9 @@ type Err struct {
 	// include the receiver type in parentheses "package.(type).func"
 	Op Op
 
+	// Field is the name of the field which caused the error and is optional.
+	Field string
+
 	// Wrapped is the error which this Err wraps and will be nil if there's no
 	// error to wrap.
 	Wrapped error
@@ -52,6 +55,9 @@ type Err struct {
 //
 // * WithCode() - allows you to specify an optional Code, this code will be prioritized
 // over a code used from WithWrap().
+//
+// * WithField() - allows you to specify the name of the field which caused
+// the error.
 func E(ctx context.Context, opt ...Option) error {
 	// nil ctx is allowed and tested for in unit tests
 	opts := GetOpts(opt...)
@@ -74,6 +80,7 @@ func E(ctx context.Context, opt ...Option) error {
 		Op:      opts.withOp,
 		Wrapped: opts.withErrWrapped,
 		Msg:     opts.withErrMsg,
+		Field:   opts.withField,
 	}
 	if opts.withoutEvent {
 		return err
@@ -88,6 +95,7 @@ func E(ctx context.Context, opt ...Option) error {
 			Op:      err.Op,
 			Wrapped: err.Wrapped,
 			Msg:     err.Msg,
+			Field:   err.Field,
 		}
 		if eventErr.Op == "" {
 			const has = "github.com/hashicorp/boundary/internal/errors."
@@ -208,6 +216,12 @@ func Convert(e error) *Err {
 				return E(ctx, WithoutEvent(), WithCode(NotSpecificIntegrity), WithMsg(pgxError.Message)).(*Err)
 			}
 		}
+		if pgxError.Code[0:2] == "40" { // class of transaction rollbacks
+			switch pgxError.Code {
+			case "40001", "40P01": // serialization_failure, deadlock_detected
+				return E(ctx, WithoutEvent(), WithMsg(pgxError.Message), WithWrap(E(ctx, WithoutEvent(), WithCode(TransactionConflict), WithMsg("transaction conflict")))).(*Err)
+			}
+		}
 		switch pgxError.Code {
 		case "42P01":
 			return E(ctx, WithoutEvent(), WithCode(MissingTable), WithMsg(pgxError.Message)).(*Err)
@@ -221,6 +235,24 @@ func Convert(e error) *Err {
 	return nil
 }
 
+// Field returns the name of the field which caused err. The first field
+// found in err's chain of wrapped errors is returned. It returns an empty
+// string if err is not a domain error or no field was attached with
+// WithField.
+func Field(err error) string {
+	for err != nil {
+		var e *Err
+		if !As(err, &e) {
+			return ""
+		}
+		if e.Field != "" {
+			return e.Field
+		}
+		err = e.Wrapped
+	}
+	return ""
+}
+
 // Info about the Err
 func (e *Err) Info() Info {
 	if e == nil {
@@ -161,6 +161,21 @@ func Test_NewError(t *testing.T) {
 				Code: errors.Unknown,
 			},
 		},
+		{
+			name: "with-field",
+			code: errors.InvalidParameter,
+			op:   "alice.Bob",
+			msg:  "no vault path",
+			opt: []errors.Option{
+				errors.WithField("VaultPath"),
+			},
+			want: &errors.Err{
+				Op:    "alice.Bob",
+				Msg:   "no vault path",
+				Code:  errors.InvalidParameter,
+				Field: "VaultPath",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -409,6 +424,59 @@ func TestError_Unwrap(t *testing.T) {
 	})
 }
 
+func TestError_Field(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+	withField := errors.New(ctx, errors.InvalidParameter, "alice.Bob", "no vault path", errors.WithoutEvent(), errors.WithField("VaultPath"))
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "not-a-domain-error",
+			err:  stderrors.New("test error"),
+			want: "",
+		},
+		{
+			name: "no-field",
+			err:  errors.New(ctx, errors.InvalidParameter, "alice.Bob", "test msg", errors.WithoutEvent()),
+			want: "",
+		},
+		{
+			name: "field",
+			err:  withField,
+			want: "VaultPath",
+		},
+		{
+			name: "wrapped-field",
+			err:  errors.Wrap(ctx, withField, "alice.Carol", errors.WithoutEvent()),
+			want: "VaultPath",
+		},
+		{
+			name: "outer-field-first",
+			err:  errors.Wrap(ctx, withField, "alice.Carol", errors.WithoutEvent(), errors.WithField("StoreId")),
+			want: "StoreId",
+		},
+		{
+			name: "std-wrapped-field",
+			err:  fmt.Errorf("test: %w", withField),
+			want: "VaultPath",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errors.Field(tt.err))
+		})
+	}
+}
+
 func TestConvertError(t *testing.T) {
 	t.Parallel()
 	testErr := errors.EDeprecated(errors.WithCode(errors.InvalidParameter), errors.WithOp("alice.Bob"), errors.WithMsg("test msg"))
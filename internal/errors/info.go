@@ -224,6 +224,14 @@ var errorCodeInfo = map[Code]Info{
 		Message: "request for a new credential from vault failed",
 		Kind:    External,
 	},
+	VaultTokenExpired: {
+		Message: "vault token is expired",
+		Kind:    VaultToken,
+	},
+	VaultRateLimited: {
+		Message: "vault request rate limit exceeded",
+		Kind:    External,
+	},
 	OidcProviderCallbackError: {
 		Message: "oidc provider callback error",
 		Kind:    External,
@@ -232,4 +240,8 @@ var errorCodeInfo = map[Code]Info{
 		Message: "purposefully aborted without error",
 		Kind:    Other,
 	},
+	TransactionConflict: {
+		Message: "transaction conflict",
+		Kind:    Transaction,
+	},
 }
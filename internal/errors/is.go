@@ -2,6 +2,9 @@ package errors
 
 import (
 	"errors"
+	"net"
+	"strings"
+	"syscall"
 
 	"github.com/jackc/pgconn"
 )
@@ -94,3 +97,39 @@ func IsNotFoundError(err error) bool {
 
 	return false
 }
+
+// IsRetryable returns a boolean indicating whether the error is known to
+// report a transient condition, such as a transaction conflict or a reset
+// connection, where retrying the operation may succeed.  Errors reporting
+// invalid parameters or constraint violations are not retryable, nor are
+// MaxRetries errors since the operation has already been retried.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var e *Err
+	if errors.As(err, &e) && e.Code != Unknown {
+		switch e.Code {
+		case TransactionConflict, Unavailable, VaultRateLimited:
+			return true
+		}
+		return false
+	}
+
+	var pgxError *pgconn.PgError
+	if errors.As(err, &pgxError) {
+		// classes of transaction rollbacks and connection exceptions
+		return strings.HasPrefix(pgxError.Code, "40") || strings.HasPrefix(pgxError.Code, "08")
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netError net.Error
+	if errors.As(err, &netError) && netError.Timeout() {
+		return true
+	}
+
+	return false
+}
@@ -3,6 +3,8 @@ package errors_test
 import (
 	"context"
 	"fmt"
+	"net"
+	"syscall"
 	"testing"
 
 	"github.com/hashicorp/boundary/internal/db"
@@ -295,3 +297,122 @@ func TestError_IsNotFoundError(t *testing.T) {
 		})
 	}
 }
+
+func TestError_IsRetryable(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   error
+		want bool
+	}{
+		{
+			name: "nil-error",
+			in:   nil,
+			want: false,
+		},
+		{
+			name: "postgres-serialization-failure",
+			in: &pgconn.PgError{
+				Code: "40001",
+			},
+			want: true,
+		},
+		{
+			name: "postgres-deadlock",
+			in: &pgconn.PgError{
+				Code: "40P01",
+			},
+			want: true,
+		},
+		{
+			name: "postgres-connection-failure",
+			in: &pgconn.PgError{
+				Code: "08006",
+			},
+			want: true,
+		},
+		{
+			name: "postgres-unique-violation",
+			in: &pgconn.PgError{
+				Code: "23505",
+			},
+			want: false,
+		},
+		{
+			name: "wrapped-pg-serialization-failure",
+			in:   errors.Wrap(context.TODO(), &pgconn.PgError{Code: "40001"}, "test.op"),
+			want: true,
+		},
+		{
+			name: "wrapped-pg-check-violation",
+			in:   errors.Wrap(context.TODO(), &pgconn.PgError{Code: "23514"}, "test.op"),
+			want: false,
+		},
+		{
+			name: "CodeTransactionConflict",
+			in:   errors.E(context.TODO(), errors.WithCode(errors.TransactionConflict)),
+			want: true,
+		},
+		{
+			name: "CodeMaxRetries",
+			in:   errors.E(context.TODO(), errors.WithCode(errors.MaxRetries)),
+			want: false,
+		},
+		{
+			name: "CodeMaxRetries-wrapping-transaction-conflict",
+			in: errors.E(context.TODO(), errors.WithCode(errors.MaxRetries),
+				errors.WithWrap(errors.E(context.TODO(), errors.WithCode(errors.TransactionConflict)))),
+			want: false,
+		},
+		{
+			name: "CodeUnavailable",
+			in:   errors.E(context.TODO(), errors.WithCode(errors.Unavailable)),
+			want: true,
+		},
+		{
+			name: "CodeNotUnique",
+			in:   errors.E(context.TODO(), errors.WithCode(errors.NotUnique)),
+			want: false,
+		},
+		{
+			name: "CodeCheckConstraint",
+			in:   errors.E(context.TODO(), errors.WithCode(errors.CheckConstraint)),
+			want: false,
+		},
+		{
+			name: "CodeInvalidParameter",
+			in:   errors.E(context.TODO(), errors.WithCode(errors.InvalidParameter)),
+			want: false,
+		},
+		{
+			name: "connection-reset",
+			in:   fmt.Errorf("read tcp: %w", syscall.ECONNRESET),
+			want: true,
+		},
+		{
+			name: "network-timeout",
+			in:   &net.OpError{Op: "dial", Err: testTimeoutError{}},
+			want: true,
+		},
+		{
+			name: "unknown-error",
+			in:   fmt.Errorf("unknown"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			got := errors.IsRetryable(tt.in)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+// testTimeoutError is a net.Error which reports a timeout.
+type testTimeoutError struct{}
+
+func (testTimeoutError) Error() string   { return "i/o timeout" }
+func (testTimeoutError) Timeout() bool   { return true }
+func (testTimeoutError) Temporary() bool { return true }
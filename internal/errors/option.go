@@ -19,6 +19,7 @@ type Options struct {
 	withErrMsg     string
 	withOp         Op
 	withoutEvent   bool
+	withField      string
 }
 
 func getDefaultOptions() Options {
@@ -57,6 +58,14 @@ func WithCode(code Code) Option {
 	}
 }
 
+// WithField provides an option to provide the name of the field which
+// caused the error.
+func WithField(name string) Option {
+	return func(o *Options) {
+		o.withField = name
+	}
+}
+
 func WithoutEvent() Option {
 	return func(o *Options) {
 		o.withoutEvent = true
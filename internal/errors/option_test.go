@@ -77,4 +77,17 @@ func Test_getOpts(t *testing.T) {
 		testOpts.withoutEvent = true
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithField", func(t *testing.T) {
+		assert := assert.New(t)
+		// test default
+		opts := GetOpts()
+		testOpts := getDefaultOptions()
+		testOpts.withField = ""
+		assert.Equal(opts, testOpts)
+
+		// try setting it
+		opts = GetOpts(WithField("VaultPath"))
+		testOpts.withField = "VaultPath"
+		assert.Equal(opts, testOpts)
+	})
 }
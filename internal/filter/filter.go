@@ -1,8 +1,11 @@
 package filter
 
 import (
+	"fmt"
 	"reflect"
 
+	"github.com/hashicorp/go-bexpr"
+
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -41,3 +44,45 @@ func WellKnownTypeFilterHook(v reflect.Value) reflect.Value {
 	}
 	return reflect.ValueOf(ret)
 }
+
+// item captures all the different namespaces that can be used when
+// filtering an item.
+type item struct {
+	Item interface{} `json:"item"`
+}
+
+// An Evaluator matches items against a go-bexpr filter expression. The
+// item is selected in the expression with "/item" and its fields are
+// selected by their json names, for example "/item/name" == "x". Proto
+// well-known types are compared as the types they wrap.
+type Evaluator struct {
+	eval *bexpr.Evaluator
+}
+
+// NewEvaluator returns an Evaluator for the filter expression f. An
+// Evaluator for an empty expression matches every item. An error is
+// returned if f is not a valid expression.
+func NewEvaluator(f string) (*Evaluator, error) {
+	const op = "filter.NewEvaluator"
+	if f == "" {
+		return &Evaluator{}, nil
+	}
+	e, err := bexpr.CreateEvaluator(f, bexpr.WithTagName("json"), bexpr.WithHookFn(WellKnownTypeFilterHook))
+	if err != nil {
+		return nil, fmt.Errorf("%s: couldn't build filter: %w", op, err)
+	}
+	return &Evaluator{eval: e}, nil
+}
+
+// Match reports whether i matches the filter expression of e.
+func (e *Evaluator) Match(i interface{}) bool {
+	if e.eval == nil {
+		return true
+	}
+	m, err := e.eval.Evaluate(item{Item: i})
+	// There isn't a clear way to differentiate between a JSON Pointer which doesn't represent
+	// the structure of the object being Matched and a JSON Pointer which references a field which
+	// is part of a sub structure that is nil in this item. Because of this, any filter which would
+	// result in an error using the underlying library is simply interpreted as not a match.
+	return err == nil && m
+}
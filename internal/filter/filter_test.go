@@ -51,3 +51,69 @@ func TestWellKnownTypeFilterHook(t *testing.T) {
 		assert.Equal(expect, actual.Interface())
 	})
 }
+
+func TestEvaluator(t *testing.T) {
+	type embedded struct {
+		Name string `json:"name,omitempty"`
+	}
+	type multiLevel struct {
+		E       *embedded               `json:"e"`
+		Version *wrapperspb.UInt32Value `json:"version"`
+	}
+	cases := []struct {
+		name    string
+		filter  string
+		wantErr bool
+		in      interface{}
+		match   bool
+	}{
+		{
+			name:  "empty",
+			in:    "foo",
+			match: true,
+		},
+		{
+			name:    "bad format",
+			filter:  `random strings that dont match a format`,
+			wantErr: true,
+		},
+		{
+			name:   "field name",
+			filter: `"/item/e/name"=="x"`,
+			in:     multiLevel{E: &embedded{Name: "x"}},
+			match:  true,
+		},
+		{
+			name:   "field name no match",
+			filter: `"/item/e/name"=="x"`,
+			in:     multiLevel{E: &embedded{Name: "y"}},
+			match:  false,
+		},
+		{
+			name:   "nil sub structure",
+			filter: `"/item/e/name"=="x"`,
+			in:     multiLevel{},
+			match:  false,
+		},
+		{
+			name:   "well known type",
+			filter: `"/item/version"==2`,
+			in:     multiLevel{Version: wrapperspb.UInt32(2)},
+			match:  true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			e, err := NewEvaluator(tc.filter)
+			if tc.wantErr {
+				assert.Error(err)
+				assert.Nil(e)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tc.match, e.Match(tc.in))
+		})
+	}
+}
@@ -15,7 +15,8 @@ import (
 // CreateSet inserts s into the repository and returns a new HostSet
 // containing the host set's PublicId. s is not changed. s must contain a
 // valid CatalogId. s must not contain a PublicId. The PublicId is
-// generated and assigned by this method. opt is ignored.
+// generated and assigned by this method. opt is ignored. The returned
+// HostSet always has both CreateTime and UpdateTime set.
 //
 // Both s.Name and s.Description are optional. If s.Name is set, it must be
 // unique within s.CatalogId.
@@ -64,12 +65,19 @@ func (r *Repository) CreateSet(ctx context.Context, scopeId string, s *HostSet,
 
 	var newHostSet *HostSet
 	_, err = r.writer.DoTx(ctx, db.StdRetryCnt, db.ExpBackoff{},
-		func(_ db.Reader, w db.Writer) error {
+		func(reader db.Reader, w db.Writer) error {
 			newHostSet = s.clone()
 			err := w.Create(ctx, newHostSet, db.WithOplog(oplogWrapper, s.oplog(oplog.OpType_OP_TYPE_CREATE)))
 			if err != nil {
 				return errors.Wrap(ctx, err, op)
 			}
+			if newHostSet.GetCreateTime() == nil || newHostSet.GetUpdateTime() == nil {
+				// the timestamps are set by the database, read them back
+				// if they were not returned by the insert
+				if err := reader.LookupByPublicId(ctx, newHostSet); err != nil {
+					return errors.Wrap(ctx, err, op, errors.WithMsg("unable to read timestamps"))
+				}
+			}
 			return nil
 		},
 	)
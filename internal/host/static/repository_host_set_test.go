@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/boundary/internal/oplog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 )
 
@@ -126,11 +127,36 @@ func TestRepository_CreateSet(t *testing.T) {
 			assert.NotSame(tt.in, got)
 			assert.Equal(tt.want.Name, got.Name)
 			assert.Equal(tt.want.Description, got.Description)
+			assert.NotNil(got.CreateTime)
+			assert.NotNil(got.UpdateTime)
 			assert.Equal(got.CreateTime, got.UpdateTime)
 			assert.NoError(db.TestVerifyOplog(t, rw, got.PublicId, db.WithOperation(oplog.OpType_OP_TYPE_CREATE), db.WithCreateNotBefore(10*time.Second)))
 		})
 	}
 
+	t.Run("round-trip-timestamps", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		ctx := context.Background()
+		repo, err := NewRepository(rw, rw, kms)
+		require.NoError(err)
+		require.NotNil(repo)
+		in, err := NewHostSet(catalog.PublicId)
+		require.NoError(err)
+		got, err := repo.CreateSet(ctx, prj.GetPublicId(), in)
+		require.NoError(err)
+		require.NotNil(got)
+		require.NotNil(got.GetCreateTime())
+		require.NotNil(got.GetUpdateTime())
+
+		found, _, err := repo.LookupSet(ctx, got.PublicId)
+		require.NoError(err)
+		require.NotNil(found)
+		require.NotNil(found.GetCreateTime())
+		require.NotNil(found.GetUpdateTime())
+		assert.True(proto.Equal(got.GetCreateTime(), found.GetCreateTime()))
+		assert.True(proto.Equal(got.GetUpdateTime(), found.GetUpdateTime()))
+	})
+
 	t.Run("invalid-duplicate-names", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
 		repo, err := NewRepository(rw, rw, kms)
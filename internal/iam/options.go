@@ -29,6 +29,7 @@ type options struct {
 	withRandomReader            io.Reader
 	withAccountIds              []string
 	withPrimaryAuthMethodId     string
+	withRecursive               bool
 }
 
 func getDefaultOptions() options {
@@ -141,3 +142,11 @@ func WithPrimaryAuthMethodId(id string) Option {
 		o.withPrimaryAuthMethodId = id
 	}
 }
+
+// WithRecursive provides an option to expand the scopes of a scope-aware list
+// operation to include all of their descendant scopes.
+func WithRecursive(recursive bool) Option {
+	return func(o *options) {
+		o.withRecursive = recursive
+	}
+}
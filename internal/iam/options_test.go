@@ -79,4 +79,11 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withPrimaryAuthMethodId = "test"
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithRecursive", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithRecursive(true))
+		testOpts := getDefaultOptions()
+		testOpts.withRecursive = true
+		assert.Equal(opts, testOpts)
+	})
 }
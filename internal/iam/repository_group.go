@@ -170,12 +170,20 @@ func (r *Repository) DeleteGroup(ctx context.Context, withPublicId string, _ ...
 	return rowsDeleted, nil
 }
 
-// ListGroups lists groups in the given scopes and supports WithLimit option.
+// ListGroups lists groups in the given scopes and supports the WithLimit and
+// WithRecursive options.
 func (r *Repository) ListGroups(ctx context.Context, withScopeIds []string, opt ...Option) ([]*Group, error) {
 	const op = "iam.(Repository).ListGroups"
 	if len(withScopeIds) == 0 {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "missing scope id")
 	}
+	if getOpts(opt...).withRecursive {
+		var err error
+		withScopeIds, err = r.recursiveScopeIds(ctx, withScopeIds)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
 	var grps []*Group
 	err := r.list(ctx, &grps, "scope_id in (?)", []interface{}{withScopeIds}, opt...)
 	if err != nil {
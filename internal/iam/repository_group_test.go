@@ -669,6 +669,46 @@ func TestRepository_ListGroups_Multiple_Scopes(t *testing.T) {
 	assert.Equal(t, total, len(got))
 }
 
+func TestRepository_ListGroups_Recursive(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	org, proj1 := TestScopes(t, repo)
+	proj2 := testProject(t, repo, org.GetPublicId())
+
+	orgGrp := TestGroup(t, conn, org.GetPublicId())
+	proj1Grp := TestGroup(t, conn, proj1.GetPublicId())
+	proj2Grp := TestGroup(t, conn, proj2.GetPublicId())
+
+	groupIds := func(grps []*Group) []string {
+		var ids []string
+		for _, g := range grps {
+			ids = append(ids, g.GetPublicId())
+		}
+		return ids
+	}
+
+	t.Run("recursive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListGroups(context.Background(), []string{org.GetPublicId()}, WithRecursive(true))
+		require.NoError(err)
+		assert.ElementsMatch([]string{orgGrp.GetPublicId(), proj1Grp.GetPublicId(), proj2Grp.GetPublicId()}, groupIds(got))
+	})
+	t.Run("non-recursive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListGroups(context.Background(), []string{org.GetPublicId()})
+		require.NoError(err)
+		assert.ElementsMatch([]string{orgGrp.GetPublicId()}, groupIds(got))
+	})
+	t.Run("recursive-project", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListGroups(context.Background(), []string{proj1.GetPublicId()}, WithRecursive(true))
+		require.NoError(err)
+		assert.ElementsMatch([]string{proj1Grp.GetPublicId()}, groupIds(got))
+	})
+}
+
 func TestRepository_ListMembers(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
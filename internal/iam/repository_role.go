@@ -181,12 +181,20 @@ func (r *Repository) DeleteRole(ctx context.Context, withPublicId string, _ ...O
 	return rowsDeleted, nil
 }
 
-// ListRoles lists roles in the given scopes and supports WithLimit option.
+// ListRoles lists roles in the given scopes and supports the WithLimit and
+// WithRecursive options.
 func (r *Repository) ListRoles(ctx context.Context, withScopeIds []string, opt ...Option) ([]*Role, error) {
 	const op = "iam.(Repository).ListRoles"
 	if len(withScopeIds) == 0 {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "missing scope ids")
 	}
+	if getOpts(opt...).withRecursive {
+		var err error
+		withScopeIds, err = r.recursiveScopeIds(ctx, withScopeIds)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
 	var roles []*Role
 	err := r.list(ctx, &roles, "scope_id in (?)", []interface{}{withScopeIds}, opt...)
 	if err != nil {
@@ -666,3 +666,45 @@ func TestRepository_ListRoles_Multiple_Scopes(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, total, len(got))
 }
+
+func TestRepository_ListRoles_Recursive(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+	org, proj1 := TestScopes(t, repo)
+	proj2 := testProject(t, repo, org.GetPublicId())
+
+	require.NoError(t, conn.Where("1=1").Delete(allocRole()).Error)
+
+	orgRole := TestRole(t, conn, org.GetPublicId())
+	proj1Role := TestRole(t, conn, proj1.GetPublicId())
+	proj2Role := TestRole(t, conn, proj2.GetPublicId())
+
+	roleIds := func(roles []*Role) []string {
+		var ids []string
+		for _, r := range roles {
+			ids = append(ids, r.GetPublicId())
+		}
+		return ids
+	}
+
+	t.Run("recursive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListRoles(context.Background(), []string{org.GetPublicId()}, WithRecursive(true))
+		require.NoError(err)
+		assert.ElementsMatch([]string{orgRole.GetPublicId(), proj1Role.GetPublicId(), proj2Role.GetPublicId()}, roleIds(got))
+	})
+	t.Run("non-recursive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListRoles(context.Background(), []string{org.GetPublicId()})
+		require.NoError(err)
+		assert.ElementsMatch([]string{orgRole.GetPublicId()}, roleIds(got))
+	})
+	t.Run("recursive-project", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListRoles(context.Background(), []string{proj2.GetPublicId()}, WithRecursive(true))
+		require.NoError(err)
+		assert.ElementsMatch([]string{proj2Role.GetPublicId()}, roleIds(got))
+	})
+}
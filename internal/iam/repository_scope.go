@@ -452,12 +452,20 @@ func (r *Repository) DeleteScope(ctx context.Context, withPublicId string, _ ...
 	return rowsDeleted, nil
 }
 
-// ListScopes with the parent IDs, supports the WithLimit option.
+// ListScopes with the parent IDs, supports the WithLimit and WithRecursive
+// options.
 func (r *Repository) ListScopes(ctx context.Context, withParentIds []string, opt ...Option) ([]*Scope, error) {
 	const op = "iam.(Repository).ListScopes"
 	if len(withParentIds) == 0 {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "missing parent id")
 	}
+	if getOpts(opt...).withRecursive {
+		var err error
+		withParentIds, err = r.recursiveScopeIds(ctx, withParentIds)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
 	var items []*Scope
 	err := r.list(ctx, &items, "parent_id in (?)", []interface{}{withParentIds}, opt...)
 	if err != nil {
@@ -495,3 +503,24 @@ func (r *Repository) ListScopesRecursively(ctx context.Context, rootScopeId stri
 	}
 	return scopes, nil
 }
+
+// recursiveScopeIds returns scopeIds along with the public ids of all of
+// their descendant scopes. Each id is only returned once.
+func (r *Repository) recursiveScopeIds(ctx context.Context, scopeIds []string) ([]string, error) {
+	const op = "iam.(Repository).recursiveScopeIds"
+	seen := make(map[string]bool, len(scopeIds))
+	ids := make([]string, 0, len(scopeIds))
+	for _, id := range scopeIds {
+		scopes, err := r.ListScopesRecursively(ctx, id, WithLimit(-1))
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op, errors.WithMsg(fmt.Sprintf("failed for %s", id)))
+		}
+		for _, s := range scopes {
+			if !seen[s.PublicId] {
+				seen[s.PublicId] = true
+				ids = append(ids, s.PublicId)
+			}
+		}
+	}
+	return ids, nil
+}
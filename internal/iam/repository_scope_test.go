@@ -564,6 +564,39 @@ func TestRepository_ListScopes_Multiple_Scopes(t *testing.T) {
 	assert.Equal(t, total, len(got))
 }
 
+func TestRepository_ListScopes_Recursive(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := TestRepo(t, conn, wrapper)
+
+	require.NoError(t, conn.Where("public_id != 'global'").Delete(AllocScope()).Error)
+
+	org, proj1 := TestScopes(t, repo)
+	proj2 := testProject(t, repo, org.GetPublicId())
+
+	scopeIds := func(scopes []*Scope) []string {
+		var ids []string
+		for _, s := range scopes {
+			ids = append(ids, s.GetPublicId())
+		}
+		return ids
+	}
+
+	t.Run("recursive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListScopes(context.Background(), []string{"global"}, WithRecursive(true))
+		require.NoError(err)
+		assert.ElementsMatch([]string{org.GetPublicId(), proj1.GetPublicId(), proj2.GetPublicId()}, scopeIds(got))
+	})
+	t.Run("non-recursive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListScopes(context.Background(), []string{"global"})
+		require.NoError(err)
+		assert.ElementsMatch([]string{org.GetPublicId()}, scopeIds(got))
+	})
+}
+
 func Test_Repository_ListRecursive(t *testing.T) {
 	t.Parallel()
 	conn, _ := db.TestSetup(t, "postgres")
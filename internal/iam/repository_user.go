@@ -205,12 +205,20 @@ func (r *Repository) DeleteUser(ctx context.Context, withPublicId string, _ ...O
 	return rowsDeleted, nil
 }
 
-// ListUsers lists users in the given scopes and supports the WithLimit option.
+// ListUsers lists users in the given scopes and supports the WithLimit and
+// WithRecursive options.
 func (r *Repository) ListUsers(ctx context.Context, withScopeIds []string, opt ...Option) ([]*User, error) {
 	const op = "iam.(Repository).ListUsers"
 	if len(withScopeIds) == 0 {
 		return nil, errors.New(ctx, errors.InvalidParameter, op, "missing scope id")
 	}
+	if getOpts(opt...).withRecursive {
+		var err error
+		withScopeIds, err = r.recursiveScopeIds(ctx, withScopeIds)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+	}
 	users, err := r.getUsers(ctx, "", withScopeIds, opt...)
 	if err != nil {
 		return nil, errors.Wrap(ctx, err, op)
@@ -709,6 +709,49 @@ func TestRepository_ListUsers_Multiple_Scopes(t *testing.T) {
 	assert.Equal(t, total, len(got))
 }
 
+func TestRepository_ListUsers_Recursive(t *testing.T) {
+	t.Parallel()
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	repo := iam.TestRepo(t, conn, wrapper)
+	org1, _ := iam.TestScopes(t, repo)
+	org2, _ := iam.TestScopes(t, repo)
+
+	require.NoError(t, conn.Where("public_id != 'u_anon' and public_id != 'u_auth' and public_id != 'u_recovery'").Delete(iam.AllocUser()).Error)
+
+	globalUsr := iam.TestUser(t, repo, "global")
+	org1Usr := iam.TestUser(t, repo, org1.GetPublicId())
+	org2Usr := iam.TestUser(t, repo, org2.GetPublicId())
+	globalIds := []string{"u_anon", "u_auth", "u_recovery", globalUsr.GetPublicId()}
+
+	userIds := func(users []*iam.User) []string {
+		var ids []string
+		for _, u := range users {
+			ids = append(ids, u.GetPublicId())
+		}
+		return ids
+	}
+
+	t.Run("recursive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListUsers(context.Background(), []string{"global"}, iam.WithRecursive(true))
+		require.NoError(err)
+		assert.ElementsMatch(append(globalIds, org1Usr.GetPublicId(), org2Usr.GetPublicId()), userIds(got))
+	})
+	t.Run("non-recursive", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListUsers(context.Background(), []string{"global"})
+		require.NoError(err)
+		assert.ElementsMatch(globalIds, userIds(got))
+	})
+	t.Run("recursive-org", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		got, err := repo.ListUsers(context.Background(), []string{org1.GetPublicId()}, iam.WithRecursive(true))
+		require.NoError(err)
+		assert.ElementsMatch([]string{org1Usr.GetPublicId()}, userIds(got))
+	})
+}
+
 func TestRepository_LookupUserWithLogin(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -0,0 +1,172 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/eventlogger"
+)
+
+const bufferedNodeName = "buffered-formatter-filter"
+
+// bufferedFormatterFilter wraps a formatter filter node and buffers its
+// formatted events before sending them to the downstream sink.  The buffer is
+// flushed when it's full, when the flush interval elapses, when the node is
+// reopened (e.g. log rotation on a SIGHUP) and when the eventer's nodes are
+// flushed on shutdown, so buffered events aren't lost.
+type bufferedFormatterFilter struct {
+	formatter  eventlogger.Node
+	downstream eventlogger.Node
+	size       int
+
+	// onFlushErr is called with the errors of flushes which aren't started
+	// by a caller which can be given the error: flushes of a full buffer
+	// and flushes at the flush interval.
+	onFlushErr func(error)
+
+	mu     sync.Mutex
+	buffer []*eventlogger.Event
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// newBufferedFormatterFilter returns a node which formats events with the
+// formatter node and buffers up to size formatted events before sending them
+// to the downstream node.  If flushInterval is greater than zero, the buffer
+// is also flushed at that interval until FlushAll is called.  The errors of
+// flushes which are not returned to a caller are passed to onFlushErr.
+func newBufferedFormatterFilter(formatter, downstream eventlogger.Node, size int, flushInterval time.Duration, onFlushErr func(error)) (*bufferedFormatterFilter, error) {
+	const op = "event.newBufferedFormatterFilter"
+	switch {
+	case formatter == nil:
+		return nil, fmt.Errorf("%s: missing formatter node: %w", op, ErrInvalidParameter)
+	case downstream == nil:
+		return nil, fmt.Errorf("%s: missing downstream node: %w", op, ErrInvalidParameter)
+	case size < 1:
+		return nil, fmt.Errorf("%s: buffer size must be greater than zero: %w", op, ErrInvalidParameter)
+	case flushInterval < 0:
+		return nil, fmt.Errorf("%s: flush interval must not be negative: %w", op, ErrInvalidParameter)
+	case onFlushErr == nil:
+		return nil, fmt.Errorf("%s: missing flush error handler: %w", op, ErrInvalidParameter)
+	}
+	b := &bufferedFormatterFilter{
+		formatter:  formatter,
+		downstream: downstream,
+		size:       size,
+		onFlushErr: onFlushErr,
+		buffer:     make([]*eventlogger.Event, 0, size),
+		stop:       make(chan struct{}),
+	}
+	if flushInterval > 0 {
+		go b.flushEvery(flushInterval)
+	}
+	return b, nil
+}
+
+// flushEvery flushes the buffer every interval until the node is stopped.
+func (b *bufferedFormatterFilter) flushEvery(interval time.Duration) {
+	const op = "event.(bufferedFormatterFilter).flushEvery"
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			err := b.flush(context.Background())
+			b.mu.Unlock()
+			if err != nil {
+				b.onFlushErr(fmt.Errorf("%s: %w", op, err))
+			}
+		}
+	}
+}
+
+// Reopen flushes the buffered events to the downstream node before reopening
+// the formatter node.
+func (b *bufferedFormatterFilter) Reopen() error {
+	const op = "event.(bufferedFormatterFilter).Reopen"
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flush(context.Background()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := b.formatter.Reopen(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// FlushAll stops flushing at the flush interval and flushes the buffered
+// events to the downstream node.  It's called when the eventer is shutting
+// down.
+func (b *bufferedFormatterFilter) FlushAll(ctx context.Context) error {
+	const op = "event.(bufferedFormatterFilter).FlushAll"
+	b.stopOnce.Do(func() { close(b.stop) })
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flush(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Type describes the type of the node as a Formatter.
+func (_ *bufferedFormatterFilter) Type() eventlogger.NodeType {
+	return eventlogger.NodeTypeFormatterFilter
+}
+
+// Name returns a representation of the bufferedFormatterFilter's name
+func (_ *bufferedFormatterFilter) Name() string {
+	return bufferedNodeName
+}
+
+// Process formats the event with the formatter node and adds it to the
+// buffer.  The buffer is flushed to the downstream node once it's full.
+// Buffered events are sent downstream by the node itself, so Process always
+// returns a nil event to stop the event from continuing down the pipeline.
+// The event has been buffered even if the flush fails, so flush errors are
+// passed to the node's flush error handler rather than returned.
+func (b *bufferedFormatterFilter) Process(ctx context.Context, e *eventlogger.Event) (*eventlogger.Event, error) {
+	const op = "event.(bufferedFormatterFilter).Process"
+	if e == nil {
+		return nil, errors.New("event is nil")
+	}
+	formatted, err := b.formatter.Process(ctx, e)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if formatted == nil {
+		// the formatter discarded the event
+		return nil, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buffer = append(b.buffer, formatted)
+	if len(b.buffer) >= b.size {
+		if err := b.flush(ctx); err != nil {
+			b.onFlushErr(fmt.Errorf("%s: %w", op, err))
+		}
+	}
+	return nil, nil
+}
+
+// flush sends the buffered events to the downstream node in the order they
+// were buffered.  Events which fail to be sent remain in the buffer.  The
+// caller must hold the lock.
+func (b *bufferedFormatterFilter) flush(ctx context.Context) error {
+	const op = "event.(bufferedFormatterFilter).flush"
+	for i, e := range b.buffer {
+		if _, err := b.downstream.Process(ctx, e); err != nil {
+			b.buffer = append(b.buffer[:0], b.buffer[i:]...)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	b.buffer = b.buffer[:0]
+	return nil
+}
@@ -0,0 +1,335 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/eventlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRecordingSink is a sink node which records the events it receives.
+type testRecordingSink struct {
+	mu       sync.Mutex
+	events   []*eventlogger.Event
+	reopened int
+	err      error
+}
+
+func (s *testRecordingSink) Process(_ context.Context, e *eventlogger.Event) (*eventlogger.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.events = append(s.events, e)
+	return nil, nil
+}
+
+func (s *testRecordingSink) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+func (s *testRecordingSink) Reopen() error {
+	s.reopened++
+	return nil
+}
+
+func (s *testRecordingSink) Type() eventlogger.NodeType {
+	return eventlogger.NodeTypeSink
+}
+
+// testIgnoreFlushErr is a flush error handler which ignores the errors.
+func testIgnoreFlushErr(error) {}
+
+func Test_newBufferedFormatterFilter(t *testing.T) {
+	t.Parallel()
+	formatter, err := newHclogFormatterFilter(false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		formatter       eventlogger.Node
+		downstream      eventlogger.Node
+		size            int
+		flushInterval   time.Duration
+		onFlushErr      func(error)
+		wantErrContains string
+	}{
+		{
+			name:            "missing-formatter",
+			downstream:      &testRecordingSink{},
+			size:            1,
+			onFlushErr:      testIgnoreFlushErr,
+			wantErrContains: "missing formatter node",
+		},
+		{
+			name:            "missing-downstream",
+			formatter:       formatter,
+			size:            1,
+			onFlushErr:      testIgnoreFlushErr,
+			wantErrContains: "missing downstream node",
+		},
+		{
+			name:            "zero-size",
+			formatter:       formatter,
+			downstream:      &testRecordingSink{},
+			onFlushErr:      testIgnoreFlushErr,
+			wantErrContains: "buffer size must be greater than zero",
+		},
+		{
+			name:            "negative-flush-interval",
+			formatter:       formatter,
+			downstream:      &testRecordingSink{},
+			size:            1,
+			flushInterval:   -time.Second,
+			onFlushErr:      testIgnoreFlushErr,
+			wantErrContains: "flush interval must not be negative",
+		},
+		{
+			name:            "missing-flush-error-handler",
+			formatter:       formatter,
+			downstream:      &testRecordingSink{},
+			size:            1,
+			wantErrContains: "missing flush error handler",
+		},
+		{
+			name:       "valid",
+			formatter:  formatter,
+			downstream: &testRecordingSink{},
+			size:       10,
+			onFlushErr: testIgnoreFlushErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			got, err := newBufferedFormatterFilter(tt.formatter, tt.downstream, tt.size, tt.flushInterval, tt.onFlushErr)
+			if tt.wantErrContains != "" {
+				require.Error(err)
+				assert.Nil(got)
+				assert.ErrorIs(err, ErrInvalidParameter)
+				assert.Contains(err.Error(), tt.wantErrContains)
+				return
+			}
+			require.NoError(err)
+			require.NotNil(got)
+			assert.Equal(tt.size, got.size)
+			assert.Equal(bufferedNodeName, got.Name())
+			assert.Equal(eventlogger.NodeTypeFormatterFilter, got.Type())
+		})
+	}
+}
+
+func TestBufferedFormatterFilter_Process(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	testEvent := func(id string) *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(SystemType),
+			Payload: &sysEvent{
+				Id:      Id(id),
+				Version: sysVersion,
+				Op:      Op("text"),
+				Data: map[string]interface{}{
+					"msg": "hello",
+				},
+			},
+		}
+	}
+
+	t.Run("flush-when-full", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		formatter, err := newHclogFormatterFilter(false)
+		require.NoError(err)
+		sink := &testRecordingSink{}
+		b, err := newBufferedFormatterFilter(formatter, sink, 3, 0, testIgnoreFlushErr)
+		require.NoError(err)
+
+		for i := 0; i < 2; i++ {
+			e, err := b.Process(ctx, testEvent(fmt.Sprint(i)))
+			require.NoError(err)
+			assert.Nil(e)
+		}
+		assert.Empty(sink.events)
+		assert.Len(b.buffer, 2)
+
+		_, err = b.Process(ctx, testEvent("2"))
+		require.NoError(err)
+		require.Len(sink.events, 3)
+		assert.Empty(b.buffer)
+		for i, e := range sink.events {
+			assert.Equal(Id(fmt.Sprint(i)), e.Payload.(*sysEvent).Id)
+			_, ok := e.Format(string(TextHclogSinkFormat))
+			assert.True(ok)
+		}
+	})
+
+	t.Run("discarded-events-not-buffered", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		formatter, err := newTypeScopedFormatterFilter(false, []Type{ErrorType})
+		require.NoError(err)
+		b, err := newBufferedFormatterFilter(formatter, &testRecordingSink{}, 3, 0, testIgnoreFlushErr)
+		require.NoError(err)
+
+		_, err = b.Process(ctx, testEvent("1"))
+		require.NoError(err)
+		assert.Empty(b.buffer)
+	})
+
+	t.Run("flush-error-reported-separately", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		formatter, err := newHclogFormatterFilter(false)
+		require.NoError(err)
+		sink := &testRecordingSink{err: fmt.Errorf("sink unavailable")}
+		var flushErrs []error
+		b, err := newBufferedFormatterFilter(formatter, sink, 1, 0, func(err error) { flushErrs = append(flushErrs, err) })
+		require.NoError(err)
+
+		// the event was buffered, so the flush error isn't returned
+		_, err = b.Process(ctx, testEvent("1"))
+		require.NoError(err)
+		require.Len(flushErrs, 1)
+		assert.Contains(flushErrs[0].Error(), "sink unavailable")
+		assert.Len(b.buffer, 1)
+	})
+
+	t.Run("nil-event", func(t *testing.T) {
+		require := require.New(t)
+		formatter, err := newHclogFormatterFilter(false)
+		require.NoError(err)
+		b, err := newBufferedFormatterFilter(formatter, &testRecordingSink{}, 3, 0, testIgnoreFlushErr)
+		require.NoError(err)
+		_, err = b.Process(ctx, nil)
+		require.Error(err)
+	})
+}
+
+func TestBufferedFormatterFilter_Reopen(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	testEvent := func(id string) *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(SystemType),
+			Payload: &sysEvent{
+				Id:      Id(id),
+				Version: sysVersion,
+				Op:      Op("text"),
+				Data: map[string]interface{}{
+					"msg": "hello",
+				},
+			},
+		}
+	}
+
+	t.Run("flushes-buffer", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		formatter, err := newHclogFormatterFilter(false)
+		require.NoError(err)
+		sink := &testRecordingSink{}
+		b, err := newBufferedFormatterFilter(formatter, sink, 10, 0, testIgnoreFlushErr)
+		require.NoError(err)
+
+		for i := 0; i < 3; i++ {
+			_, err := b.Process(ctx, testEvent(fmt.Sprint(i)))
+			require.NoError(err)
+		}
+		assert.Empty(sink.events)
+
+		require.NoError(b.Reopen())
+		require.Len(sink.events, 3)
+		assert.Empty(b.buffer)
+		for i, e := range sink.events {
+			assert.Equal(Id(fmt.Sprint(i)), e.Payload.(*sysEvent).Id)
+		}
+
+		// nothing left to flush
+		require.NoError(b.Reopen())
+		assert.Len(sink.events, 3)
+	})
+
+	t.Run("downstream-error-keeps-buffer", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		formatter, err := newHclogFormatterFilter(false)
+		require.NoError(err)
+		sink := &testRecordingSink{err: fmt.Errorf("sink unavailable")}
+		b, err := newBufferedFormatterFilter(formatter, sink, 10, 0, testIgnoreFlushErr)
+		require.NoError(err)
+
+		_, err = b.Process(ctx, testEvent("1"))
+		require.NoError(err)
+
+		err = b.Reopen()
+		require.Error(err)
+		assert.Contains(err.Error(), "sink unavailable")
+		assert.Len(b.buffer, 1)
+
+		sink.err = nil
+		require.NoError(b.Reopen())
+		assert.Len(sink.events, 1)
+		assert.Empty(b.buffer)
+	})
+}
+
+func TestBufferedFormatterFilter_FlushInterval(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	formatter, err := newHclogFormatterFilter(false)
+	require.NoError(err)
+	sink := &testRecordingSink{}
+	b, err := newBufferedFormatterFilter(formatter, sink, 10, 10*time.Millisecond, testIgnoreFlushErr)
+	require.NoError(err)
+	t.Cleanup(func() { _ = b.FlushAll(ctx) })
+
+	_, err = b.Process(ctx, &eventlogger.Event{
+		Type: eventlogger.EventType(SystemType),
+		Payload: &sysEvent{
+			Id:      "1",
+			Version: sysVersion,
+			Op:      Op("text"),
+			Data:    map[string]interface{}{"msg": "hello"},
+		},
+	})
+	require.NoError(err)
+	assert.Eventually(func() bool { return sink.len() == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestBufferedFormatterFilter_FlushAll(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	ctx := context.Background()
+	formatter, err := newHclogFormatterFilter(false)
+	require.NoError(err)
+	sink := &testRecordingSink{}
+	b, err := newBufferedFormatterFilter(formatter, sink, 10, time.Hour, testIgnoreFlushErr)
+	require.NoError(err)
+
+	for i := 0; i < 3; i++ {
+		_, err := b.Process(ctx, &eventlogger.Event{
+			Type: eventlogger.EventType(SystemType),
+			Payload: &sysEvent{
+				Id:      Id(fmt.Sprint(i)),
+				Version: sysVersion,
+				Op:      Op("text"),
+				Data:    map[string]interface{}{"msg": "hello"},
+			},
+		})
+		require.NoError(err)
+	}
+	assert.Equal(0, sink.len())
+
+	require.NoError(b.FlushAll(ctx))
+	assert.Equal(3, sink.len())
+	assert.Empty(b.buffer)
+
+	// FlushAll can be called again after the interval flushing is stopped
+	require.NoError(b.FlushAll(ctx))
+	assert.Equal(3, sink.len())
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/boundary/globals"
@@ -14,11 +15,17 @@ import (
 
 type key int
 
+// ContextKey is a context key whose value can be added to hclog formatted
+// events.  See WithContextFields.
+type ContextKey string
+
 const cancelledSendTimeout = 3 * time.Second
 
 const (
 	eventerKey key = iota
 	requestInfoKey
+	callerKey
+	requestScopeKey
 )
 
 // NewEventerContext will return a context containing a value of the provided Eventer
@@ -62,15 +69,72 @@ func NewRequestInfoContext(ctx context.Context, info *RequestInfo) (context.Cont
 }
 
 // RequestInfoFromContext attempts to get the RequestInfo value from the context
-// provided
+// provided.  If the scope of the request has been set with SetRequestScope, a
+// copy of the RequestInfo with the scope id is returned.
 func RequestInfoFromContext(ctx context.Context) (*RequestInfo, bool) {
 	if ctx == nil {
 		return nil, false
 	}
 	reqInfo, ok := ctx.Value(requestInfoKey).(*RequestInfo)
+	if !ok || reqInfo == nil || reqInfo.ScopeId != "" {
+		return reqInfo, ok
+	}
+	if scope, ok := ctx.Value(requestScopeKey).(*requestScope); ok {
+		if id := scope.get(); id != "" {
+			scoped := *reqInfo
+			scoped.ScopeId = id
+			return &scoped, true
+		}
+	}
 	return reqInfo, ok
 }
 
+// requestScope holds the scope id of a request once it's been resolved.  The
+// RequestInfo of a request is shared by every event of the request, so the
+// scope id is held separately rather than set on the RequestInfo.
+type requestScope struct {
+	mu sync.RWMutex
+	id string
+}
+
+func (s *requestScope) get() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+// NewRequestScopeContext will return a context which can hold the scope of a
+// request.  The scope is set with SetRequestScope once it's been resolved,
+// and events written with the context afterwards include the scope id in
+// their RequestInfo.
+func NewRequestScopeContext(ctx context.Context) (context.Context, error) {
+	const op = "event.NewRequestScopeContext"
+	if ctx == nil {
+		return nil, fmt.Errorf("%s: missing context: %w", op, ErrInvalidParameter)
+	}
+	return context.WithValue(ctx, requestScopeKey, &requestScope{}), nil
+}
+
+// SetRequestScope sets the scope id of the request of the context, which
+// must have been created with NewRequestScopeContext.
+func SetRequestScope(ctx context.Context, scopeId string) error {
+	const op = "event.SetRequestScope"
+	if ctx == nil {
+		return fmt.Errorf("%s: missing context: %w", op, ErrInvalidParameter)
+	}
+	if scopeId == "" {
+		return fmt.Errorf("%s: missing scope id: %w", op, ErrInvalidParameter)
+	}
+	scope, ok := ctx.Value(requestScopeKey).(*requestScope)
+	if !ok {
+		return fmt.Errorf("%s: context has no request scope: %w", op, ErrInvalidParameter)
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.id = scopeId
+	return nil
+}
+
 // WriteObservation will write an observation event.  It will first check the
 // ctx for an eventer, then try event.SysEventer() and if no eventer can be
 // found an error is returned.
@@ -335,6 +399,57 @@ func newSendCtx(ctx context.Context) (context.Context, context.CancelFunc) {
 	return sendCtx, sendCancel
 }
 
+// callerSkipPrefixes are the packages whose frames are skipped when finding
+// the location that emitted an event.
+var callerSkipPrefixes = []string{
+	"github.com/hashicorp/boundary/internal/observability/event.",
+	"github.com/hashicorp/boundary/internal/errors.",
+	"github.com/hashicorp/eventlogger",
+}
+
+// maxCallerDepth is the max number of frames searched for the location that
+// emitted an event.
+const maxCallerDepth = 20
+
+// newCallerContext will return a context containing the file:line location
+// that emitted an event.  The eventlogger broker processes events in its own
+// go routine, so the location must be found before the event is sent.
+func newCallerContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return ctx
+	}
+	for i := 1; i < maxCallerDepth; i++ {
+		pc, file, line, ok := runtime.Caller(i)
+		if !ok {
+			break
+		}
+		if details := runtime.FuncForPC(pc); details != nil && hasCallerSkipPrefix(details.Name()) {
+			continue
+		}
+		return context.WithValue(ctx, callerKey, fmt.Sprintf("%s:%d", file, line))
+	}
+	return ctx
+}
+
+// callerFromContext attempts to get the caller location from the context
+// provided
+func callerFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	caller, ok := ctx.Value(callerKey).(string)
+	return caller, ok
+}
+
+func hasCallerSkipPrefix(funcName string) bool {
+	for _, p := range callerSkipPrefixes {
+		if strings.HasPrefix(funcName, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // MissingKey defines a key to be used as the "missing key" when ConvertArgs has
 // an odd number of args (it's missing a key in its key/value pairs)
 const MissingKey = "EXTRA_VALUE_AT_END"
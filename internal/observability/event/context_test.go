@@ -153,6 +153,37 @@ func Test_RequestInfoFromContext(t *testing.T) {
 	}
 }
 
+func Test_SetRequestScope(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+	testInfo := event.TestRequestInfo(t)
+
+	_, err := event.NewRequestScopeContext(nil)
+	assert.ErrorIs(err, event.ErrInvalidParameter)
+
+	infoCtx, err := event.NewRequestInfoContext(context.Background(), testInfo)
+	require.NoError(err)
+	assert.ErrorIs(event.SetRequestScope(infoCtx, "global"), event.ErrInvalidParameter, "the context has no request scope")
+
+	scopeCtx, err := event.NewRequestScopeContext(infoCtx)
+	require.NoError(err)
+	assert.ErrorIs(event.SetRequestScope(scopeCtx, ""), event.ErrInvalidParameter)
+
+	got, ok := event.RequestInfoFromContext(scopeCtx)
+	require.True(ok)
+	assert.Same(testInfo, got, "the request info should not be copied before the scope is set")
+
+	// contexts derived from the scope context see the scope once it's set
+	derivedCtx, cancel := context.WithCancel(scopeCtx)
+	defer cancel()
+	require.NoError(event.SetRequestScope(scopeCtx, "o_1234567890"))
+	got, ok = event.RequestInfoFromContext(derivedCtx)
+	require.True(ok)
+	assert.Equal("o_1234567890", got.ScopeId)
+	assert.Equal(testInfo.Id, got.Id)
+	assert.Empty(testInfo.ScopeId, "the shared request info must not be changed")
+}
+
 func Test_NewEventerContext(t *testing.T) {
 	testSetup := event.TestEventerConfig(t, "Test_NewEventerContext")
 	testLock := &sync.Mutex{}
@@ -1132,6 +1163,48 @@ func Test_WriteError(t *testing.T) {
 	}
 }
 
+func Test_WriteErrorWithCaller(t *testing.T) {
+	event.TestEnableEventing(t, true)
+
+	tmpFile, err := ioutil.TempFile("./", "tmp-caller-Test_WriteErrorWithCaller")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+
+	c := event.EventerConfig{
+		Sinks: []*event.SinkConfig{
+			{
+				Name:          "caller-file-sink",
+				Type:          event.FileSink,
+				EventTypes:    []event.Type{event.ErrorType},
+				Format:        event.TextHclogSinkFormat,
+				IncludeCaller: true,
+				FileConfig: &event.FileSinkTypeConfig{
+					Path:     "./",
+					FileName: tmpFile.Name(),
+				},
+			},
+		},
+	}
+	testLock := &sync.Mutex{}
+	testLogger := hclog.New(&hclog.LoggerOptions{
+		Mutex: testLock,
+		Name:  "test",
+	})
+	e, err := event.NewEventer(testLogger, testLock, "Test_WriteErrorWithCaller", c)
+	require.NoError(t, err)
+	testCtx, err := event.NewEventerContext(context.Background(), e)
+	require.NoError(t, err)
+
+	event.WriteError(testCtx, "Test_WriteErrorWithCaller", &fakeError{Msg: "test", Code: "code"})
+
+	b, err := ioutil.ReadFile(tmpFile.Name())
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "caller=")
+	// the caller should point into this package, rather than into the event
+	// or eventlogger packages which are skipped.
+	assert.Contains(t, string(b), "context_test.go:")
+}
+
 type fakeError struct {
 	Code string
 	Msg  string
@@ -66,6 +66,10 @@ type Eventer struct {
 	auditPipelines       []pipeline
 	observationPipelines []pipeline
 	errPipelines         []pipeline
+
+	// includeCaller is true when at least one sink includes the location
+	// that emitted an event.
+	includeCaller bool
 }
 
 type pipeline struct {
@@ -193,14 +197,15 @@ func NewEventer(log hclog.Logger, serializationLock *sync.Mutex, serverName stri
 	// reused.
 	allSinkFilenames := map[string]bool{}
 
+	var bufferedNodes []flushable
+
 	for _, s := range c.Sinks {
 		fmtId, fmtNode, err := newFmtFilterNode(serverName, *s)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		err = e.broker.RegisterNode(eventlogger.NodeID(fmtId), fmtNode)
-		if err != nil {
-			return nil, fmt.Errorf("%s: unable to register fmt/filter node: %w", op, err)
+		if s.IncludeCaller && (s.Format == TextHclogSinkFormat || s.Format == JSONHclogSinkFormat) {
+			e.includeCaller = true
 		}
 
 		var sinkId eventlogger.NodeID
@@ -242,6 +247,25 @@ func NewEventer(log hclog.Logger, serializationLock *sync.Mutex, serverName stri
 		if err != nil {
 			return nil, fmt.Errorf("%s: failed to register sink node %s: %w", op, sinkId, err)
 		}
+		if s.BufferSize > 0 {
+			// the buffered node writes the formatted events to the sink
+			// itself, so its flush errors can't be returned from the
+			// pipeline and are logged instead
+			sinkName := s.Name
+			onFlushErr := func(err error) {
+				log.Error("unable to write buffered events to sink", "sink", sinkName, "error", err.Error())
+			}
+			bufferedNode, err := newBufferedFormatterFilter(fmtNode, sinkNode, s.BufferSize, s.BufferFlushInterval, onFlushErr)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			bufferedNodes = append(bufferedNodes, bufferedNode)
+			fmtNode = bufferedNode
+		}
+		err = e.broker.RegisterNode(eventlogger.NodeID(fmtId), fmtNode)
+		if err != nil {
+			return nil, fmt.Errorf("%s: unable to register fmt/filter node: %w", op, err)
+		}
 		var addToAudit, addToObservation, addToErr, addToSys bool
 		for _, t := range s.EventTypes {
 			switch t {
@@ -446,6 +470,10 @@ func NewEventer(log hclog.Logger, serializationLock *sync.Mutex, serverName stri
 	e.errPipelines = append(e.errPipelines, errPipelines...)
 	e.observationPipelines = append(e.observationPipelines, observationPipelines...)
 
+	// the buffered nodes are flushed after the gated nodes, since flushing
+	// a gated node sends its events to the buffered nodes
+	e.flushableNodes = append(e.flushableNodes, bufferedNodes...)
+
 	return e, nil
 }
 
@@ -464,7 +492,7 @@ func newFmtFilterNode(serverName string, c SinkConfig) (eventlogger.NodeID, even
 		}
 		fmtId = eventlogger.NodeID(id)
 
-		fmtNode, err = newHclogFormatterFilter(c.Format == JSONHclogSinkFormat, WithAllow(c.AllowFilters...), WithDeny(c.DenyFilters...))
+		fmtNode, err = newTypeScopedFormatterFilter(c.Format == JSONHclogSinkFormat, c.EventTypes, WithAllow(c.AllowFilters...), WithDeny(c.DenyFilters...), WithScopeAllow(c.ScopeAllow...), WithScopeDeny(c.ScopeDeny...), WithIncludeCaller(c.IncludeCaller), WithUnknownTypePolicy(c.UnknownTypePolicy))
 		if err != nil {
 			return "", nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -525,6 +553,9 @@ func (e *Eventer) writeObservation(ctx context.Context, event *observation) erro
 	if !e.conf.ObservationsEnabled {
 		return nil
 	}
+	if e.includeCaller {
+		ctx = newCallerContext(ctx)
+	}
 	err := e.retrySend(ctx, stdRetryCount, expBackoff{}, func() (eventlogger.Status, error) {
 		if event.Header != nil {
 			event.Header[RequestInfoField] = event.RequestInfo
@@ -548,6 +579,9 @@ func (e *Eventer) writeError(ctx context.Context, event *err) error {
 	if event == nil {
 		return fmt.Errorf("%s: missing event: %w", op, ErrInvalidParameter)
 	}
+	if e.includeCaller {
+		ctx = newCallerContext(ctx)
+	}
 	err := e.retrySend(ctx, stdRetryCount, expBackoff{}, func() (eventlogger.Status, error) {
 		return e.broker.Send(ctx, eventlogger.EventType(ErrorType), event)
 	})
@@ -564,6 +598,9 @@ func (e *Eventer) writeSysEvent(ctx context.Context, event *sysEvent) error {
 	if event == nil {
 		return fmt.Errorf("%s: missing event: %w", op, ErrInvalidParameter)
 	}
+	if e.includeCaller {
+		ctx = newCallerContext(ctx)
+	}
 	err := e.retrySend(ctx, stdRetryCount, expBackoff{}, func() (eventlogger.Status, error) {
 		return e.broker.Send(ctx, eventlogger.EventType(SystemType), event)
 	})
@@ -583,6 +620,9 @@ func (e *Eventer) writeAudit(ctx context.Context, event *audit) error {
 	if !e.conf.AuditEnabled {
 		return nil
 	}
+	if e.includeCaller {
+		ctx = newCallerContext(ctx)
+	}
 	err := e.retrySend(ctx, stdRetryCount, expBackoff{}, func() (eventlogger.Status, error) {
 		return e.broker.Send(ctx, eventlogger.EventType(AuditType), event)
 	})
@@ -826,6 +826,49 @@ func TestEventer_FlushNodes(t *testing.T) {
 		require.Error(e.FlushNodes(context.Background()))
 		assert.True(node.flushed)
 	})
+	t.Run("buffered-sink", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		testLock := &sync.Mutex{}
+		testLogger := hclog.New(&hclog.LoggerOptions{
+			Mutex: testLock,
+			Name:  "test",
+		})
+		tmpFile, err := ioutil.TempFile("./", "tmp-buffered-TestEventer_FlushNodes")
+		require.NoError(err)
+		t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+
+		e, err := NewEventer(testLogger, testLock, "TestEventer_FlushNodes", EventerConfig{
+			Sinks: []*SinkConfig{
+				{
+					Name:       "buffered-file-sink",
+					Type:       FileSink,
+					EventTypes: []Type{SystemType},
+					Format:     JSONSinkFormat,
+					FileConfig: &FileSinkTypeConfig{
+						Path:     "./",
+						FileName: tmpFile.Name(),
+					},
+					BufferSize: 10,
+				},
+			},
+		})
+		require.NoError(err)
+
+		require.NoError(e.writeSysEvent(context.Background(), &sysEvent{
+			Id:      "buffered",
+			Version: sysVersion,
+			Op:      Op("TestEventer_FlushNodes"),
+			Data:    map[string]interface{}{"msg": "buffered"},
+		}))
+		b, err := ioutil.ReadFile(tmpFile.Name())
+		require.NoError(err)
+		assert.Empty(b)
+
+		require.NoError(e.FlushNodes(context.Background()))
+		b, err = ioutil.ReadFile(tmpFile.Name())
+		require.NoError(err)
+		assert.Contains(string(b), "buffered")
+	})
 }
 
 type testFlushNode struct {
@@ -1184,3 +1227,58 @@ func Test_logAdapter_Write(t *testing.T) {
 		})
 	}
 }
+
+func Test_newFmtFilterNode(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	observationEvent := func() *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(ObservationType),
+			Payload: map[string]interface{}{
+				"id":      "1",
+				"version": observationVersion,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		eventTypes  []Type
+		wantDropped bool
+	}{
+		{
+			name:       "every-type",
+			eventTypes: []Type{EveryType},
+		},
+		{
+			name:       "observation",
+			eventTypes: []Type{ObservationType},
+		},
+		{
+			name:        "error-only",
+			eventTypes:  []Type{ErrorType},
+			wantDropped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			_, n, err := newFmtFilterNode("test-server", SinkConfig{
+				Name:       "test-sink",
+				Type:       StderrSink,
+				Format:     TextHclogSinkFormat,
+				EventTypes: tt.eventTypes,
+			})
+			require.NoError(err)
+			f, ok := n.(*hclogFormatterFilter)
+			require.True(ok)
+			got, err := f.Process(ctx, observationEvent())
+			require.NoError(err)
+			if tt.wantDropped {
+				assert.Nil(got)
+				return
+			}
+			assert.NotNil(got)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/fatih/structs"
 	"github.com/hashicorp/eventlogger"
@@ -17,9 +18,15 @@ const (
 	errorFields      = "ErrorFields"
 	requestInfoField = "RequestInfo"
 	wrappedField     = "Wrapped"
+	errorChainField  = "error_chain"
+	callerField      = "caller"
 	hclogNodeName    = "hclog-formatter-filter"
 )
 
+// SizeObserver is called with the type and the size in bytes of each event
+// formatted by an hclog formatter.
+type SizeObserver func(t Type, size int)
+
 // hclogFormatterFilter will format a boundary event an an hclog entry.
 type hclogFormatterFilter struct {
 	// jsonFormat allows you to specify that the hclog entry should be in JSON
@@ -28,14 +35,49 @@ type hclogFormatterFilter struct {
 	predicate  func(ctx context.Context, i interface{}) (bool, error)
 	allow      []*filter
 	deny       []*filter
+	// scopeAllow and scopeDeny optionally filter events by the scope id of
+	// their RequestInfo.  They're applied in addition to the allow and deny
+	// filters.
+	scopeAllow map[string]bool
+	scopeDeny  map[string]bool
+	// includeCaller allows you to specify that the hclog entry should include
+	// the file:line location that emitted the event.
+	includeCaller bool
+	// contextFields are the context keys whose values are added to the hclog
+	// entry when they're found in the context of the event.
+	contextFields []ContextKey
+	// eventTypes optionally scopes the formatter to a set of event types.
+	// Events of any other type are discarded.  If it's empty, then events of
+	// every type are kept.
+	eventTypes map[Type]bool
+	// explainLogger optionally logs why an event was dropped by the filters
+	// of the node.  It's nil unless WithExplainFilters is set.
+	explainLogger hclog.Logger
+	// sizeObserver optionally receives the size of each formatted event.
+	// It's nil unless WithSizeObserver is set.
+	sizeObserver SizeObserver
+	// unknownTypePolicy defines how events of an unknown type are handled.
+	// They're an error unless it's SkipUnknownTypePolicy or
+	// PassthroughUnknownTypePolicy.
+	unknownTypePolicy UnknownTypePolicy
 }
 
 func newHclogFormatterFilter(jsonFormat bool, opt ...Option) (*hclogFormatterFilter, error) {
 	const op = "event.NewHclogFormatter"
+	opts := getOpts(opt...)
 	n := hclogFormatterFilter{
-		jsonFormat: jsonFormat,
+		jsonFormat:    jsonFormat,
+		includeCaller: opts.withIncludeCaller,
+		contextFields: opts.withContextFields,
+		sizeObserver:  opts.withSizeObserver,
+	}
+	if err := opts.withUnknownTypePolicy.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	n.unknownTypePolicy = opts.withUnknownTypePolicy
+	if opts.withExplainFilters {
+		n.explainLogger = hclog.Default().Named(hclogNodeName)
 	}
-	opts := getOpts(opt...)
 	// intentionally not checking if allow and/or deny optional filters were
 	// supplied since having a filter node with no filters is okay.
 
@@ -61,9 +103,57 @@ func newHclogFormatterFilter(jsonFormat bool, opt ...Option) (*hclogFormatterFil
 	}
 	n.predicate = newPredicate(n.allow, n.deny)
 
+	if len(opts.withScopeAllow) > 0 {
+		n.scopeAllow = make(map[string]bool, len(opts.withScopeAllow))
+		for _, id := range opts.withScopeAllow {
+			if id == "" {
+				return nil, fmt.Errorf("%s: missing allow scope id: %w", op, ErrInvalidParameter)
+			}
+			n.scopeAllow[id] = true
+		}
+	}
+	if len(opts.withScopeDeny) > 0 {
+		n.scopeDeny = make(map[string]bool, len(opts.withScopeDeny))
+		for _, id := range opts.withScopeDeny {
+			if id == "" {
+				return nil, fmt.Errorf("%s: missing deny scope id: %w", op, ErrInvalidParameter)
+			}
+			n.scopeDeny[id] = true
+		}
+	}
+
 	return &n, nil
 }
 
+// newTypeScopedFormatterFilter returns an hclog formatter that only keeps
+// events of the given types, which allows multiple formatter nodes to route
+// types to different sinks.  The opts are the same as the options of
+// newHclogFormatterFilter.
+func newTypeScopedFormatterFilter(jsonFormat bool, types []Type, opt ...Option) (*hclogFormatterFilter, error) {
+	const op = "event.newTypeScopedFormatterFilter"
+	if len(types) == 0 {
+		return nil, fmt.Errorf("%s: missing event types: %w", op, ErrInvalidParameter)
+	}
+	eventTypes := make(map[Type]bool, len(types))
+	for _, t := range types {
+		if err := t.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if t == EveryType {
+			// every type is kept, so there's no need to scope the formatter
+			eventTypes = nil
+			break
+		}
+		eventTypes[t] = true
+	}
+	n, err := newHclogFormatterFilter(jsonFormat, opt...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	n.eventTypes = eventTypes
+	return n, nil
+}
+
 // Reopen is a no op
 func (_ *hclogFormatterFilter) Reopen() error { return nil }
 
@@ -89,6 +179,11 @@ func (f *hclogFormatterFilter) Process(ctx context.Context, e *eventlogger.Event
 		return nil, errors.New("event is nil")
 	}
 
+	if len(f.eventTypes) > 0 && !f.eventTypes[Type(e.Type)] {
+		// Return nil to signal that the event should be discarded.
+		return nil, nil
+	}
+
 	if f.predicate != nil {
 		// Use the predicate to see if we want to keep the event using it's
 		// formatted struct as a parmeter to the predicate.
@@ -97,19 +192,58 @@ func (f *hclogFormatterFilter) Process(ctx context.Context, e *eventlogger.Event
 			return nil, fmt.Errorf("%s: unable to filter: %w", op, err)
 		}
 		if !keep {
+			f.explainDrop(ctx, e)
 			// Return nil to signal that the event should be discarded.
 			return nil, nil
 		}
 	}
 
+	if !f.keepScope(ctx, e.Payload) {
+		f.explainDrop(ctx, e)
+		// Return nil to signal that the event should be discarded.
+		return nil, nil
+	}
+
 	var m map[string]interface{}
 	switch string(e.Type) {
 	case string(ErrorType), string(AuditType), string(SystemType):
-		m = structs.Map(e.Payload)
+		var err error
+		if m, err = payloadMap(e.Payload); err != nil {
+			return nil, fmt.Errorf("%s: unable to format %s event: %w", op, e.Type, err)
+		}
 	case string(ObservationType):
-		m = e.Payload.(map[string]interface{})
+		var ok bool
+		if m, ok = e.Payload.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("%s: unable to format %s event: payload is %T, not a map: %w", op, e.Type, e.Payload, ErrInvalidParameter)
+		}
 	default:
-		return nil, fmt.Errorf("%s: unknown event type %s", op, e.Type)
+		switch f.unknownTypePolicy {
+		case SkipUnknownTypePolicy:
+			// Return nil to signal that the event should be discarded.
+			return nil, nil
+		case PassthroughUnknownTypePolicy:
+			var ok bool
+			if m, ok = e.Payload.(map[string]interface{}); !ok {
+				var err error
+				if m, err = payloadMap(e.Payload); err != nil {
+					return nil, fmt.Errorf("%s: unable to format %s event: %w", op, e.Type, err)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%s: unknown event type %s", op, e.Type)
+		}
+	}
+	if string(e.Type) == string(ErrorType) {
+		// the wrapped error chain is flattened into a single ordered list of
+		// messages, so we don't want to emit just the top wrapped value.
+		if fields, ok := m[errorFields].(map[string]interface{}); ok {
+			delete(fields, wrappedField)
+		}
+		if errEvent, ok := e.Payload.(*err); ok {
+			if chain := errorChain(errEvent.ErrorFields); len(chain) > 0 {
+				m[errorChainField] = chain
+			}
+		}
 	}
 
 	args := make([]interface{}, 0, len(m))
@@ -153,6 +287,12 @@ func (f *hclogFormatterFilter) Process(ctx context.Context, e *eventlogger.Event
 		}
 		args = append(args, k, v)
 	}
+	if f.includeCaller {
+		if caller, ok := callerFromContext(ctx); ok {
+			args = append(args, callerField, caller)
+		}
+	}
+	args = append(args, contextFieldArgs(ctx, f.contextFields)...)
 
 	var buf bytes.Buffer
 	logger := hclog.New(&hclog.LoggerOptions{
@@ -167,9 +307,9 @@ func (f *hclogFormatterFilter) Process(ctx context.Context, e *eventlogger.Event
 	case string(ObservationType), string(SystemType), string(AuditType):
 		logger.Info(string(e.Type)+eventMarker, args...)
 	default:
-		// well, we should ever hit this, since we should be specific about the
-		// event type we're processing, but adding this default to just be sure
-		// we haven't missed anything.
+		// events of an unknown type only reach this with a
+		// PassthroughUnknownTypePolicy, since we should be specific about the
+		// event type we're processing.
 		logger.Trace(string(e.Type)+eventMarker, args...)
 	}
 	switch f.jsonFormat {
@@ -178,6 +318,156 @@ func (f *hclogFormatterFilter) Process(ctx context.Context, e *eventlogger.Event
 	case false:
 		e.FormattedAs(string(TextHclogSinkFormat), buf.Bytes())
 	}
+	if f.sizeObserver != nil {
+		f.sizeObserver(Type(e.Type), buf.Len())
+	}
 
 	return e, nil
 }
+
+// payloadMap returns the fields of the struct payload as a map.  structs.Map
+// panics when the payload isn't a struct, or a pointer to one, so the panic is
+// recovered and returned as an error rather than crashing the event pipeline.
+func payloadMap(payload interface{}) (m map[string]interface{}, err error) {
+	const op = "event.payloadMap"
+	defer func() {
+		if r := recover(); r != nil {
+			m = nil
+			err = fmt.Errorf("%s: payload is %T, not a struct: %v: %w", op, payload, r, ErrInvalidParameter)
+		}
+	}()
+	return structs.Map(payload), nil
+}
+
+// keepScope returns true if the scope filters of the node keep an event with
+// the given payload.  When there are allow scopes, events without a scope id
+// are discarded.
+func (f *hclogFormatterFilter) keepScope(ctx context.Context, payload interface{}) bool {
+	if len(f.scopeAllow) == 0 && len(f.scopeDeny) == 0 {
+		return true
+	}
+	scopeId := requestInfoScopeId(ctx, payload)
+	if f.scopeDeny[scopeId] {
+		return false
+	}
+	if len(f.scopeAllow) > 0 {
+		return f.scopeAllow[scopeId]
+	}
+	return true
+}
+
+// explainDrop logs why the filters of the node dropped the event when the
+// node has an explainLogger.  The filters are evaluated again to find the
+// reason, so it should only be enabled while debugging.
+func (f *hclogFormatterFilter) explainDrop(ctx context.Context, e *eventlogger.Event) {
+	if f.explainLogger == nil {
+		return
+	}
+	args := []interface{}{"event_type", string(e.Type)}
+	args = append(args, f.dropReason(ctx, e.Payload)...)
+	f.explainLogger.Debug("event dropped by filter", args...)
+}
+
+// dropReason returns the key/value args describing the first filter which
+// drops an event with the given payload.  Deny filters are checked before
+// allow filters, in the same order as the node applies them.
+func (f *hclogFormatterFilter) dropReason(ctx context.Context, payload interface{}) []interface{} {
+	for _, d := range f.deny {
+		if d.Match(payload) {
+			return []interface{}{"reason", "deny filter matched", "filter", d.raw}
+		}
+	}
+	if len(f.allow) > 0 {
+		allowed := false
+		for _, a := range f.allow {
+			if a.Match(payload) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return []interface{}{"reason", "no allow filter matched"}
+		}
+	}
+	scopeId := requestInfoScopeId(ctx, payload)
+	switch {
+	case f.scopeDeny[scopeId]:
+		return []interface{}{"reason", "deny scope matched", "scope_id", scopeId}
+	case len(f.scopeAllow) > 0 && !f.scopeAllow[scopeId]:
+		return []interface{}{"reason", "no allow scope matched", "scope_id", scopeId}
+	}
+	return []interface{}{"reason", "unknown"}
+}
+
+// requestInfoScopeId returns the scope id of the RequestInfo of the event
+// payload.  If the payload doesn't have a RequestInfo, the RequestInfo from
+// the ctx is used.
+func requestInfoScopeId(ctx context.Context, payload interface{}) string {
+	var info *RequestInfo
+	switch p := payload.(type) {
+	case *err:
+		info = p.RequestInfo
+	case *audit:
+		info = p.RequestInfo
+	case *observation:
+		info = p.RequestInfo
+	}
+	if info == nil {
+		info, _ = RequestInfoFromContext(ctx)
+	}
+	if info == nil {
+		return ""
+	}
+	return info.ScopeId
+}
+
+// contextFieldArgs returns the key/value args for each of the keys found in
+// ctx.  Keys which are not found in ctx are skipped.
+func contextFieldArgs(ctx context.Context, keys []ContextKey) []interface{} {
+	if ctx == nil || len(keys) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(keys)*2)
+	for _, k := range keys {
+		if v := ctx.Value(k); v != nil {
+			args = append(args, string(k), v)
+		}
+	}
+	return args
+}
+
+// errorChain returns the message of each layer of e, starting with e itself
+// and ending with the root cause.  The message of a layer doesn't include the
+// message of the error it wraps, so for an *errors.Err it's just the layer's
+// Op, Msg and Code.  Layers which don't add a message of their own are
+// skipped.  It returns nil when e is nil or doesn't wrap an error.
+func errorChain(e error) []string {
+	if e == nil || errors.Unwrap(e) == nil {
+		return nil
+	}
+	var chain []string
+	for ; e != nil; e = errors.Unwrap(e) {
+		msg := e.Error()
+		if w := errors.Unwrap(e); w != nil {
+			msg = layerMessage(msg, w.Error())
+		}
+		if msg != "" {
+			chain = append(chain, msg)
+		}
+	}
+	return chain
+}
+
+// layerMessage returns msg, the message of an error, without wrappedMsg, the
+// message of the error it wraps.  msg is returned unchanged if it doesn't end
+// with wrappedMsg.
+func layerMessage(msg, wrappedMsg string) string {
+	switch {
+	case strings.HasSuffix(msg, ": "+wrappedMsg):
+		return strings.TrimSuffix(msg, ": "+wrappedMsg)
+	case strings.HasSuffix(msg, wrappedMsg):
+		return strings.TrimSuffix(msg, wrappedMsg)
+	default:
+		return msg
+	}
+}
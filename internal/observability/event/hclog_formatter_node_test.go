@@ -1,10 +1,13 @@
 package event
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/eventlogger"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -40,6 +43,38 @@ func TestHclogFormatter_Process(t *testing.T) {
 			e:               &eventlogger.Event{Type: eventlogger.EventType("invalid-type")},
 			wantErrContains: "unknown event type invalid-type",
 		},
+		{
+			name: "audit-payload-not-a-struct",
+			formatter: &hclogFormatterFilter{
+				jsonFormat: false,
+			},
+			e: &eventlogger.Event{
+				Type:    eventlogger.EventType(AuditType),
+				Payload: "not-a-struct",
+			},
+			wantErrContains: "unable to format audit event: event.payloadMap: payload is string, not a struct",
+		},
+		{
+			name: "audit-nil-payload",
+			formatter: &hclogFormatterFilter{
+				jsonFormat: true,
+			},
+			e: &eventlogger.Event{
+				Type: eventlogger.EventType(AuditType),
+			},
+			wantErrContains: "payload is <nil>, not a struct",
+		},
+		{
+			name: "observation-payload-not-a-map",
+			formatter: &hclogFormatterFilter{
+				jsonFormat: false,
+			},
+			e: &eventlogger.Event{
+				Type:    eventlogger.EventType(ObservationType),
+				Payload: []string{"not-a-map"},
+			},
+			wantErrContains: "payload is []string, not a map",
+		},
 		{
 			name: "sys-text",
 			formatter: &hclogFormatterFilter{
@@ -172,6 +207,47 @@ func TestHclogFormatter_Process(t *testing.T) {
 				"Op=text",
 			},
 		},
+		{
+			name: "err-json-with-wrapped-chain",
+			formatter: &hclogFormatterFilter{
+				jsonFormat: true,
+			},
+			e: &eventlogger.Event{
+				Type: eventlogger.EventType(ErrorType),
+				Payload: &err{
+					Id:          "1",
+					Version:     errorVersion,
+					Error:       "top: middle: invalid parameter",
+					ErrorFields: fmt.Errorf("top: %w", fmt.Errorf("middle: %w", ErrInvalidParameter)),
+					Op:          Op("text"),
+				},
+			},
+			want: []string{
+				"{\"@level\":\"error\",\"@message\":\"error event\"",
+				"\"Error\":\"top: middle: invalid parameter\"",
+				"\"error_chain\":[\"top\",\"middle\",\"invalid parameter\"]",
+			},
+		},
+		{
+			name: "err-text-with-wrapped-chain",
+			formatter: &hclogFormatterFilter{
+				jsonFormat: false,
+			},
+			e: &eventlogger.Event{
+				Type: eventlogger.EventType(ErrorType),
+				Payload: &err{
+					Id:          "1",
+					Version:     errorVersion,
+					Error:       "top: middle: invalid parameter",
+					ErrorFields: fmt.Errorf("top: %w", fmt.Errorf("middle: %w", ErrInvalidParameter)),
+					Op:          Op("text"),
+				},
+			},
+			want: []string{
+				"[ERROR] error event:",
+				"error_chain=[\"top\", \"middle\", \"invalid parameter\"]",
+			},
+		},
 		{
 			name: "filter-match",
 			formatter: &hclogFormatterFilter{
@@ -248,6 +324,633 @@ func TestHclogFormatter_Process(t *testing.T) {
 	}
 }
 
+func TestHclogFormatter_ProcessWithCaller(t *testing.T) {
+	t.Parallel()
+	testEvent := func() *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(SystemType),
+			Payload: &sysEvent{
+				Id:      "1",
+				Version: sysVersion,
+				Op:      Op("text"),
+				Data: map[string]interface{}{
+					"msg": "hello",
+				},
+			},
+		}
+	}
+	callerCtx := context.WithValue(context.Background(), callerKey, "emitter.go:42")
+
+	tests := []struct {
+		name          string
+		includeCaller bool
+		ctx           context.Context
+		wantCaller    bool
+	}{
+		{
+			name:          "include-caller",
+			includeCaller: true,
+			ctx:           callerCtx,
+			wantCaller:    true,
+		},
+		{
+			name:          "include-caller-missing-from-ctx",
+			includeCaller: true,
+			ctx:           context.Background(),
+		},
+		{
+			name: "default",
+			ctx:  callerCtx,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			f, err := newHclogFormatterFilter(false, WithIncludeCaller(tt.includeCaller))
+			require.NoError(err)
+			e, err := f.Process(tt.ctx, testEvent())
+			require.NoError(err)
+			require.NotNil(e)
+			b, ok := e.Format(string(TextHclogSinkFormat))
+			require.True(ok)
+			if tt.wantCaller {
+				assert.Contains(string(b), "caller=emitter.go:42")
+				return
+			}
+			assert.NotContains(string(b), "caller=")
+		})
+	}
+}
+
+func TestHclogFormatter_ProcessWithSizeObserver(t *testing.T) {
+	t.Parallel()
+	testEvent := func() *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(SystemType),
+			Payload: &sysEvent{
+				Id:      "1",
+				Version: sysVersion,
+				Op:      Op("text"),
+				Data: map[string]interface{}{
+					"msg": "hello",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		jsonFormat bool
+		format     SinkFormat
+	}{
+		{
+			name:   "text",
+			format: TextHclogSinkFormat,
+		},
+		{
+			name:       "json",
+			jsonFormat: true,
+			format:     JSONHclogSinkFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			var gotType Type
+			var gotSize, calls int
+			observer := func(typ Type, size int) {
+				calls++
+				gotType = typ
+				gotSize = size
+			}
+			f, err := newHclogFormatterFilter(tt.jsonFormat, WithSizeObserver(observer))
+			require.NoError(err)
+			e, err := f.Process(context.Background(), testEvent())
+			require.NoError(err)
+			require.NotNil(e)
+			b, ok := e.Format(string(tt.format))
+			require.True(ok)
+			assert.Equal(1, calls)
+			assert.Equal(SystemType, gotType)
+			assert.Equal(len(b), gotSize)
+			assert.Greater(gotSize, len("system event"))
+		})
+	}
+	t.Run("dropped-event-not-observed", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		var calls int
+		f, err := newHclogFormatterFilter(false, WithDeny(`"/Op" == "text"`), WithSizeObserver(func(Type, int) { calls++ }))
+		require.NoError(err)
+		e, err := f.Process(context.Background(), testEvent())
+		require.NoError(err)
+		assert.Nil(e)
+		assert.Equal(0, calls)
+	})
+}
+
+func TestHclogFormatter_ProcessWithUnknownTypePolicy(t *testing.T) {
+	t.Parallel()
+	const unknownType = "synthetic-unknown"
+	testEvent := func() *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(unknownType),
+			Payload: map[string]interface{}{
+				"msg": "hello",
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		policy          UnknownTypePolicy
+		e               *eventlogger.Event
+		wantErrContains string
+		wantDropped     bool
+		want            []string
+	}{
+		{
+			name:            "default",
+			e:               testEvent(),
+			wantErrContains: "unknown event type synthetic-unknown",
+		},
+		{
+			name:            "error",
+			policy:          ErrorUnknownTypePolicy,
+			e:               testEvent(),
+			wantErrContains: "unknown event type synthetic-unknown",
+		},
+		{
+			name:        "skip",
+			policy:      SkipUnknownTypePolicy,
+			e:           testEvent(),
+			wantDropped: true,
+		},
+		{
+			name:   "passthrough",
+			policy: PassthroughUnknownTypePolicy,
+			e:      testEvent(),
+			want:   []string{"[TRACE]", "synthetic-unknown event", "msg=hello"},
+		},
+		{
+			name:   "passthrough-struct-payload",
+			policy: PassthroughUnknownTypePolicy,
+			e: &eventlogger.Event{
+				Type: eventlogger.EventType(unknownType),
+				Payload: &sysEvent{
+					Id:      "1",
+					Version: sysVersion,
+					Op:      Op("text"),
+				},
+			},
+			want: []string{"[TRACE]", "synthetic-unknown event", "Op=text"},
+		},
+		{
+			name:   "passthrough-invalid-payload",
+			policy: PassthroughUnknownTypePolicy,
+			e: &eventlogger.Event{
+				Type:    eventlogger.EventType(unknownType),
+				Payload: "not-a-struct",
+			},
+			wantErrContains: "unable to format synthetic-unknown event",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			f, err := newHclogFormatterFilter(false, WithUnknownTypePolicy(tt.policy))
+			require.NoError(err)
+			e, err := f.Process(context.Background(), tt.e)
+			if tt.wantErrContains != "" {
+				require.Error(err)
+				assert.Contains(err.Error(), tt.wantErrContains)
+				assert.Nil(e)
+				return
+			}
+			require.NoError(err)
+			if tt.wantDropped {
+				assert.Nil(e)
+				return
+			}
+			require.NotNil(e)
+			b, ok := e.Format(string(TextHclogSinkFormat))
+			require.True(ok)
+			for _, txt := range tt.want {
+				assert.Contains(string(b), txt)
+			}
+		})
+	}
+}
+
+func TestHclogFormatter_ProcessWithContextFields(t *testing.T) {
+	t.Parallel()
+	const (
+		opKey     ContextKey = "op_name"
+		tenantKey ContextKey = "tenant_id"
+	)
+	testEvent := func() *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(SystemType),
+			Payload: &sysEvent{
+				Id:      "1",
+				Version: sysVersion,
+				Op:      Op("text"),
+				Data: map[string]interface{}{
+					"msg": "hello",
+				},
+			},
+		}
+	}
+	opCtx := context.WithValue(context.Background(), opKey, "authenticate")
+
+	tests := []struct {
+		name          string
+		jsonFormat    bool
+		contextFields []ContextKey
+		ctx           context.Context
+		want          []string
+		wantMissing   []string
+	}{
+		{
+			name:          "field-in-ctx",
+			contextFields: []ContextKey{opKey},
+			ctx:           opCtx,
+			want:          []string{"op_name=authenticate"},
+		},
+		{
+			name:          "field-in-ctx-json",
+			jsonFormat:    true,
+			contextFields: []ContextKey{opKey},
+			ctx:           opCtx,
+			want:          []string{`"op_name":"authenticate"`},
+		},
+		{
+			name:          "field-missing-from-ctx",
+			contextFields: []ContextKey{opKey, tenantKey},
+			ctx:           opCtx,
+			want:          []string{"op_name=authenticate"},
+			wantMissing:   []string{"tenant_id"},
+		},
+		{
+			name:        "default",
+			ctx:         opCtx,
+			wantMissing: []string{"op_name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			f, err := newHclogFormatterFilter(tt.jsonFormat, WithContextFields(tt.contextFields))
+			require.NoError(err)
+			e, err := f.Process(tt.ctx, testEvent())
+			require.NoError(err)
+			require.NotNil(e)
+			format := TextHclogSinkFormat
+			if tt.jsonFormat {
+				format = JSONHclogSinkFormat
+			}
+			b, ok := e.Format(string(format))
+			require.True(ok)
+			for _, txt := range tt.want {
+				assert.Contains(string(b), txt)
+			}
+			for _, txt := range tt.wantMissing {
+				assert.NotContains(string(b), txt)
+			}
+		})
+	}
+}
+
+func TestHclogFormatter_ProcessWithScopeFilters(t *testing.T) {
+	t.Parallel()
+	const (
+		targetScope    = "o_target"
+		unrelatedScope = "o_unrelated"
+	)
+	testEvent := func(scopeId string) *eventlogger.Event {
+		e := &eventlogger.Event{
+			Type: eventlogger.EventType(ErrorType),
+			Payload: &err{
+				Id:      "1",
+				Version: errorVersion,
+				Op:      Op("text"),
+				Error:   ErrInvalidParameter.Error(),
+			},
+		}
+		if scopeId != "" {
+			e.Payload.(*err).RequestInfo = &RequestInfo{Id: "req-id", ScopeId: scopeId}
+		}
+		return e
+	}
+
+	tests := []struct {
+		name     string
+		opt      []Option
+		ctx      context.Context
+		scopeId  string
+		wantKeep bool
+	}{
+		{
+			name:     "allow-target-scope",
+			opt:      []Option{WithScopeAllow(targetScope)},
+			scopeId:  targetScope,
+			wantKeep: true,
+		},
+		{
+			name:    "allow-drops-unrelated-scope",
+			opt:     []Option{WithScopeAllow(targetScope)},
+			scopeId: unrelatedScope,
+		},
+		{
+			name: "allow-drops-missing-scope",
+			opt:  []Option{WithScopeAllow(targetScope)},
+		},
+		{
+			name:     "allow-scope-from-ctx",
+			opt:      []Option{WithScopeAllow(targetScope)},
+			ctx:      context.WithValue(context.Background(), requestInfoKey, &RequestInfo{Id: "req-id", ScopeId: targetScope}),
+			wantKeep: true,
+		},
+		{
+			name:    "deny-target-scope",
+			opt:     []Option{WithScopeDeny(targetScope)},
+			scopeId: targetScope,
+		},
+		{
+			name:     "deny-keeps-unrelated-scope",
+			opt:      []Option{WithScopeDeny(targetScope)},
+			scopeId:  unrelatedScope,
+			wantKeep: true,
+		},
+		{
+			name:     "deny-keeps-missing-scope",
+			opt:      []Option{WithScopeDeny(targetScope)},
+			wantKeep: true,
+		},
+		{
+			name:    "deny-wins",
+			opt:     []Option{WithScopeAllow(targetScope), WithScopeDeny(targetScope)},
+			scopeId: targetScope,
+		},
+		{
+			name:    "composes-with-deny-filter",
+			opt:     []Option{WithScopeAllow(targetScope), WithDeny(`"/Op" == "text"`)},
+			scopeId: targetScope,
+		},
+		{
+			name:     "composes-with-allow-filter",
+			opt:      []Option{WithScopeAllow(targetScope), WithAllow(`"/Op" == "text"`)},
+			scopeId:  targetScope,
+			wantKeep: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			f, err := newHclogFormatterFilter(false, tt.opt...)
+			require.NoError(err)
+			e, err := f.Process(ctx, testEvent(tt.scopeId))
+			require.NoError(err)
+			if !tt.wantKeep {
+				assert.Nil(e)
+				return
+			}
+			require.NotNil(e)
+			_, ok := e.Format(string(TextHclogSinkFormat))
+			assert.True(ok)
+		})
+	}
+}
+
+func TestHclogFormatter_ProcessWithExplainFilters(t *testing.T) {
+	t.Parallel()
+	testEvent := func(scopeId string) *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(ErrorType),
+			Payload: &err{
+				Id:          "1",
+				Version:     errorVersion,
+				Op:          Op("text"),
+				Error:       ErrInvalidParameter.Error(),
+				RequestInfo: &RequestInfo{Id: "req-id", ScopeId: scopeId},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		opt         []Option
+		scopeId     string
+		wantKeep    bool
+		wantExplain []string
+	}{
+		{
+			name:        "deny-filter",
+			opt:         []Option{WithExplainFilters(true), WithDeny(`"/Op" == "other"`, `"/Op" == "text"`)},
+			wantExplain: []string{"event dropped by filter", "event_type=error", `reason="deny filter matched"`, `"/Op" == "text"`},
+		},
+		{
+			name:        "no-allow-filter",
+			opt:         []Option{WithExplainFilters(true), WithAllow(`"/Op" == "other"`)},
+			wantExplain: []string{"event dropped by filter", `reason="no allow filter matched"`},
+		},
+		{
+			name:        "deny-scope",
+			opt:         []Option{WithExplainFilters(true), WithScopeDeny("o_1234567890")},
+			scopeId:     "o_1234567890",
+			wantExplain: []string{"event dropped by filter", `reason="deny scope matched"`, "scope_id=o_1234567890"},
+		},
+		{
+			name:        "no-allow-scope",
+			opt:         []Option{WithExplainFilters(true), WithScopeAllow("o_1234567890")},
+			scopeId:     "o_other",
+			wantExplain: []string{"event dropped by filter", `reason="no allow scope matched"`, "scope_id=o_other"},
+		},
+		{
+			name:     "kept-not-explained",
+			opt:      []Option{WithExplainFilters(true), WithAllow(`"/Op" == "text"`)},
+			wantKeep: true,
+		},
+		{
+			name: "off-by-default",
+			opt:  []Option{WithDeny(`"/Op" == "text"`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			f, err := newHclogFormatterFilter(false, tt.opt...)
+			require.NoError(err)
+
+			var buf bytes.Buffer
+			if f.explainLogger != nil {
+				f.explainLogger = hclog.New(&hclog.LoggerOptions{
+					Output: &buf,
+					Level:  hclog.Debug,
+				})
+			}
+
+			e, err := f.Process(context.Background(), testEvent(tt.scopeId))
+			require.NoError(err)
+			if tt.wantKeep {
+				assert.NotNil(e)
+			} else {
+				assert.Nil(e, "explaining a drop must not change the result")
+			}
+			if len(tt.wantExplain) == 0 {
+				assert.Empty(buf.String())
+				return
+			}
+			for _, want := range tt.wantExplain {
+				assert.Contains(buf.String(), want)
+			}
+		})
+	}
+}
+
+func Test_newTypeScopedFormatterFilter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	errEvent := func() *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(ErrorType),
+			Payload: &err{
+				Id:      "1",
+				Version: errorVersion,
+				Error:   ErrInvalidParameter.Error(),
+				Op:      Op("text"),
+			},
+		}
+	}
+	observationEvent := func() *eventlogger.Event {
+		return &eventlogger.Event{
+			Type: eventlogger.EventType(ObservationType),
+			Payload: map[string]interface{}{
+				"id":         "1",
+				"version":    observationVersion,
+				"latency-ms": 10,
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		types           []Type
+		e               *eventlogger.Event
+		wantErrIs       error
+		wantErrContains string
+		wantDropped     bool
+	}{
+		{
+			name:            "missing-types",
+			wantErrIs:       ErrInvalidParameter,
+			wantErrContains: "missing event types",
+		},
+		{
+			name:            "invalid-type",
+			types:           []Type{ErrorType, "invalid"},
+			wantErrIs:       ErrInvalidParameter,
+			wantErrContains: "'invalid' is not a valid event type",
+		},
+		{
+			name:  "error-only-keeps-error",
+			types: []Type{ErrorType},
+			e:     errEvent(),
+		},
+		{
+			name:        "error-only-drops-observation",
+			types:       []Type{ErrorType},
+			e:           observationEvent(),
+			wantDropped: true,
+		},
+		{
+			name:  "error-and-observation-keeps-observation",
+			types: []Type{ErrorType, ObservationType},
+			e:     observationEvent(),
+		},
+		{
+			name:  "every-type-keeps-observation",
+			types: []Type{ErrorType, EveryType},
+			e:     observationEvent(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			f, err := newTypeScopedFormatterFilter(false, tt.types)
+			if tt.wantErrIs != nil {
+				require.Error(err)
+				assert.Nil(f)
+				assert.ErrorIs(err, tt.wantErrIs)
+				assert.Contains(err.Error(), tt.wantErrContains)
+				return
+			}
+			require.NoError(err)
+			require.NotNil(f)
+			got, err := f.Process(ctx, tt.e)
+			require.NoError(err)
+			if tt.wantDropped {
+				assert.Nil(got)
+				return
+			}
+			require.NotNil(got)
+			_, ok := got.Format(string(TextHclogSinkFormat))
+			assert.True(ok)
+		})
+	}
+}
+
+func Test_errorChain(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		e    error
+		want []string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "not-wrapped",
+			e:    ErrInvalidParameter,
+		},
+		{
+			name: "multiply-wrapped",
+			e:    fmt.Errorf("top: %w", fmt.Errorf("middle: %w", fmt.Errorf("bottom: %w", ErrInvalidParameter))),
+			want: []string{
+				"top",
+				"middle",
+				"bottom",
+				"invalid parameter",
+			},
+		},
+		{
+			name: "wrapped-without-own-message",
+			e:    fmt.Errorf("top: %w", fmt.Errorf("%w", ErrInvalidParameter)),
+			want: []string{
+				"top",
+				"invalid parameter",
+			},
+		},
+		{
+			name: "wrapped-message-not-at-end",
+			e:    fmt.Errorf("%w (while reading config)", ErrInvalidParameter),
+			want: []string{
+				"invalid parameter (while reading config)",
+				"invalid parameter",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errorChain(tt.e))
+		})
+	}
+}
+
 func Test_hclogFormatterFilter_Name(t *testing.T) {
 	t.Parallel()
 	t.Run("simple", func(t *testing.T) {
@@ -275,14 +978,16 @@ func Test_hclogFormatterFilter_Type(t *testing.T) {
 func Test_newHclogFormatterFilter(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
-		name            string
-		jsonFormat      bool
-		opt             []Option
-		wantErr         bool
-		wantIsError     error
-		wantErrContains string
-		wantAllow       []string
-		wantDeny        []string
+		name              string
+		jsonFormat        bool
+		opt               []Option
+		wantErr           bool
+		wantIsError       error
+		wantErrContains   string
+		wantAllow         []string
+		wantDeny          []string
+		wantIncludeCaller bool
+		wantContextFields []ContextKey
 	}{
 		{
 			name: "no-opts",
@@ -323,6 +1028,35 @@ func Test_newHclogFormatterFilter(t *testing.T) {
 			wantErr:         true,
 			wantErrContains: "missing filter",
 		},
+		{
+			name:       "empty-scope-allow",
+			jsonFormat: true,
+			opt: []Option{
+				WithScopeAllow("o_1234567890", ""),
+			},
+			wantErr:         true,
+			wantIsError:     ErrInvalidParameter,
+			wantErrContains: "missing allow scope id",
+		},
+		{
+			name:       "empty-scope-deny",
+			jsonFormat: true,
+			opt: []Option{
+				WithScopeDeny(""),
+			},
+			wantErr:         true,
+			wantIsError:     ErrInvalidParameter,
+			wantErrContains: "missing deny scope id",
+		},
+		{
+			name: "invalid-unknown-type-policy",
+			opt: []Option{
+				WithUnknownTypePolicy("invalid"),
+			},
+			wantErr:         true,
+			wantIsError:     ErrInvalidParameter,
+			wantErrContains: "not a valid unknown type policy",
+		},
 		{
 			name:       "valid-filters",
 			jsonFormat: true,
@@ -333,6 +1067,21 @@ func Test_newHclogFormatterFilter(t *testing.T) {
 			wantAllow: []string{"alice==friend", "bob==friend"},
 			wantDeny:  []string{"eve==acquaintance", "fido!=dog"},
 		},
+		{
+			name:       "with-include-caller",
+			jsonFormat: true,
+			opt: []Option{
+				WithIncludeCaller(true),
+			},
+			wantIncludeCaller: true,
+		},
+		{
+			name: "with-context-fields",
+			opt: []Option{
+				WithContextFields([]ContextKey{"op_name", "tenant_id"}),
+			},
+			wantContextFields: []ContextKey{"op_name", "tenant_id"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -354,6 +1103,8 @@ func Test_newHclogFormatterFilter(t *testing.T) {
 			assert.NotNil(got)
 
 			assert.Equal(tt.jsonFormat, got.jsonFormat)
+			assert.Equal(tt.wantIncludeCaller, got.includeCaller)
+			assert.Equal(tt.wantContextFields, got.contextFields)
 
 			assert.Len(got.allow, len(tt.wantAllow))
 			for _, f := range got.allow {
@@ -25,23 +25,30 @@ type Option func(*options)
 
 // options = how options are represented
 type options struct {
-	withId               string
-	withDetails          map[string]interface{}
-	withHeader           map[string]interface{}
-	withFlush            bool
-	withInfo             map[string]interface{}
-	withRequestInfo      *RequestInfo
-	withNow              time.Time
-	withRequest          *Request
-	withResponse         *Response
-	withAuth             *Auth
-	withEventer          *Eventer
-	withEventerConfig    *EventerConfig
-	withAllow            []string
-	withDeny             []string
-	withSchema           *url.URL
-	withAuditWrapper     wrapping.Wrapper
-	withFilterOperations AuditFilterOperations
+	withId                string
+	withDetails           map[string]interface{}
+	withHeader            map[string]interface{}
+	withFlush             bool
+	withInfo              map[string]interface{}
+	withRequestInfo       *RequestInfo
+	withNow               time.Time
+	withRequest           *Request
+	withResponse          *Response
+	withAuth              *Auth
+	withEventer           *Eventer
+	withEventerConfig     *EventerConfig
+	withAllow             []string
+	withDeny              []string
+	withScopeAllow        []string
+	withScopeDeny         []string
+	withSchema            *url.URL
+	withAuditWrapper      wrapping.Wrapper
+	withFilterOperations  AuditFilterOperations
+	withIncludeCaller     bool
+	withContextFields     []ContextKey
+	withExplainFilters    bool
+	withSizeObserver      SizeObserver
+	withUnknownTypePolicy UnknownTypePolicy
 
 	withBroker          broker     // test only option
 	withAuditSink       bool       // test only option
@@ -183,6 +190,66 @@ func WithDeny(f ...string) Option {
 	}
 }
 
+// WithScopeAllow is an optional set of scope ids.  If set, only events with a
+// RequestInfo scope id in the set are kept.
+func WithScopeAllow(scopeIds ...string) Option {
+	return func(o *options) {
+		o.withScopeAllow = scopeIds
+	}
+}
+
+// WithScopeDeny is an optional set of scope ids.  Events with a RequestInfo
+// scope id in the set are discarded.
+func WithScopeDeny(scopeIds ...string) Option {
+	return func(o *options) {
+		o.withScopeDeny = scopeIds
+	}
+}
+
+// WithIncludeCaller is an optional flag to include the file:line location
+// that emitted an event.  It's off by default, since finding the caller adds
+// overhead to every event.
+func WithIncludeCaller(include bool) Option {
+	return func(o *options) {
+		o.withIncludeCaller = include
+	}
+}
+
+// WithContextFields is an optional set of context keys whose values are added
+// to hclog formatted events.  Keys which are not found in an event's context
+// are skipped.
+func WithContextFields(keys []ContextKey) Option {
+	return func(o *options) {
+		o.withContextFields = keys
+	}
+}
+
+// WithExplainFilters is an optional flag to log why an event was dropped by
+// the filters of an hclog formatter.  It's off by default and is intended for
+// debugging filter configurations.
+func WithExplainFilters(explain bool) Option {
+	return func(o *options) {
+		o.withExplainFilters = explain
+	}
+}
+
+// WithSizeObserver is an optional callback which is called with the size in
+// bytes of each event formatted by an hclog formatter.  It allows operators
+// to alarm on unusually large events.
+func WithSizeObserver(fn SizeObserver) Option {
+	return func(o *options) {
+		o.withSizeObserver = fn
+	}
+}
+
+// WithUnknownTypePolicy is an optional policy for how an hclog formatter
+// handles events of an unknown type.  By default, they're an error.
+func WithUnknownTypePolicy(p UnknownTypePolicy) Option {
+	return func(o *options) {
+		o.withUnknownTypePolicy = p
+	}
+}
+
 // WithAuditWrapper is an optional wrapper for audit events
 func WithAuditWrapper(w wrapping.Wrapper) Option {
 	return func(o *options) {
@@ -145,6 +145,59 @@ func Test_GetOpts(t *testing.T) {
 		testOpts.withDeny = deny
 		assert.Equal(opts, testOpts)
 	})
+	t.Run("WithIncludeCaller", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithIncludeCaller(true))
+		testOpts := getDefaultOptions()
+		testOpts.withIncludeCaller = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithScopeAllow", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithScopeAllow("o_1", "o_2"))
+		testOpts := getDefaultOptions()
+		testOpts.withScopeAllow = []string{"o_1", "o_2"}
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithScopeDeny", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithScopeDeny("o_1", "o_2"))
+		testOpts := getDefaultOptions()
+		testOpts.withScopeDeny = []string{"o_1", "o_2"}
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithContextFields", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithContextFields([]ContextKey{"op_name"}))
+		testOpts := getDefaultOptions()
+		testOpts.withContextFields = []ContextKey{"op_name"}
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithExplainFilters", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithExplainFilters(true))
+		testOpts := getDefaultOptions()
+		testOpts.withExplainFilters = true
+		assert.Equal(opts, testOpts)
+	})
+	t.Run("WithSizeObserver", func(t *testing.T) {
+		assert, require := assert.New(t), require.New(t)
+		var gotSize int
+		opts := getOpts(WithSizeObserver(func(_ Type, size int) { gotSize = size }))
+		require.NotNil(opts.withSizeObserver)
+		opts.withSizeObserver(SystemType, 42)
+		assert.Equal(42, gotSize)
+		// funcs can't be compared, so clear it before comparing the rest
+		opts.withSizeObserver = nil
+		assert.Equal(opts, getDefaultOptions())
+	})
+	t.Run("WithUnknownTypePolicy", func(t *testing.T) {
+		assert := assert.New(t)
+		opts := getOpts(WithUnknownTypePolicy(SkipUnknownTypePolicy))
+		testOpts := getDefaultOptions()
+		testOpts.withUnknownTypePolicy = SkipUnknownTypePolicy
+		assert.Equal(opts, testOpts)
+	})
 	t.Run("WithSchema", func(t *testing.T) {
 		assert, require := assert.New(t), require.New(t)
 		schema, err := url.Parse("https://alice.com")
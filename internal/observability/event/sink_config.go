@@ -7,17 +7,25 @@ import (
 
 // SinkConfig defines the configuration for a Eventer sink
 type SinkConfig struct {
-	Name           string                `hcl:"name"`             // Name defines a name for the sink.
-	Description    string                `hcl:"description"`      // Description defines a description for the sink.
-	EventTypes     []Type                `hcl:"event_types"`      // EventTypes defines a list of event types that will be sent to the sink. See the docs for EventTypes for a list of accepted values.
-	EventSourceUrl string                `hcl:"event_source_url"` // EventSource defines an optional event source URL for the sink.  If not defined a default source will be composed of the https://hashicorp.com/boundary.io/ServerName/Path/FileName.
-	AllowFilters   []string              `hcl:"allow_filters"`    // AllowFilters define a set predicates for including an event in the sink. If any filter matches, the event will be included. The filter should be in a format supported by hashicorp/go-bexpr.
-	DenyFilters    []string              `hcl:"deny_filters"`     // DenyFilters define a set predicates for excluding an event in the sink. If any filter matches, the event will be excluded. The filter should be in a format supported by hashicorp/go-bexpr.
-	Format         SinkFormat            `hcl:"format"`           // Format defines the format for the sink (JSONSinkFormat or TextSinkFormat).
-	Type           SinkType              `hcl:"type"`             // Type defines the type of sink (StderrSink or FileSink).
-	StderrConfig   *StderrSinkTypeConfig `hcl:"stderr"`           // StderrConfig defines parameters for a stderr output.
-	FileConfig     *FileSinkTypeConfig   `hcl:"file"`             // FileConfig defines parameters for a file output.
-	AuditConfig    *AuditConfig          `hcl:"audit_config"`     // AuditConfig defines optional parameters for audit events (if EventTypes contains audit)
+	Name              string                `hcl:"name"`                // Name defines a name for the sink.
+	Description       string                `hcl:"description"`         // Description defines a description for the sink.
+	EventTypes        []Type                `hcl:"event_types"`         // EventTypes defines a list of event types that will be sent to the sink. See the docs for EventTypes for a list of accepted values.
+	EventSourceUrl    string                `hcl:"event_source_url"`    // EventSource defines an optional event source URL for the sink.  If not defined a default source will be composed of the https://hashicorp.com/boundary.io/ServerName/Path/FileName.
+	AllowFilters      []string              `hcl:"allow_filters"`       // AllowFilters define a set predicates for including an event in the sink. If any filter matches, the event will be included. The filter should be in a format supported by hashicorp/go-bexpr.
+	DenyFilters       []string              `hcl:"deny_filters"`        // DenyFilters define a set predicates for excluding an event in the sink. If any filter matches, the event will be excluded. The filter should be in a format supported by hashicorp/go-bexpr.
+	ScopeAllow        []string              `hcl:"scope_allow"`         // ScopeAllow defines an optional set of scope ids. If set, only events for requests in one of the scopes will be included (only supported for hclog formats)
+	ScopeDeny         []string              `hcl:"scope_deny"`          // ScopeDeny defines an optional set of scope ids. Events for requests in one of the scopes will be excluded (only supported for hclog formats)
+	Format            SinkFormat            `hcl:"format"`              // Format defines the format for the sink (JSONSinkFormat or TextSinkFormat).
+	Type              SinkType              `hcl:"type"`                // Type defines the type of sink (StderrSink or FileSink).
+	StderrConfig      *StderrSinkTypeConfig `hcl:"stderr"`              // StderrConfig defines parameters for a stderr output.
+	FileConfig        *FileSinkTypeConfig   `hcl:"file"`                // FileConfig defines parameters for a file output.
+	AuditConfig       *AuditConfig          `hcl:"audit_config"`        // AuditConfig defines optional parameters for audit events (if EventTypes contains audit)
+	IncludeCaller     bool                  `hcl:"include_caller"`      // IncludeCaller defines an optional flag to include the file:line location that emitted an event (only supported for hclog formats)
+	UnknownTypePolicy UnknownTypePolicy     `hcl:"unknown_type_policy"` // UnknownTypePolicy defines how events of an unknown type are handled: error (default), skip, or passthrough (only supported for hclog formats)
+
+	BufferSize             int           `hcl:"buffer_size"`                    // BufferSize defines an optional number of formatted events to buffer before writing them to the sink. Events are not buffered if it's zero.
+	BufferFlushInterval    time.Duration `mapstructure:"buffer_flush_interval"` // BufferFlushInterval defines an optional interval to write the buffered events to the sink (requires a BufferSize)
+	BufferFlushIntervalHCL string        `hcl:"buffer_flush_interval" json:"-"` // BufferFlushIntervalHCL defines hcl string version of BufferFlushInterval
 }
 
 func (sc *SinkConfig) Validate() error {
@@ -28,6 +36,9 @@ func (sc *SinkConfig) Validate() error {
 	if err := sc.Format.Validate(); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	if err := sc.UnknownTypePolicy.validate(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
 	var foundSinkTypeConfigs int
 	if sc.StderrConfig != nil {
@@ -60,6 +71,14 @@ func (sc *SinkConfig) Validate() error {
 	if sc.Name == "" {
 		return fmt.Errorf("%s: missing sink name: %w", op, ErrInvalidParameter)
 	}
+	switch {
+	case sc.BufferSize < 0:
+		return fmt.Errorf("%s: buffer size must not be negative: %w", op, ErrInvalidParameter)
+	case sc.BufferFlushInterval < 0:
+		return fmt.Errorf("%s: buffer flush interval must not be negative: %w", op, ErrInvalidParameter)
+	case sc.BufferFlushInterval > 0 && sc.BufferSize == 0:
+		return fmt.Errorf("%s: buffer flush interval requires a buffer size: %w", op, ErrInvalidParameter)
+	}
 	if len(sc.EventTypes) == 0 {
 		return fmt.Errorf("%s: missing event types: %w", op, ErrInvalidParameter)
 	}
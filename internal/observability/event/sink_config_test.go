@@ -2,6 +2,7 @@ package event
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -147,6 +148,21 @@ func TestSinkConfig_Validate(t *testing.T) {
 			wantErrIs:       ErrInvalidParameter,
 			wantErrContains: "not a valid sink format",
 		},
+		{
+			name: "invalid-unknown-type-policy",
+			sc: SinkConfig{
+				Name:              "sink-name",
+				Format:            TextHclogSinkFormat,
+				Type:              FileSink,
+				EventTypes:        []Type{EveryType},
+				UnknownTypePolicy: "invalid",
+				FileConfig: &FileSinkTypeConfig{
+					FileName: "tmp.file",
+				},
+			},
+			wantErrIs:       ErrInvalidParameter,
+			wantErrContains: "not a valid unknown type policy",
+		},
 		{
 			name: "file-sink-with-no-file-name",
 			sc: SinkConfig{
@@ -204,6 +220,54 @@ func TestSinkConfig_Validate(t *testing.T) {
 			wantErrIs:       ErrInvalidParameter,
 			wantErrContains: `too many sink type config blocks`,
 		},
+		{
+			name: "negative-buffer-size",
+			sc: SinkConfig{
+				Name:       "negative-buffer-size",
+				EventTypes: []Type{EveryType},
+				Type:       StderrSink,
+				Format:     JSONSinkFormat,
+				BufferSize: -1,
+			},
+			wantErrIs:       ErrInvalidParameter,
+			wantErrContains: "buffer size must not be negative",
+		},
+		{
+			name: "negative-buffer-flush-interval",
+			sc: SinkConfig{
+				Name:                "negative-buffer-flush-interval",
+				EventTypes:          []Type{EveryType},
+				Type:                StderrSink,
+				Format:              JSONSinkFormat,
+				BufferSize:          10,
+				BufferFlushInterval: -time.Second,
+			},
+			wantErrIs:       ErrInvalidParameter,
+			wantErrContains: "buffer flush interval must not be negative",
+		},
+		{
+			name: "buffer-flush-interval-without-size",
+			sc: SinkConfig{
+				Name:                "buffer-flush-interval-without-size",
+				EventTypes:          []Type{EveryType},
+				Type:                StderrSink,
+				Format:              JSONSinkFormat,
+				BufferFlushInterval: time.Second,
+			},
+			wantErrIs:       ErrInvalidParameter,
+			wantErrContains: "buffer flush interval requires a buffer size",
+		},
+		{
+			name: "valid-buffered",
+			sc: SinkConfig{
+				Name:                "valid-buffered",
+				EventTypes:          []Type{EveryType},
+				Type:                StderrSink,
+				Format:              JSONSinkFormat,
+				BufferSize:          10,
+				BufferFlushInterval: time.Second,
+			},
+		},
 		{
 			name: "valid",
 			sc: SinkConfig{
@@ -0,0 +1,24 @@
+package event
+
+import (
+	"fmt"
+)
+
+const (
+	DefaultUnknownTypePolicy     UnknownTypePolicy = ""            // DefaultUnknownTypePolicy will be ErrorUnknownTypePolicy
+	ErrorUnknownTypePolicy       UnknownTypePolicy = "error"       // ErrorUnknownTypePolicy means an event of an unknown type is an error
+	SkipUnknownTypePolicy        UnknownTypePolicy = "skip"        // SkipUnknownTypePolicy means an event of an unknown type is discarded
+	PassthroughUnknownTypePolicy UnknownTypePolicy = "passthrough" // PassthroughUnknownTypePolicy means an event of an unknown type is formatted like any other event
+)
+
+type UnknownTypePolicy string // UnknownTypePolicy defines how a formatter handles an event of an unknown type
+
+func (p UnknownTypePolicy) validate() error {
+	const op = "event.(UnknownTypePolicy).validate"
+	switch p {
+	case DefaultUnknownTypePolicy, ErrorUnknownTypePolicy, SkipUnknownTypePolicy, PassthroughUnknownTypePolicy:
+		return nil
+	default:
+		return fmt.Errorf("%s: %s is not a valid unknown type policy: %w", op, p, ErrInvalidParameter)
+	}
+}
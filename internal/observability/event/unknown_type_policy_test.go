@@ -0,0 +1,56 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownTypePolicy_validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		p               UnknownTypePolicy
+		wantErrIs       error
+		wantErrContains string
+	}{
+		{
+			name:            "invalid",
+			p:               "invalid",
+			wantErrIs:       ErrInvalidParameter,
+			wantErrContains: "not a valid unknown type policy",
+		},
+		{
+			name: "Default",
+			p:    DefaultUnknownTypePolicy,
+		},
+		{
+			name: "Error",
+			p:    ErrorUnknownTypePolicy,
+		},
+		{
+			name: "Skip",
+			p:    SkipUnknownTypePolicy,
+		},
+		{
+			name: "Passthrough",
+			p:    PassthroughUnknownTypePolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert, require := assert.New(t), require.New(t)
+			err := tt.p.validate()
+			if tt.wantErrIs != nil {
+				require.Error(err)
+				assert.ErrorIs(err, tt.wantErrIs)
+				if tt.wantErrContains != "" {
+					assert.Contains(err.Error(), tt.wantErrContains)
+				}
+				return
+			}
+			require.NoError(err)
+		})
+	}
+}
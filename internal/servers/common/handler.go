@@ -137,6 +137,12 @@ func WrapWithEventsHandler(h http.Handler, e *event.Eventer, kms *kms.Kms) (http
 			event.WriteError(r.Context(), op, err, event.WithInfoMsg("unable to create context with request info", "method", r.Method, "url", r.URL.RequestURI()))
 			return
 		}
+		ctx, err = event.NewRequestScopeContext(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			event.WriteError(r.Context(), op, err, event.WithInfoMsg("unable to create context with request scope", "method", r.Method, "url", r.URL.RequestURI()))
+			return
+		}
 		ctx, err = event.NewEventerContext(ctx, e)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -218,6 +218,7 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 		if reqInfo != nil {
 			reqInfo.UserId = ret.UserId
 		}
+		setEventScope(ctx, ret.Scope)
 		ret.Error = nil
 		return
 	}
@@ -245,6 +246,7 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 		event.WriteError(ctx, op, err, event.WithInfoMsg("error performing authn/authz check"))
 		return
 	}
+	setEventScope(ctx, ret.Scope)
 
 	ret.AuthTokenId = v.requestInfo.PublicId
 	ret.AuthenticationFinished = authResults.AuthenticationFinished
@@ -288,6 +290,17 @@ func Verify(ctx context.Context, opt ...Option) (ret VerifyResults) {
 	return
 }
 
+// setEventScope sets scp as the scope of the request's events written from
+// now on, including the events of the handler and the outgoing interceptor,
+// so sinks can filter them by scope. Only the context of an API request
+// holds the request scope, so nothing is set for other contexts.
+func setEventScope(ctx context.Context, scp *scopes.ScopeInfo) {
+	if scp == nil || scp.Id == "" {
+		return
+	}
+	_ = event.SetRequestScope(ctx, scp.Id)
+}
+
 func (v *verifier) decryptToken(ctx context.Context) {
 	const op = "auth.(verifier).decryptToken"
 	switch v.requestInfo.TokenFormat {
@@ -2,17 +2,25 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
+	"github.com/hashicorp/boundary/internal/observability/event"
 	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	"github.com/hashicorp/boundary/internal/types/scope"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -131,3 +139,78 @@ func TestAuthTokenAuthenticator(t *testing.T) {
 		})
 	}
 }
+
+func TestVerify_EventScope(t *testing.T) {
+	// this cannot run in parallel because it relies on envvar
+	// globals.BOUNDARY_DEVELOPER_ENABLE_EVENTS
+	event.TestEnableEventing(t, true)
+	assert, require := assert.New(t), require.New(t)
+
+	c := event.TestEventerConfig(t, "TestVerify_EventScope", event.TestWithAuditSink(t))
+	testLock := &sync.Mutex{}
+	testLogger := hclog.New(&hclog.LoggerOptions{
+		Mutex: testLock,
+		Name:  "test",
+	})
+	testEventer, err := event.NewEventer(testLogger, testLock, "TestVerify_EventScope", c.EventerConfig)
+	require.NoError(err)
+
+	// the handler verifies the request and then writes an event
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		res := Verify(ctx, WithScopeId(scope.Global.String()))
+		if res.Error != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		event.WriteError(ctx, "TestVerify_EventScope.handler", stderrors.New("written by the handler"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// the interceptor installs the request's event context, like
+	// common.WrapWithEventsHandler, and writes the request's audit events
+	// before and after the handler
+	interceptor := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id, err := event.NewId(event.IdPrefix)
+		require.NoError(err)
+		ctx, err = event.NewRequestInfoContext(ctx, &event.RequestInfo{
+			EventId: id,
+			Id:      "trace-id",
+			Method:  r.Method,
+			Path:    r.URL.RequestURI(),
+		})
+		require.NoError(err)
+		ctx, err = event.NewRequestScopeContext(ctx)
+		require.NoError(err)
+		ctx, err = event.NewEventerContext(ctx, testEventer)
+		require.NoError(err)
+
+		require.NoError(event.WriteAudit(ctx, "TestVerify_EventScope.interceptor", event.WithRequest(&event.Request{Operation: "GET"})))
+		handler.ServeHTTP(w, r.WithContext(ctx))
+		require.NoError(event.WriteAudit(ctx, "TestVerify_EventScope.interceptor", event.WithResponse(&event.Response{StatusCode: http.StatusOK}), event.WithFlush()))
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/scopes/global", nil)
+	require.NoError(err)
+	req = req.WithContext(DisabledAuthTestContext(nil, scope.Global.String()))
+	rr := httptest.NewRecorder()
+	interceptor.ServeHTTP(rr, req)
+	require.Equal(http.StatusOK, rr.Code)
+
+	eventScopeId := func(f *os.File) string {
+		b, err := ioutil.ReadFile(f.Name())
+		require.NoError(err)
+		require.NotEmpty(b)
+		var got struct {
+			Data struct {
+				RequestInfo *event.RequestInfo `json:"request_info"`
+			} `json:"data"`
+		}
+		require.NoError(json.Unmarshal(b, &got))
+		require.NotNil(got.Data.RequestInfo)
+		return got.Data.RequestInfo.ScopeId
+	}
+	assert.Equal(scope.Global.String(), eventScopeId(c.ErrorEvents), "the handler's event must have the scope")
+	assert.Equal(scope.Global.String(), eventScopeId(c.AuditEvents), "the interceptor's event must have the scope")
+}
@@ -44,6 +44,10 @@ type Controller struct {
 	// Used for testing and tracking worker health
 	workerStatusUpdateTimes *sync.Map
 
+	// vaultLimiters are the rate limiters of the Vault credential stores
+	// shared by the vault repositories and jobs.
+	vaultLimiters *vault.RateLimiters
+
 	// Repo factory methods
 	AuthTokenRepoFn       common.AuthTokenRepoFactory
 	VaultCredentialRepoFn common.VaultCredentialRepoFactory
@@ -136,8 +140,11 @@ func New(ctx context.Context, conf *Config) (*Controller, error) {
 			authtoken.WithTokenTimeToLiveDuration(c.conf.RawConfig.Controller.AuthTokenTimeToLiveDuration),
 			authtoken.WithTokenTimeToStaleDuration(c.conf.RawConfig.Controller.AuthTokenTimeToStaleDuration))
 	}
+	c.vaultLimiters = vault.NewRateLimiters(c.conf.RawConfig.Controller.VaultIssueRequestsPerSecond, c.conf.RawConfig.Controller.VaultIssueBurst)
 	c.VaultCredentialRepoFn = func() (*vault.Repository, error) {
-		return vault.NewRepository(dbase, dbase, c.kms, c.scheduler)
+		return vault.NewRepository(ctx, dbase, dbase, c.kms, c.scheduler,
+			vault.WithRateLimiters(c.vaultLimiters),
+			vault.WithTokenFileDirectory(c.conf.RawConfig.Controller.VaultTokenFileDirectory))
 	}
 	c.ServersRepoFn = func() (*servers.Repository, error) {
 		return servers.NewRepository(dbase, dbase, c.kms)
@@ -203,7 +210,9 @@ func (c *Controller) Start() error {
 
 func (c *Controller) registerJobs() error {
 	rw := db.New(c.conf.Database)
-	if err := vault.RegisterJobs(c.baseContext, c.scheduler, rw, rw, c.kms); err != nil {
+	if err := vault.RegisterJobs(c.baseContext, c.scheduler, rw, rw, c.kms,
+		vault.WithTokenFileDirectory(c.conf.RawConfig.Controller.VaultTokenFileDirectory),
+		vault.WithRateLimiters(c.vaultLimiters)); err != nil {
 		return err
 	}
 
@@ -19,7 +19,6 @@ import (
 	"github.com/hashicorp/boundary/internal/types/action"
 	"github.com/hashicorp/boundary/internal/types/resource"
 	pb "github.com/hashicorp/boundary/sdk/pbs/controller/api/resources/credentiallibraries"
-	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
@@ -179,7 +178,7 @@ func (s Service) GetCredentialLibrary(ctx context.Context, req *pbs.GetCredentia
 func (s Service) CreateCredentialLibrary(ctx context.Context, req *pbs.CreateCredentialLibraryRequest) (*pbs.CreateCredentialLibraryResponse, error) {
 	const op = "credentiallibraries.(Service).CreateCredentialLibrary"
 
-	if err := validateCreateRequest(req); err != nil {
+	if err := validateCreateRequest(ctx, req); err != nil {
 		return nil, err
 	}
 	authResults := s.authResult(ctx, req.GetItem().GetCredentialStoreId(), action.Create)
@@ -220,7 +219,7 @@ func (s Service) CreateCredentialLibrary(ctx context.Context, req *pbs.CreateCre
 func (s Service) UpdateCredentialLibrary(ctx context.Context, req *pbs.UpdateCredentialLibraryRequest) (*pbs.UpdateCredentialLibraryResponse, error) {
 	const op = "credentiallibraries.(Service).UpdateCredentialLibrary"
 
-	if err := validateUpdateRequest(req); err != nil {
+	if err := validateUpdateRequest(ctx, req); err != nil {
 		return nil, err
 	}
 	authResults := s.authResult(ctx, req.GetId(), action.Update)
@@ -511,7 +510,7 @@ func toStorageVaultLibrary(storeId string, in *pb.CredentialLibrary) (out *vault
 		opts = append(opts, vault.WithRequestBody([]byte(attrs.GetHttpRequestBody().GetValue())))
 	}
 
-	cs, err := vault.NewCredentialLibrary(storeId, attrs.GetPath().GetValue(), opts...)
+	cs, err := vault.NewCredentialLibrary(context.Background(), storeId, attrs.GetPath().GetValue(), opts...)
 	if err != nil {
 		return nil, errors.WrapDeprecated(err, op, errors.WithMsg("unable to build credential library"))
 	}
@@ -527,7 +526,7 @@ func validateGetRequest(req *pbs.GetCredentialLibraryRequest) error {
 	return handlers.ValidateGetRequest(handlers.NoopValidatorFn, req, vault.CredentialLibraryPrefix)
 }
 
-func validateCreateRequest(req *pbs.CreateCredentialLibraryRequest) error {
+func validateCreateRequest(ctx context.Context, req *pbs.CreateCredentialLibraryRequest) error {
 	return handlers.ValidateCreateRequest(req.GetItem(), func() map[string]string {
 		badFields := map[string]string{}
 		switch credential.SubtypeFromId(req.GetItem().GetCredentialStoreId()) {
@@ -543,11 +542,13 @@ func validateCreateRequest(req *pbs.CreateCredentialLibraryRequest) error {
 			if attrs.GetPath().GetValue() == "" {
 				badFields[vaultPathField] = "This is a required field."
 			}
-			if m := attrs.GetHttpMethod(); m != nil && !strutil.StrListContains([]string{"GET", "POST"}, strings.ToUpper(m.GetValue())) {
-				badFields[httpMethodField] = "If set, value must be 'GET' or 'POST'."
+			if m := attrs.GetHttpMethod(); m != nil {
+				if _, err := vault.ParseMethod(ctx, m.GetValue()); err != nil {
+					badFields[httpMethodField] = "If set, value must be 'GET', 'POST', 'PUT', or 'PATCH'."
+				}
 			}
-			if b := attrs.GetHttpRequestBody(); b != nil && strings.ToUpper(attrs.GetHttpMethod().GetValue()) != "POST" {
-				badFields[httpRequestBodyField] = fmt.Sprintf("Field can only be set if %q is set to the value 'POST'.", httpMethodField)
+			if b := attrs.GetHttpRequestBody(); b != nil && !vault.Method(strings.ToUpper(attrs.GetHttpMethod().GetValue())).AllowsRequestBody() {
+				badFields[httpRequestBodyField] = fmt.Sprintf("Field can only be set if %q is set to the value 'POST', 'PUT', or 'PATCH'.", httpMethodField)
 			}
 		default:
 			badFields[globals.CredentialStoreIdField] = "This field must be a valid credential store id."
@@ -556,7 +557,7 @@ func validateCreateRequest(req *pbs.CreateCredentialLibraryRequest) error {
 	})
 }
 
-func validateUpdateRequest(req *pbs.UpdateCredentialLibraryRequest) error {
+func validateUpdateRequest(ctx context.Context, req *pbs.UpdateCredentialLibraryRequest) error {
 	return handlers.ValidateUpdateRequest(req, req.GetItem(), func() map[string]string {
 		badFields := map[string]string{}
 		switch credential.SubtypeFromId(req.GetId()) {
@@ -572,11 +573,13 @@ func validateUpdateRequest(req *pbs.UpdateCredentialLibraryRequest) error {
 			if handlers.MaskContains(req.GetUpdateMask().GetPaths(), vaultPathField) && attrs.GetPath().GetValue() == "" {
 				badFields[vaultPathField] = "This is a required field and cannot be set to empty."
 			}
-			if m := attrs.GetHttpMethod(); handlers.MaskContains(req.GetUpdateMask().GetPaths(), httpMethodField) && m != nil && !strutil.StrListContains([]string{"GET", "POST"}, strings.ToUpper(m.GetValue())) {
-				badFields[httpMethodField] = "If set, value must be 'GET' or 'POST'."
+			if m := attrs.GetHttpMethod(); handlers.MaskContains(req.GetUpdateMask().GetPaths(), httpMethodField) && m != nil {
+				if _, err := vault.ParseMethod(ctx, m.GetValue()); err != nil {
+					badFields[httpMethodField] = "If set, value must be 'GET', 'POST', 'PUT', or 'PATCH'."
+				}
 			}
-			if b := attrs.GetHttpRequestBody(); b != nil && strings.ToUpper(attrs.GetHttpMethod().GetValue()) == "GET" {
-				badFields[httpRequestBodyField] = fmt.Sprintf("Field can only be set if %q is set to the value 'POST'.", httpMethodField)
+			if b := attrs.GetHttpRequestBody(); b != nil && vault.Method(strings.ToUpper(attrs.GetHttpMethod().GetValue())) == vault.MethodGet {
+				badFields[httpRequestBodyField] = fmt.Sprintf("Field can only be set if %q is set to the value 'POST', 'PUT', or 'PATCH'.", httpMethodField)
 			}
 		}
 		return badFields
@@ -1,6 +1,7 @@
 package credentiallibraries
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -45,7 +46,7 @@ func TestList(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prjNoLibs := iam.TestScopes(t, iamRepo)
@@ -154,7 +155,7 @@ func TestCreate(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prj := iam.TestScopes(t, iamRepo)
@@ -301,7 +302,7 @@ func TestCreate(t *testing.T) {
 				Attributes: func() *structpb.Struct {
 					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialLibraryAttributes{
 						Path:       wrapperspb.String("something"),
-						HttpMethod: wrapperspb.String("PATCH"),
+						HttpMethod: wrapperspb.String("DELETE"),
 					})
 					require.NoError(t, err)
 					return attrs
@@ -364,6 +365,44 @@ func TestCreate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Using PATCH method",
+			req: &pbs.CreateCredentialLibraryRequest{Item: &pb.CredentialLibrary{
+				CredentialStoreId: store.GetPublicId(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialLibraryAttributes{
+						Path:            wrapperspb.String("something"),
+						HttpMethod:      wrapperspb.String("patch"),
+						HttpRequestBody: wrapperspb.String("foo"),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialLibraryPrefix + "_",
+			res: &pbs.CreateCredentialLibraryResponse{
+				Uri: fmt.Sprintf("credential-libraries/%s_", vault.CredentialLibraryPrefix),
+				Item: &pb.CredentialLibrary{
+					Id:                store.GetPublicId(),
+					CredentialStoreId: store.GetPublicId(),
+					CreatedTime:       store.GetCreateTime().GetTimestamp(),
+					UpdatedTime:       store.GetUpdateTime().GetTimestamp(),
+					Scope:             &scopepb.ScopeInfo{Id: prj.GetPublicId(), Type: prj.GetType(), ParentScopeId: prj.GetParentId()},
+					Version:           1,
+					Type:              vault.Subtype.String(),
+					Attributes: func() *structpb.Struct {
+						attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialLibraryAttributes{
+							Path:            wrapperspb.String("something"),
+							HttpMethod:      wrapperspb.String("PATCH"),
+							HttpRequestBody: wrapperspb.String("foo"),
+						})
+						require.NoError(t, err)
+						return attrs
+					}(),
+					AuthorizedActions: testAuthorizedActions,
+				},
+			},
+		},
 		{
 			name: "Create a valid vault CredentialLibrary",
 			req: &pbs.CreateCredentialLibraryRequest{Item: &pb.CredentialLibrary{
@@ -455,7 +494,7 @@ func TestGet(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prj := iam.TestScopes(t, iamRepo)
@@ -541,7 +580,7 @@ func TestDelete(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prj := iam.TestScopes(t, iamRepo)
@@ -600,7 +639,7 @@ func TestUpdate(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prj := iam.TestScopes(t, iamRepo)
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/pem"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/boundary/globals"
 	"github.com/hashicorp/boundary/internal/credential"
@@ -34,6 +35,14 @@ const (
 	caCertsField        = "attributes.ca_cert"
 	clientCertField     = "attributes.client_certificate"
 	clientCertKeyField  = "attributes.certificate_key"
+	authMethodField     = "attributes.auth_method"
+	appRoleRoleIdField  = "attributes.approle_role_id"
+	appRoleSecretField  = "attributes.approle_secret_id"
+	appRoleHmacField    = "attributes.approle_secret_id_hmac"
+	connectTimeoutField = "attributes.connect_timeout_seconds"
+	requestTimeoutField = "attributes.request_timeout_seconds"
+	requestsPerSecField = "attributes.requests_per_second"
+	tlsMinVersionField  = "attributes.tls_min_version"
 )
 
 var (
@@ -62,7 +71,7 @@ var (
 
 func init() {
 	var err error
-	if maskManager, err = handlers.NewMaskManager(handlers.MaskDestination{&store.CredentialStore{}, &store.Token{}, &store.ClientCertificate{}},
+	if maskManager, err = handlers.NewMaskManager(handlers.MaskDestination{&store.CredentialStore{}, &store.Token{}, &store.ClientCertificate{}, &store.AppRole{}},
 		handlers.MaskSource{&pb.CredentialStore{}, &pb.VaultCredentialStoreAttributes{}}); err != nil {
 		panic(err)
 	}
@@ -258,6 +267,7 @@ func (s Service) CreateCredentialStore(ctx context.Context, req *pbs.CreateCrede
 	if err != nil {
 		return nil, err
 	}
+	item.Warnings = storeWarnings(cs)
 
 	return &pbs.CreateCredentialStoreResponse{
 		Item: item,
@@ -306,6 +316,7 @@ func (s Service) UpdateCredentialStore(ctx context.Context, req *pbs.UpdateCrede
 	if err != nil {
 		return nil, err
 	}
+	item.Warnings = storeWarnings(cs)
 
 	return &pbs.UpdateCredentialStoreResponse{Item: item}, nil
 }
@@ -357,7 +368,7 @@ func (s Service) getFromRepo(ctx context.Context, id string) (credential.Store,
 
 func (s Service) createInRepo(ctx context.Context, projId string, item *pb.CredentialStore) (credential.Store, error) {
 	const op = "credentialstores.(Service).createInRepo"
-	cs, err := toStorageVaultStore(projId, item)
+	cs, err := toStorageVaultStore(ctx, projId, item)
 	if err != nil {
 		return nil, errors.Wrap(ctx, err, op)
 	}
@@ -377,7 +388,7 @@ func (s Service) createInRepo(ctx context.Context, projId string, item *pb.Crede
 
 func (s Service) updateInRepo(ctx context.Context, projId, id string, mask []string, item *pb.CredentialStore) (credential.Store, error) {
 	const op = "credentialstores.(Service).updateInRepo"
-	cs, err := toStorageVaultStore(projId, item)
+	cs, err := toStorageVaultStore(ctx, projId, item)
 	if err != nil {
 		return nil, errors.Wrap(ctx, err, op)
 	}
@@ -461,6 +472,15 @@ func (s Service) authResult(ctx context.Context, id string, a action.Type) auth.
 	return auth.Verify(ctx, opts...)
 }
 
+// storeWarnings returns the non-fatal problems with the configuration of
+// the credential store in.
+func storeWarnings(in credential.Store) []string {
+	if vaultIn, ok := in.(*vault.CredentialStore); ok {
+		return vaultIn.Warnings()
+	}
+	return nil
+}
+
 func toProto(in credential.Store, opt ...handlers.Option) (*pb.CredentialStore, error) {
 	const op = "credentialstores.toProto"
 
@@ -526,6 +546,9 @@ func toProto(in credential.Store, opt ...handlers.Option) (*pb.CredentialStore,
 			if vaultIn.GetTlsSkipVerify() {
 				attrs.TlsSkipVerify = wrapperspb.Bool(vaultIn.GetTlsSkipVerify())
 			}
+			if vaultIn.GetTlsMinVersion() != "" {
+				attrs.TlsMinVersion = wrapperspb.String(vaultIn.GetTlsMinVersion())
+			}
 			if vaultIn.Token() != nil {
 				attrs.TokenHmac = base64.RawURLEncoding.EncodeToString(vaultIn.Token().GetTokenHmac())
 			}
@@ -535,6 +558,20 @@ func toProto(in credential.Store, opt ...handlers.Option) (*pb.CredentialStore,
 				}
 				attrs.ClientCertificateKeyHmac = base64.RawURLEncoding.EncodeToString(cc.GetCertificateKeyHmac())
 			}
+			attrs.AuthMethod = wrapperspb.String(string(vaultIn.AuthMethod()))
+			if ar := vaultIn.AppRole(); ar != nil {
+				attrs.ApproleRoleId = wrapperspb.String(ar.GetRoleId())
+				attrs.ApproleSecretIdHmac = base64.RawURLEncoding.EncodeToString(ar.GetSecretIdHmac())
+			}
+			if vaultIn.GetConnectTimeoutSeconds() != 0 {
+				attrs.ConnectTimeoutSeconds = wrapperspb.UInt32(vaultIn.GetConnectTimeoutSeconds())
+			}
+			if vaultIn.GetRequestTimeoutSeconds() != 0 {
+				attrs.RequestTimeoutSeconds = wrapperspb.UInt32(vaultIn.GetRequestTimeoutSeconds())
+			}
+			if vaultIn.GetRequestsPerSecond() != 0 {
+				attrs.RequestsPerSecond = wrapperspb.UInt32(vaultIn.GetRequestsPerSecond())
+			}
 
 			var err error
 			if out.Attributes, err = handlers.ProtoToStruct(attrs); err != nil {
@@ -545,7 +582,7 @@ func toProto(in credential.Store, opt ...handlers.Option) (*pb.CredentialStore,
 	return &out, nil
 }
 
-func toStorageVaultStore(scopeId string, in *pb.CredentialStore) (out *vault.CredentialStore, err error) {
+func toStorageVaultStore(ctx context.Context, scopeId string, in *pb.CredentialStore) (out *vault.CredentialStore, err error) {
 	const op = "credentialstores.toStorageVaultStore"
 	var opts []vault.Option
 	if in.GetName() != nil {
@@ -565,9 +602,21 @@ func toStorageVaultStore(scopeId string, in *pb.CredentialStore) (out *vault.Cre
 	if attrs.GetTlsSkipVerify().GetValue() {
 		opts = append(opts, vault.WithTlsSkipVerify(attrs.GetTlsSkipVerify().GetValue()))
 	}
+	if attrs.GetTlsMinVersion() != nil {
+		opts = append(opts, vault.WithTlsMinVersion(attrs.GetTlsMinVersion().GetValue()))
+	}
 	if attrs.GetNamespace().GetValue() != "" {
 		opts = append(opts, vault.WithNamespace(attrs.GetNamespace().GetValue()))
 	}
+	if attrs.GetConnectTimeoutSeconds() != nil {
+		opts = append(opts, vault.WithConnectTimeout(time.Duration(attrs.GetConnectTimeoutSeconds().GetValue())*time.Second))
+	}
+	if attrs.GetRequestTimeoutSeconds() != nil {
+		opts = append(opts, vault.WithRequestTimeout(time.Duration(attrs.GetRequestTimeoutSeconds().GetValue())*time.Second))
+	}
+	if attrs.GetRequestsPerSecond() != nil {
+		opts = append(opts, vault.WithRequestsPerSecond(attrs.GetRequestsPerSecond().GetValue()))
+	}
 
 	// TODO (ICU-1478 and ICU-1479): Update the vault's interface around ca cert to match oidc's,
 	//  accepting x509.Certificate instead of []byte
@@ -593,8 +642,15 @@ func toStorageVaultStore(scopeId string, in *pb.CredentialStore) (out *vault.Cre
 		}
 		opts = append(opts, vault.WithClientCert(cc))
 	}
+	if attrs.GetApproleRoleId().GetValue() != "" || attrs.GetApproleSecretId().GetValue() != "" {
+		ar, err := vault.NewAppRole(ctx, attrs.GetApproleRoleId().GetValue(), vault.SecretIdSecret(attrs.GetApproleSecretId().GetValue()))
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, op)
+		}
+		opts = append(opts, vault.WithAppRole(ar))
+	}
 
-	cs, err := vault.NewCredentialStore(scopeId, attrs.GetAddress().GetValue(), []byte(attrs.GetToken().GetValue()), opts...)
+	cs, err := vault.NewCredentialStore(ctx, scopeId, attrs.GetAddress().GetValue(), []byte(attrs.GetToken().GetValue()), opts...)
 	if err != nil {
 		return nil, errors.WrapDeprecated(err, op, errors.WithMsg("unable to build credential store for creation"))
 	}
@@ -626,12 +682,38 @@ func validateCreateRequest(req *pbs.CreateCredentialStoreRequest) error {
 			if attrs.GetAddress().GetValue() == "" {
 				badFields[addressField] = "Field required for creating a vault credential store."
 			}
-			if attrs.GetToken().GetValue() == "" {
-				badFields[vaultTokenField] = "Field required for creating a vault credential store."
+			switch vault.AuthMethod(attrs.GetAuthMethod().GetValue()) {
+			case "", vault.TokenAuthMethod:
+				if attrs.GetToken().GetValue() == "" {
+					badFields[vaultTokenField] = "Field required for creating a vault credential store."
+				}
+				if attrs.GetApproleRoleId() != nil {
+					badFields[appRoleRoleIdField] = "Field can only be set when using the approle auth method."
+				}
+				if attrs.GetApproleSecretId() != nil {
+					badFields[appRoleSecretField] = "Field can only be set when using the approle auth method."
+				}
+			case vault.AppRoleAuthMethod:
+				if attrs.GetToken() != nil {
+					badFields[vaultTokenField] = "Field cannot be set when using the approle auth method."
+				}
+				if attrs.GetApproleRoleId().GetValue() == "" {
+					badFields[appRoleRoleIdField] = "Field required when using the approle auth method."
+				}
+				if attrs.GetApproleSecretId().GetValue() == "" {
+					badFields[appRoleSecretField] = "Field required when using the approle auth method."
+				}
+			default:
+				badFields[authMethodField] = fmt.Sprintf("Unknown auth method, must be %q or %q.", vault.TokenAuthMethod, vault.AppRoleAuthMethod)
 			}
 			if attrs.GetTokenHmac() != "" {
 				badFields[vaultTokenHmacField] = "This is a read only field."
 			}
+			if attrs.GetApproleSecretIdHmac() != "" {
+				badFields[appRoleHmacField] = "This is a read only field."
+			}
+			validateLimits(attrs, badFields)
+			validateTlsMinVersion(attrs, badFields)
 
 			// TODO(ICU-1478 and ICU-1479): Validate client and CA certificate payloads
 			_, err := decodePemBlocks(attrs.GetCaCert().GetValue())
@@ -680,6 +762,26 @@ func validateUpdateRequest(req *pbs.UpdateCredentialStoreRequest) error {
 			if attrs.GetTokenHmac() != "" {
 				badFields[vaultTokenHmacField] = "This is a read only field."
 			}
+			if attrs.GetAuthMethod() != nil {
+				badFields[authMethodField] = "Field cannot be updated; it follows from setting either the token or the approle fields."
+			}
+			// The AppRole RoleID and SecretID are set or unset together.
+			paths := req.GetUpdateMask().GetPaths()
+			roleIdInMask := handlers.MaskContains(paths, appRoleRoleIdField)
+			secretIdInMask := handlers.MaskContains(paths, appRoleSecretField)
+			switch {
+			case roleIdInMask && !secretIdInMask:
+				badFields[appRoleSecretField] = "Field must be updated along with the approle role id."
+			case secretIdInMask && !roleIdInMask:
+				badFields[appRoleRoleIdField] = "Field must be updated along with the approle secret id."
+			case roleIdInMask && (attrs.GetApproleRoleId().GetValue() == "") != (attrs.GetApproleSecretId().GetValue() == ""):
+				badFields[appRoleRoleIdField] = "The approle role id and secret id must be set or unset together."
+			}
+			if attrs.GetApproleSecretIdHmac() != "" {
+				badFields[appRoleHmacField] = "This is a read only field."
+			}
+			validateLimits(attrs, badFields)
+			validateTlsMinVersion(attrs, badFields)
 
 			// TODO(ICU-1478 and ICU-1479): Validate client and CA certificate payloads
 			_, err := decodePemBlocks(attrs.GetCaCert().GetValue())
@@ -696,6 +798,35 @@ func validateUpdateRequest(req *pbs.UpdateCredentialStoreRequest) error {
 	}, vault.CredentialStorePrefix)
 }
 
+// validateLimits adds an entry to badFields for each timeout or rate
+// limit in attrs which is set to zero. A limit is unset by setting it to
+// null.
+func validateLimits(attrs *pb.VaultCredentialStoreAttributes, badFields map[string]string) {
+	if attrs.GetConnectTimeoutSeconds() != nil && attrs.GetConnectTimeoutSeconds().GetValue() == 0 {
+		badFields[connectTimeoutField] = "Must be a positive number of seconds."
+	}
+	if attrs.GetRequestTimeoutSeconds() != nil && attrs.GetRequestTimeoutSeconds().GetValue() == 0 {
+		badFields[requestTimeoutField] = "Must be a positive number of seconds."
+	}
+	if attrs.GetRequestsPerSecond() != nil && attrs.GetRequestsPerSecond().GetValue() == 0 {
+		badFields[requestsPerSecField] = "Must be a positive number of requests."
+	}
+}
+
+// validateTlsMinVersion adds an entry to badFields if the TLS min version
+// in attrs is set to an unsupported version. The TLS min version is unset
+// by setting it to null.
+func validateTlsMinVersion(attrs *pb.VaultCredentialStoreAttributes, badFields map[string]string) {
+	if attrs.GetTlsMinVersion() == nil {
+		return
+	}
+	switch attrs.GetTlsMinVersion().GetValue() {
+	case vault.TlsVersion12, vault.TlsVersion13:
+	default:
+		badFields[tlsMinVersionField] = fmt.Sprintf("Must be either %q or %q.", vault.TlsVersion12, vault.TlsVersion13)
+	}
+}
+
 func validateDeleteRequest(req *pbs.DeleteCredentialStoreRequest) error {
 	return handlers.ValidateDeleteRequest(handlers.NoopValidatorFn, req, vault.CredentialStorePrefix)
 }
@@ -1,6 +1,7 @@
 package credentialstores
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -10,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/boundary/internal/authtoken"
 	"github.com/hashicorp/boundary/internal/credential/vault"
 	"github.com/hashicorp/boundary/internal/db"
 	"github.com/hashicorp/boundary/internal/errors"
@@ -18,6 +20,7 @@ import (
 	"github.com/hashicorp/boundary/internal/iam"
 	"github.com/hashicorp/boundary/internal/kms"
 	"github.com/hashicorp/boundary/internal/scheduler"
+	"github.com/hashicorp/boundary/internal/servers"
 	"github.com/hashicorp/boundary/internal/servers/controller/auth"
 	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
 	"github.com/hashicorp/boundary/internal/types/scope"
@@ -58,7 +61,7 @@ func TestList(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prjNoStores := iam.TestScopes(t, iamRepo)
@@ -79,6 +82,7 @@ func TestList(t *testing.T) {
 			Attributes: func() *structpb.Struct {
 				attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
 					Address:                  wrapperspb.String(s.GetVaultAddress()),
+					AuthMethod:               wrapperspb.String(string(vault.TokenAuthMethod)),
 					TokenHmac:                base64.RawURLEncoding.EncodeToString(s.Token().GetTokenHmac()),
 					ClientCertificate:        wrapperspb.String(string(s.ClientCertificate().GetCertificate())),
 					ClientCertificateKeyHmac: base64.RawURLEncoding.EncodeToString(s.ClientCertificate().GetCertificateKeyHmac()),
@@ -150,6 +154,90 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestList_Recursive(t *testing.T) {
+	conn, _ := db.TestSetup(t, "postgres")
+	wrapper := db.TestWrapper(t)
+	kms := kms.TestKms(t, conn, wrapper)
+	sche := scheduler.TestScheduler(t, conn, wrapper)
+	rw := db.New(conn)
+
+	iamRepo := iam.TestRepo(t, conn, wrapper)
+	iamRepoFn := func() (*iam.Repository, error) {
+		return iamRepo, nil
+	}
+	repoFn := func() (*vault.Repository, error) {
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
+	}
+	tokenRepoFn := func() (*authtoken.Repository, error) {
+		return authtoken.NewRepository(rw, rw, kms)
+	}
+	serversRepoFn := func() (*servers.Repository, error) {
+		return servers.NewRepository(rw, rw, kms)
+	}
+
+	// a scope tree of an org with two projects, the user is only granted
+	// access to the credential stores in the first project
+	org, allowedPrj := iam.TestScopes(t, iamRepo)
+	deniedPrj, err := iam.NewProject(org.GetPublicId())
+	require.NoError(t, err)
+	deniedPrj, err = iamRepo.CreateScope(context.Background(), deniedPrj, "")
+	require.NoError(t, err)
+
+	var allowedIds, allIds []string
+	for _, s := range vault.TestCredentialStores(t, conn, wrapper, allowedPrj.GetPublicId(), 2) {
+		allowedIds = append(allowedIds, s.GetPublicId())
+		allIds = append(allIds, s.GetPublicId())
+	}
+	for _, s := range vault.TestCredentialStores(t, conn, wrapper, deniedPrj.GetPublicId(), 2) {
+		allIds = append(allIds, s.GetPublicId())
+	}
+
+	at := authtoken.TestAuthToken(t, conn, kms, org.GetPublicId())
+	r := iam.TestRole(t, conn, allowedPrj.GetPublicId())
+	_ = iam.TestUserRole(t, conn, r.GetPublicId(), at.GetIamUserId())
+	_ = iam.TestRoleGrant(t, conn, r.GetPublicId(), "id=*;type=credential-store;actions=list,read")
+
+	s, err := NewService(repoFn, iamRepoFn)
+	require.NoError(t, err)
+
+	ids := func(items []*pb.CredentialStore) []string {
+		var ret []string
+		for _, item := range items {
+			ret = append(ret, item.GetId())
+		}
+		return ret
+	}
+
+	t.Run("all-scopes", func(t *testing.T) {
+		req := &pbs.ListCredentialStoresRequest{ScopeId: org.GetPublicId(), Recursive: true}
+		got, err := s.ListCredentialStores(auth.DisabledAuthTestContext(iamRepoFn, org.GetPublicId()), req)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, allIds, ids(got.GetItems()))
+	})
+
+	t.Run("skip-denied-scopes", func(t *testing.T) {
+		requestInfo := auth.RequestInfo{
+			TokenFormat: auth.AuthTokenTypeBearer,
+			PublicId:    at.GetPublicId(),
+			Token:       at.GetToken(),
+		}
+		ctx := auth.NewVerifierContext(context.Background(), iamRepoFn, tokenRepoFn, serversRepoFn, kms, requestInfo)
+
+		// the user is not granted list in the org, a recursive list skips
+		// the org and the denied project instead of failing
+		req := &pbs.ListCredentialStoresRequest{ScopeId: org.GetPublicId(), Recursive: true}
+		got, err := s.ListCredentialStores(ctx, req)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, allowedIds, ids(got.GetItems()))
+
+		// a non-recursive list of the denied project fails
+		req = &pbs.ListCredentialStoresRequest{ScopeId: deniedPrj.GetPublicId()}
+		_, err = s.ListCredentialStores(ctx, req)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, handlers.ForbiddenError()), "got error %v, wanted forbidden", err)
+	})
+}
+
 func TestCreate(t *testing.T) {
 	conn, _ := db.TestSetup(t, "postgres")
 	wrapper := db.TestWrapper(t)
@@ -162,7 +250,7 @@ func TestCreate(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prj := iam.TestScopes(t, iamRepo)
@@ -231,6 +319,161 @@ func TestCreate(t *testing.T) {
 			idPrefix: vault.CredentialStorePrefix + "_",
 			wantErr:  true,
 		},
+		{
+			name: "Approle without secret id",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:       wrapperspb.String(v.Addr),
+						CaCert:        wrapperspb.String(string(v.CaCert)),
+						AuthMethod:    wrapperspb.String(string(vault.AppRoleAuthMethod)),
+						ApproleRoleId: wrapperspb.String("role-id"),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			err:      handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Approle with token",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:         wrapperspb.String(v.Addr),
+						CaCert:          wrapperspb.String(string(v.CaCert)),
+						Token:           wrapperspb.String(newToken()),
+						AuthMethod:      wrapperspb.String(string(vault.AppRoleAuthMethod)),
+						ApproleRoleId:   wrapperspb.String("role-id"),
+						ApproleSecretId: wrapperspb.String("secret-id"),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			err:      handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Approle fields with token auth method",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:         wrapperspb.String(v.Addr),
+						CaCert:          wrapperspb.String(string(v.CaCert)),
+						Token:           wrapperspb.String(newToken()),
+						ApproleRoleId:   wrapperspb.String("role-id"),
+						ApproleSecretId: wrapperspb.String("secret-id"),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			err:      handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Unknown auth method",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:    wrapperspb.String(v.Addr),
+						CaCert:     wrapperspb.String(string(v.CaCert)),
+						Token:      wrapperspb.String(newToken()),
+						AuthMethod: wrapperspb.String("userpass"),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			err:      handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Zero connect timeout",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:               wrapperspb.String(v.Addr),
+						CaCert:                wrapperspb.String(string(v.CaCert)),
+						Token:                 wrapperspb.String(newToken()),
+						ConnectTimeoutSeconds: wrapperspb.UInt32(0),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			err:      handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Zero request timeout",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:               wrapperspb.String(v.Addr),
+						CaCert:                wrapperspb.String(string(v.CaCert)),
+						Token:                 wrapperspb.String(newToken()),
+						RequestTimeoutSeconds: wrapperspb.UInt32(0),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			err:      handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Zero requests per second",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:           wrapperspb.String(v.Addr),
+						CaCert:            wrapperspb.String(string(v.CaCert)),
+						Token:             wrapperspb.String(newToken()),
+						RequestsPerSecond: wrapperspb.UInt32(0),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			err:      handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
+		{
+			name: "Unsupported tls min version",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:       wrapperspb.String(v.Addr),
+						CaCert:        wrapperspb.String(string(v.CaCert)),
+						Token:         wrapperspb.String(newToken()),
+						TlsMinVersion: wrapperspb.String("tls10"),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			err:      handlers.ApiErrorWithCode(codes.InvalidArgument),
+		},
 		{
 			name: "Define only client cert",
 			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
@@ -455,6 +698,7 @@ func TestCreate(t *testing.T) {
 						attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
 							CaCert:                   wrapperspb.String(string(v.CaCert)),
 							Address:                  wrapperspb.String(v.Addr),
+							AuthMethod:               wrapperspb.String(string(vault.TokenAuthMethod)),
 							TokenHmac:                "<hmac>",
 							ClientCertificate:        wrapperspb.String(string(v.ClientCert)),
 							ClientCertificateKeyHmac: "<hmac>",
@@ -500,6 +744,49 @@ func TestCreate(t *testing.T) {
 						attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
 							CaCert:                   wrapperspb.String(string(v.CaCert)),
 							Address:                  wrapperspb.String(v.Addr),
+							AuthMethod:               wrapperspb.String(string(vault.TokenAuthMethod)),
+							TokenHmac:                "<hmac>",
+							ClientCertificate:        wrapperspb.String(string(v.ClientCert)),
+							ClientCertificateKeyHmac: "<hmac>",
+						})
+						require.NoError(t, err)
+						return attrs
+					}(),
+					AuthorizedActions:           testAuthorizedActions,
+					AuthorizedCollectionActions: testAuthorizedCollectionActions,
+				},
+			},
+		},
+		{
+			name: "Create a vault CredentialStore with TLS verification disabled returns warnings",
+			req: &pbs.CreateCredentialStoreRequest{Item: &pb.CredentialStore{
+				ScopeId: prj.GetPublicId(),
+				Type:    vault.Subtype.String(),
+				Attributes: func() *structpb.Struct {
+					attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+						Address:              wrapperspb.String(v.Addr),
+						Token:                wrapperspb.String(newToken()),
+						TlsSkipVerify:        wrapperspb.Bool(true),
+						ClientCertificate:    wrapperspb.String(string(v.ClientCert)),
+						ClientCertificateKey: wrapperspb.String(string(v.ClientKey)),
+					})
+					require.NoError(t, err)
+					return attrs
+				}(),
+			}},
+			idPrefix: vault.CredentialStorePrefix + "_",
+			res: &pbs.CreateCredentialStoreResponse{
+				Uri: fmt.Sprintf("credential-stores/%s_", vault.CredentialStorePrefix),
+				Item: &pb.CredentialStore{
+					ScopeId: prj.GetPublicId(),
+					Scope:   &scopepb.ScopeInfo{Id: prj.GetPublicId(), Type: prj.GetType(), ParentScopeId: prj.GetParentId()},
+					Version: 1,
+					Type:    vault.Subtype.String(),
+					Attributes: func() *structpb.Struct {
+						attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
+							Address:                  wrapperspb.String(v.Addr),
+							TlsSkipVerify:            wrapperspb.Bool(true),
+							AuthMethod:               wrapperspb.String(string(vault.TokenAuthMethod)),
 							TokenHmac:                "<hmac>",
 							ClientCertificate:        wrapperspb.String(string(v.ClientCert)),
 							ClientCertificateKeyHmac: "<hmac>",
@@ -509,6 +796,10 @@ func TestCreate(t *testing.T) {
 					}(),
 					AuthorizedActions:           testAuthorizedActions,
 					AuthorizedCollectionActions: testAuthorizedCollectionActions,
+					Warnings: []string{
+						"TLS verification disabled",
+						"no CA certificate provided, the Vault server's certificate is verified with the system's trusted CA certificates",
+					},
 				},
 			},
 		},
@@ -573,7 +864,7 @@ func TestGet(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prj := iam.TestScopes(t, iamRepo)
@@ -605,6 +896,7 @@ func TestGet(t *testing.T) {
 					Attributes: func() *structpb.Struct {
 						attrs, err := handlers.ProtoToStruct(&pb.VaultCredentialStoreAttributes{
 							Address:                  wrapperspb.String(store.GetVaultAddress()),
+							AuthMethod:               wrapperspb.String(string(vault.TokenAuthMethod)),
 							TokenHmac:                base64.RawURLEncoding.EncodeToString(store.Token().GetTokenHmac()),
 							ClientCertificate:        wrapperspb.String(string(store.ClientCertificate().GetCertificate())),
 							ClientCertificateKeyHmac: base64.RawURLEncoding.EncodeToString(store.ClientCertificate().GetCertificateKeyHmac()),
@@ -661,7 +953,7 @@ func TestDelete(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prj := iam.TestScopes(t, iamRepo)
@@ -719,7 +1011,7 @@ func TestUpdate(t *testing.T) {
 		return iamRepo, nil
 	}
 	repoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 
 	_, prj := iam.TestScopes(t, iamRepo)
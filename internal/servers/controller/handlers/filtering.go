@@ -3,29 +3,21 @@ package handlers
 import (
 	"github.com/hashicorp/boundary/internal/errors"
 	"github.com/hashicorp/boundary/internal/filter"
-	"github.com/hashicorp/go-bexpr"
 )
 
-// filterItem captures all the different namespaces that can be used when
-// filtering an item.
-type filterItem struct {
-	Item interface{} `json:"item"`
-}
-
+// Filter matches the items of a list request against the request's filter
+// expression.
 type Filter struct {
-	eval *bexpr.Evaluator
+	eval *filter.Evaluator
 }
 
 // NewFilter returns a Filter which can be evluated against.  An empty string paramter indicates
 // all items passed to it should succeed.
 func NewFilter(f string) (*Filter, error) {
 	const op = "handlers.NewFilter"
-	if f == "" {
-		return &Filter{}, nil
-	}
-	e, err := bexpr.CreateEvaluator(f, bexpr.WithTagName("json"), bexpr.WithHookFn(filter.WellKnownTypeFilterHook))
+	e, err := filter.NewEvaluator(f)
 	if err != nil {
-		return nil, errors.WrapDeprecated(err, op, errors.WithMsg("couldn't build filter"), errors.WithCode(errors.InvalidParameter))
+		return nil, errors.WrapDeprecated(err, op, errors.WithCode(errors.InvalidParameter))
 	}
 	return &Filter{eval: e}, nil
 }
@@ -35,13 +27,5 @@ func NewFilter(f string) (*Filter, error) {
 // TODO: Support more than just matching against the item being filtered.  Also allow matching against
 //   values in the request or the request context itself.
 func (f *Filter) Match(item interface{}) bool {
-	if f.eval == nil {
-		return true
-	}
-	m, err := f.eval.Evaluate(filterItem{Item: item})
-	// There isn't a clear way to differentiate between a JSON Pointer which doesn't represent
-	// the structure of the object being Matched and a JSON Pointer which references a field which
-	// is part of a sub structure that is nil in this item. Because of this, any filter which would
-	// result in an error using the underlying library is simply interpreted as not a match.
-	return err == nil && m
+	return f.eval.Match(item)
 }
@@ -20,9 +20,9 @@ func TestNewFilter_everythingMatchesEmpty(t *testing.T) {
 		map[string]string{},
 		[]string{"foo"},
 		[]int(nil),
-		(*filterItem)(nil),
+		(*struct{ Item interface{} })(nil),
 		struct{ foo string }{foo: "foo"},
-		filterItem{Item: struct{ foo string }{foo: "foo"}},
+		struct{ Item interface{} }{Item: struct{ foo string }{foo: "foo"}},
 	} {
 		assert.True(t, f.Match(v), "Trying to match %v", v)
 	}
@@ -596,10 +596,11 @@ func toProto(ctx context.Context, in *static.HostSet, hosts []*static.Host, opt
 	if outputFields.Has(globals.NameField) && in.GetName() != "" {
 		out.Name = &wrapperspb.StringValue{Value: in.GetName()}
 	}
-	if outputFields.Has(globals.CreatedTimeField) {
+	// a nil timestamp is treated as unset
+	if outputFields.Has(globals.CreatedTimeField) && in.GetCreateTime() != nil {
 		out.CreatedTime = in.GetCreateTime().GetTimestamp()
 	}
-	if outputFields.Has(globals.UpdatedTimeField) {
+	if outputFields.Has(globals.UpdatedTimeField) && in.GetUpdateTime() != nil {
 		out.UpdatedTime = in.GetUpdateTime().GetTimestamp()
 	}
 	if outputFields.Has(globals.VersionField) {
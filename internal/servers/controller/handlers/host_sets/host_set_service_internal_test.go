@@ -0,0 +1,40 @@
+package host_sets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/boundary/internal/host/static"
+	"github.com/hashicorp/boundary/internal/host/static/store"
+	"github.com/hashicorp/boundary/internal/perms"
+	"github.com/hashicorp/boundary/internal/servers/controller/handlers"
+	pb "github.com/hashicorp/boundary/sdk/pbs/controller/api/resources/hostsets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToProto_NilTimestamps(t *testing.T) {
+	t.Parallel()
+	assert, require := assert.New(t), require.New(t)
+
+	in := &static.HostSet{
+		HostSet: &store.HostSet{
+			PublicId:  "hsst_1234567890",
+			CatalogId: "hcst_1234567890",
+		},
+	}
+	require.Nil(in.GetCreateTime())
+	require.Nil(in.GetUpdateTime())
+
+	outputFields := perms.OutputFieldsMap(nil).AddFields([]string{"*"})
+	var got *pb.HostSet
+	var err error
+	require.NotPanics(func() {
+		got, err = toProto(context.Background(), in, nil, handlers.WithOutputFields(&outputFields))
+	})
+	require.NoError(err)
+	require.NotNil(got)
+	assert.Equal(in.GetPublicId(), got.GetId())
+	assert.Nil(got.GetCreatedTime())
+	assert.Nil(got.GetUpdatedTime())
+}
@@ -1110,6 +1110,7 @@ HostSourceIterationLoop:
 				Raw:     base64.StdEncoding.EncodeToString(jSecret),
 				Decoded: sSecret,
 			},
+			CredentialName: credentialName(c),
 		})
 	}
 
@@ -1148,6 +1149,15 @@ HostSourceIterationLoop:
 	return &pbs.AuthorizeSessionResponse{Item: ret}, nil
 }
 
+// credentialName returns the mapping name of c if it was mapped from part of
+// a library's response, otherwise it returns an empty string.
+func credentialName(c credential.Dynamic) string {
+	if m, ok := c.(credential.Mapped); ok {
+		return m.MappingName()
+	}
+	return ""
+}
+
 func (s Service) getFromRepo(ctx context.Context, id string) (target.Target, []target.HostSource, []target.CredentialSource, error) {
 	repo, err := s.repoFn()
 	if err != nil {
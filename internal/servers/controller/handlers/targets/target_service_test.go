@@ -85,7 +85,7 @@ func testService(t *testing.T, conn *db.DB, kms *kms.Kms, wrapper wrapping.Wrapp
 		return static.NewRepository(rw, rw, kms)
 	}
 	credentialRepoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 	return targets.NewService(kms, repoFn, iamRepoFn, serversRepoFn, sessionRepoFn, staticHostRepoFn, credentialRepoFn)
 }
@@ -2486,7 +2486,7 @@ func TestAuthorizeSession(t *testing.T) {
 		return static.NewRepository(rw, rw, kms)
 	}
 	credentialRepoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 	atRepoFn := func() (*authtoken.Repository, error) {
 		return authtoken.NewRepository(rw, rw, kms)
@@ -2656,7 +2656,7 @@ func TestAuthorizeSession_Errors(t *testing.T) {
 		return static.NewRepository(rw, rw, kms)
 	}
 	credentialRepoFn := func() (*vault.Repository, error) {
-		return vault.NewRepository(rw, rw, kms, sche)
+		return vault.NewRepository(context.Background(), rw, rw, kms, sche)
 	}
 	atRepoFn := func() (*authtoken.Repository, error) {
 		return authtoken.NewRepository(rw, rw, kms)